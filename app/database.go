@@ -0,0 +1,154 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/lock"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	_articleMemoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/memory"
+	_articleMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/mysql"
+	_articlePostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/postgres"
+	_articleSqliteRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/sqlite"
+	_authMemoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/memory"
+	_authMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/mysql"
+	_authPostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/postgres"
+	_authSqliteRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/sqlite"
+	_authorMemoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/memory"
+	_authorMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/mysql"
+	_authorPostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/postgres"
+	_authorSqliteRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/sqlite"
+	_categoryMemoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/memory"
+	_categoryMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/mysql"
+	_categoryPostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/postgres"
+	_categorySqliteRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/sqlite"
+	_userMemoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/memory"
+	_userMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/mysql"
+	_userPostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/postgres"
+	_userSqliteRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/sqlite"
+)
+
+// repositories holds the repository implementations of one database driver
+type repositories struct {
+	article      domain.ArticleRepository
+	revision     domain.ArticleRevisionRepository
+	author       domain.AuthorRepository
+	category     domain.CategoryRepository
+	user         domain.UserRepository
+	refreshToken domain.RefreshTokenRepository
+	transactor   domain.Transactor
+	locker       domain.Locker
+}
+
+// openDatabase connects to the database configured for driver. MySQL and
+// PostgreSQL read database.host, port, user, pass and name, SQLite the file at
+// database.path.
+func openDatabase(driver string) (*sql.DB, error) {
+	var dsn string
+	switch driver {
+	case "mysql":
+		connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
+			viper.GetString(`database.user`),
+			viper.GetString(`database.pass`),
+			viper.GetString(`database.host`),
+			viper.GetString(`database.port`),
+			viper.GetString(`database.name`))
+		val := url.Values{}
+		val.Add("parseTime", "1")
+		val.Add("loc", "Asia/Jakarta")
+		dsn = fmt.Sprintf("%s?%s", connection, val.Encode())
+	case "postgres":
+		connection := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(viper.GetString(`database.user`), viper.GetString(`database.pass`)),
+			Host:   fmt.Sprintf("%s:%s", viper.GetString(`database.host`), viper.GetString(`database.port`)),
+			Path:   viper.GetString(`database.name`),
+		}
+		val := url.Values{}
+		val.Add("sslmode", viper.GetString(`database.sslmode`))
+		connection.RawQuery = val.Encode()
+		dsn = connection.String()
+	case "sqlite":
+		val := url.Values{}
+		val.Add("_busy_timeout", "5000")
+		dsn = fmt.Sprintf("file:%s?%s", viper.GetString(`database.path`), val.Encode())
+		driver = "sqlite3"
+	default:
+		return nil, fmt.Errorf("unsupported database.driver %q", driver)
+	}
+
+	dbConn, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == "sqlite3" {
+		// SQLite allows a single writer, sharing one connection avoids
+		// "database is locked" errors under concurrent requests
+		dbConn.SetMaxOpenConns(1)
+	}
+	if err = dbConn.Ping(); err != nil {
+		return nil, err
+	}
+	return dbConn, nil
+}
+
+// newRepositories builds the repositories matching database.driver. The memory
+// driver keeps everything in the process and starts empty on every run.
+func newRepositories(driver string, dbConn *sql.DB) (repositories, error) {
+	switch driver {
+	case "memory":
+		db := memdb.New()
+		return repositories{
+			article:      _articleMemoryRepo.NewMemoryArticleRepository(db),
+			revision:     _articleMemoryRepo.NewMemoryArticleRevisionRepository(db),
+			author:       _authorMemoryRepo.NewMemoryAuthorRepository(db),
+			category:     _categoryMemoryRepo.NewMemoryCategoryRepository(db),
+			user:         _userMemoryRepo.NewMemoryUserRepository(db),
+			refreshToken: _authMemoryRepo.NewMemoryRefreshTokenRepository(db),
+			transactor:   db,
+			locker:       lock.NewLocalLocker(),
+		}, nil
+	case "mysql":
+		return repositories{
+			article:      _articleMysqlRepo.NewMysqlArticleRepository(dbConn),
+			revision:     _articleMysqlRepo.NewMysqlArticleRevisionRepository(dbConn),
+			author:       _authorMysqlRepo.NewMysqlAuthorRepository(dbConn),
+			category:     _categoryMysqlRepo.NewMysqlCategoryRepository(dbConn),
+			user:         _userMysqlRepo.NewMysqlUserRepository(dbConn),
+			refreshToken: _authMysqlRepo.NewMysqlRefreshTokenRepository(dbConn),
+			transactor:   transaction.NewSQLTransactor(dbConn),
+			locker:       lock.NewMysqlLocker(dbConn),
+		}, nil
+	case "postgres":
+		return repositories{
+			article:      _articlePostgresRepo.NewPostgresArticleRepository(dbConn),
+			revision:     _articlePostgresRepo.NewPostgresArticleRevisionRepository(dbConn),
+			author:       _authorPostgresRepo.NewPostgresAuthorRepository(dbConn),
+			category:     _categoryPostgresRepo.NewPostgresCategoryRepository(dbConn),
+			user:         _userPostgresRepo.NewPostgresUserRepository(dbConn),
+			refreshToken: _authPostgresRepo.NewPostgresRefreshTokenRepository(dbConn),
+			transactor:   transaction.NewSQLTransactor(dbConn),
+			locker:       lock.NewPostgresLocker(dbConn),
+		}, nil
+	case "sqlite":
+		return repositories{
+			article:      _articleSqliteRepo.NewSqliteArticleRepository(dbConn),
+			revision:     _articleSqliteRepo.NewSqliteArticleRevisionRepository(dbConn),
+			author:       _authorSqliteRepo.NewSqliteAuthorRepository(dbConn),
+			category:     _categorySqliteRepo.NewSqliteCategoryRepository(dbConn),
+			user:         _userSqliteRepo.NewSqliteUserRepository(dbConn),
+			refreshToken: _authSqliteRepo.NewSqliteRefreshTokenRepository(dbConn),
+			transactor:   transaction.NewSQLTransactor(dbConn),
+			locker:       lock.NewLocalLocker(),
+		}, nil
+	}
+	return repositories{}, fmt.Errorf("unsupported database.driver %q", driver)
+}
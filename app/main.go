@@ -1,23 +1,36 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"io/ioutil"
 	"log"
-	"net/url"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo"
 	"github.com/spf13/viper"
 
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+
 	_articleHttpDelivery "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/delivery/http"
 	_articleHttpDeliveryMiddleware "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/delivery/http/middleware"
-	_articleRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/mysql"
+	_articleScheduler "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/scheduler"
+	_articleBleveSearch "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/search/bleve"
 	_articleUcase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/usecase"
-	_authorRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/mysql"
+	_authHttpDelivery "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/delivery/http"
+	_authUcase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/usecase"
+	_authorHttpDelivery "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/delivery/http"
+	_authorUcase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/usecase"
+	_categoryHttpDelivery "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/delivery/http"
+	_categoryUcase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/usecase"
+	_policy "github.com/rachadiannovansyah/go-echo-clean-arch/modules/policy"
 	_userHttpDelivery "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/delivery/http"
-	_userRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/mysql"
 	_userUcase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/usecase"
 )
 
@@ -34,50 +47,219 @@ func init() {
 }
 
 func main() {
-	dbHost := viper.GetString(`database.host`)
-	dbPort := viper.GetString(`database.port`)
-	dbUser := viper.GetString(`database.user`)
-	dbPass := viper.GetString(`database.pass`)
-	dbName := viper.GetString(`database.name`)
-	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPass, dbHost, dbPort, dbName)
-	val := url.Values{}
-	val.Add("parseTime", "1")
-	val.Add("loc", "Asia/Jakarta")
-	dsn := fmt.Sprintf("%s?%s", connection, val.Encode())
-	dbConn, err := sql.Open(`mysql`, dsn)
+	driver := viper.GetString(`database.driver`)
+	var dbConn *sql.DB
+	if driver != "memory" {
+		var err error
+		dbConn, err = openDatabase(driver)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defer func() {
+			err := dbConn.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(dbConn, driver, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if dbConn != nil && viper.GetBool(`database.auto_migrate`) {
+		if err := runMigrate(dbConn, driver, []string{"up"}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := runSeed(dbConn, driver); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	// init repo
+	repos, err := newRepositories(driver, dbConn)
 	if err != nil {
 		log.Fatal(err)
 	}
-	err = dbConn.Ping()
+
+	if len(os.Args) > 1 && os.Args[1] == "grant" {
+		userUsecase := _userUcase.NewUserUsecase(repos.user, repos.author, _policy.NewRBACPolicy(), repos.transactor, cursorSealer(), timeoutContext)
+		if err := runGrant(driver, userUsecase, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	unlockSearchIndex, err := holdSearchIndex(repos.locker)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer unlockSearchIndex()
+	rebuild := len(os.Args) > 1 && os.Args[1] == "reindex"
+	searchIndex, fresh, err := openSearchIndex(driver, rebuild)
 	if err != nil {
 		log.Fatal(err)
 	}
-
 	defer func() {
-		err := dbConn.Close()
+		err := searchIndex.Close()
 		if err != nil {
 			log.Fatal(err)
 		}
 	}()
+	searcher := _articleBleveSearch.NewBleveArticleSearcher(searchIndex)
+	authorRepo := repos.author
+	articleRepo := repos.article
+	categoryRepo := repos.category
+	userRepo := repos.user
+
+	authUsecase := _authUcase.NewAuthUsecase(userRepo, repos.refreshToken, tokenConfig(), timeoutContext)
 
 	// use echo
 	e := echo.New()
-	middL := _articleHttpDeliveryMiddleware.InitMiddleware()
+	middL := _articleHttpDeliveryMiddleware.InitMiddleware(authUsecase)
 	e.Use(middL.CORS)
-
-	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
-
-	// init repo
-	authorRepo := _authorRepo.NewMysqlAuthorRepository(dbConn)
-	articleRepo := _articleRepo.NewMysqlArticleRepository(dbConn)
-	userRepo := _userRepo.NewMysqlUserRepository(dbConn)
+	e.Use(middL.Authenticate)
 
 	// init usecase
-	articleUsecase := _articleUcase.NewArticleUsecase(articleRepo, authorRepo, timeoutContext)
+	policy := _policy.NewRBACPolicy()
+	sealer := cursorSealer()
+	articleUsecase := _articleUcase.NewArticleUsecase(articleRepo, authorRepo, categoryRepo, policy, repos.transactor, sealer, searcher, repos.revision, timeoutContext)
 	_articleHttpDelivery.NewArticleHandler(e, articleUsecase)
-	userUsecase := _userUcase.NewUserUsecase(userRepo, timeoutContext)
+	authorUsecase := _authorUcase.NewAuthorUsecase(authorRepo, articleRepo, userRepo, policy, repos.transactor, sealer, timeoutContext)
+	_authorHttpDelivery.NewAuthorHandler(e, authorUsecase)
+	categoryUsecase := _categoryUcase.NewCategoryUsecase(categoryRepo, articleRepo, policy, searcher, timeoutContext)
+	_categoryHttpDelivery.NewCategoryHandler(e, categoryUsecase)
+	userUsecase := _userUcase.NewUserUsecase(userRepo, authorRepo, policy, repos.transactor, sealer, timeoutContext)
 	_userHttpDelivery.NewUserHandler(e, userUsecase)
+	if err := bootstrapAdmin(userUsecase); err != nil {
+		log.Fatal(err)
+	}
+	_authHttpDelivery.NewAuthHandler(e, authUsecase)
+
+	slugged, err := articleUsecase.FillSlugs(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if slugged > 0 {
+		log.Printf("gave slugs to %d articles", slugged)
+	}
+	if fresh {
+		n, err := articleUsecase.Reindex(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("indexed %d articles", n)
+	}
+	if rebuild {
+		return
+	}
+
+	schedulers := []*_articleScheduler.Scheduler{
+		_articleScheduler.NewScheduler("article-scheduler", _articleScheduler.PublishDue(articleUsecase),
+			repos.locker, _articleScheduler.SystemClock, positiveSeconds("scheduler.interval")),
+		_articleScheduler.NewScheduler("article-purge", _articleScheduler.PurgeTrash(articleUsecase, positiveSeconds("trash.retention")),
+			repos.locker, _articleScheduler.SystemClock, positiveSeconds("trash.purge_interval")),
+	}
+	for _, sched := range schedulers {
+		sched.Start()
+	}
+
+	go func() {
+		err := e.Start(viper.GetString("server.address"))
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	// let the requests and the scheduler runs in progress finish
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		log.Print(err)
+	}
+	for _, sched := range schedulers {
+		if err := sched.Stop(ctx); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+// shutdownTimeout bounds the wait for the work in progress on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// positiveSeconds is the duration of the given seconds setting, such as the
+// scheduler.interval between two runs of the scheduler publishing the due
+// articles, or the trash.retention of the trashed articles purged every
+// trash.purge_interval
+func positiveSeconds(key string) time.Duration {
+	d := time.Duration(viper.GetInt(key)) * time.Second
+	if d <= 0 {
+		log.Fatal(key + " must be positive")
+	}
+	return d
+}
+
+// cursorSealer signs the pagination cursors with pagination.secret, valid for
+// pagination.cursor_ttl seconds
+func cursorSealer() domain.CursorSealer {
+	secret := []byte(viper.GetString("pagination.secret"))
+	if len(secret) == 0 {
+		log.Fatal("pagination.secret must be set")
+	}
+	return pagination.NewSealer(secret, time.Duration(viper.GetInt("pagination.cursor_ttl"))*time.Second)
+}
+
+// tokenConfig builds the access token settings from the auth section of the
+// config. RS256 reads PEM encoded keys from auth.private_key and auth.public_key,
+// HS256 signs with auth.secret.
+func tokenConfig() _authUcase.TokenConfig {
+	config := _authUcase.TokenConfig{
+		AccessTTL:  time.Duration(viper.GetInt("auth.access_ttl")) * time.Second,
+		RefreshTTL: time.Duration(viper.GetInt("auth.refresh_ttl")) * time.Second,
+	}
+
+	switch alg := viper.GetString("auth.algorithm"); alg {
+	case "RS256":
+		privPEM, err := ioutil.ReadFile(viper.GetString("auth.private_key"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		pubPEM, err := ioutil.ReadFile(viper.GetString("auth.public_key"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		config.SigningMethod = jwt.SigningMethodRS256
+		if config.SignKey, err = jwt.ParseRSAPrivateKeyFromPEM(privPEM); err != nil {
+			log.Fatal(err)
+		}
+		if config.VerifyKey, err = jwt.ParseRSAPublicKeyFromPEM(pubPEM); err != nil {
+			log.Fatal(err)
+		}
+	case "HS256", "":
+		secret := []byte(viper.GetString("auth.secret"))
+		if len(secret) == 0 {
+			log.Fatal("auth.secret must be set for HS256")
+		}
+		config.SigningMethod = jwt.SigningMethodHS256
+		config.SignKey = secret
+		config.VerifyKey = secret
+	default:
+		log.Fatalf("unsupported auth.algorithm %q", alg)
+	}
 
-	log.Fatal(e.Start(viper.GetString("server.address")))
+	return config
 }
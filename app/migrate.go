@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/migration"
+)
+
+const migrateUsage = "usage: engine migrate up|down|status|redo|adopt|baseline <version>"
+
+func newMigrator(db *sql.DB, driver string) (*migration.Migrator, error) {
+	fsys, err := migration.Source(driver)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := migration.Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return migration.New(db, driver, migrations), nil
+}
+
+// runMigrate handles the migrate subcommand
+func runMigrate(db *sql.DB, driver string, args []string) error {
+	if len(args) == 0 || len(args) > 2 || (len(args) == 2) != (args[0] == "baseline") {
+		return fmt.Errorf(migrateUsage)
+	}
+	if db == nil {
+		return fmt.Errorf("database driver %q has nothing to migrate", driver)
+	}
+
+	m, err := newMigrator(db, driver)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			log.Printf("applied %d_%s", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			log.Println("database is up to date")
+		}
+		return err
+	case "down":
+		mig, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("rolled back %d_%s", mig.Version, mig.Name)
+	case "redo":
+		mig, err := m.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("redone %d_%s", mig.Version, mig.Name)
+	case "adopt":
+		mig, err := m.Adopt(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("adopted the dump as %d_%s", mig.Version, mig.Name)
+	case "baseline":
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf(migrateUsage)
+		}
+		recorded, err := m.Baseline(ctx, version)
+		for _, mig := range recorded {
+			log.Printf("recorded %d_%s as applied", mig.Version, mig.Name)
+		}
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+	return nil
+}
+
+// runSeed handles the seed subcommand
+func runSeed(db *sql.DB, driver string) error {
+	if db == nil {
+		return fmt.Errorf("database driver %q has nothing to seed", driver)
+	}
+	if err := migration.Seed(context.Background(), db, driver); err != nil {
+		return err
+	}
+	log.Println("loaded the demo data")
+	return nil
+}
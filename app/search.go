@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	blevesearch "github.com/blevesearch/bleve/v2"
+	"github.com/spf13/viper"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	_articleBleveSearch "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/search/bleve"
+)
+
+// openSearchIndex opens the article index at search.path, dropping it first
+// when rebuild is set. The memory driver keeps the index in memory as well.
+// fresh reports an index created empty, to be filled from the repository.
+func openSearchIndex(driver string, rebuild bool) (index blevesearch.Index, fresh bool, err error) {
+	path := viper.GetString("search.path")
+	if driver == "memory" {
+		path = ""
+	}
+	if rebuild && path != "" {
+		if err = os.RemoveAll(path); err != nil {
+			return nil, false, err
+		}
+	}
+	return _articleBleveSearch.OpenIndex(path)
+}
+
+// searchIndexLock names the lock held by the one replica serving the search
+// index for as long as it runs
+const searchIndexLock = "article-search-index"
+
+// holdSearchIndex takes the search index lock, failing when another replica
+// holds it. The index lives on the disk of the replica keeping it up to date,
+// so that a second replica would search an index missing the writes served by
+// the first: the service runs a single replica.
+func holdSearchIndex(locker domain.Locker) (unlock func(), err error) {
+	unlock, ok, err := locker.TryLock(context.Background(), searchIndexLock)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("another replica holds the search index, the service runs a single replica")
+	}
+	return unlock, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+const grantUsage = "usage: engine grant <email> author|editor|admin"
+
+// runGrant grants a role to the user with the given email, which is how a
+// deployment gets its first admin: `engine grant <email> admin`
+func runGrant(driver string, users domain.UserUsecase, args []string) error {
+	if driver == "memory" {
+		return fmt.Errorf("database driver %q forgets its users on exit, set admin.email in the config instead", driver)
+	}
+	if len(args) != 2 {
+		return fmt.Errorf(grantUsage)
+	}
+
+	err := users.Promote(context.Background(), args[0], domain.Role(args[1]))
+	switch err {
+	case errHandle.ErrNotFound:
+		return fmt.Errorf("no user has the email %s", args[0])
+	case errHandle.ErrBadParamInput:
+		return fmt.Errorf(grantUsage)
+	}
+	return err
+}
+
+// bootstrapAdmin makes an admin of the user with admin.email, registering it
+// with admin.username and admin.password first when there is no such user.
+// Nothing happens when admin.email is empty.
+func bootstrapAdmin(users domain.UserUsecase) error {
+	email := viper.GetString("admin.email")
+	if email == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	err := users.Promote(ctx, email, domain.RoleAdmin)
+	if err != errHandle.ErrNotFound {
+		return err
+	}
+
+	admin := domain.User{
+		Username: viper.GetString("admin.username"),
+		Email:    email,
+		Password: viper.GetString("admin.password"),
+	}
+	if admin.Username == "" || len(admin.Password) < 8 {
+		return fmt.Errorf("admin.username and an admin.password of at least 8 characters are needed to register %s", email)
+	}
+	if err = users.Store(ctx, &admin); err != nil {
+		return err
+	}
+	return users.Promote(ctx, email, domain.RoleAdmin)
+}
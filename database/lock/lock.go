@@ -0,0 +1,100 @@
+// Package lock hands out the domain.Locker of each database driver. MySQL and
+// PostgreSQL take advisory locks, which the database releases by itself should
+// the replica holding one lose its connection.
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+)
+
+// advisoryLocker takes the locks on a connection of their own, since advisory
+// locks belong to the session that took them
+type advisoryLocker struct {
+	db      *sql.DB
+	lock    string
+	release string
+	key     func(name string) interface{}
+}
+
+// NewMysqlLocker will create a domain.Locker taking MySQL GET_LOCK locks
+func NewMysqlLocker(db *sql.DB) domain.Locker {
+	return &advisoryLocker{
+		db:      db,
+		lock:    `SELECT COALESCE(GET_LOCK(?, 0), 0) = 1`,
+		release: `SELECT RELEASE_LOCK(?)`,
+		key:     func(name string) interface{} { return name },
+	}
+}
+
+// NewPostgresLocker will create a domain.Locker taking PostgreSQL session
+// advisory locks, keyed by the 64-bit FNV-1a hash of their name
+func NewPostgresLocker(db *sql.DB) domain.Locker {
+	return &advisoryLocker{
+		db:      db,
+		lock:    `SELECT pg_try_advisory_lock($1)`,
+		release: `SELECT pg_advisory_unlock($1)`,
+		key: func(name string) interface{} {
+			h := fnv.New64a()
+			h.Write([]byte(name))
+			return int64(h.Sum64())
+		},
+	}
+}
+
+func (l *advisoryLocker) TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := l.key(name)
+	if err = conn.QueryRowContext(ctx, l.lock, key).Scan(&ok); err != nil || !ok {
+		if errClose := conn.Close(); errClose != nil {
+			logrus.Error(errClose)
+		}
+		return nil, false, err
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), l.release, key); err != nil {
+			logrus.Error(err)
+		}
+		if err := conn.Close(); err != nil {
+			logrus.Error(err)
+		}
+	}, true, nil
+}
+
+type localLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+// NewLocalLocker will create a domain.Locker whose locks only hold within the
+// process, for the drivers serving a single replica
+func NewLocalLocker() domain.Locker {
+	return &localLocker{held: make(map[string]bool)}
+}
+
+func (l *localLocker) TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, name)
+	}, true, nil
+}
@@ -0,0 +1,79 @@
+package lock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/lock"
+)
+
+func TestMysqlLocker(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	locker := lock.NewMysqlLocker(db)
+
+	t.Run("taken", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COALESCE\\(GET_LOCK\\(\\?, 0\\), 0\\) = 1").WithArgs("job").
+			WillReturnRows(sqlmock.NewRows([]string{"ok"}).AddRow(1))
+		mock.ExpectExec("SELECT RELEASE_LOCK\\(\\?\\)").WithArgs("job").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		unlock, ok, err := locker.TryLock(context.TODO(), "job")
+		require.NoError(t, err)
+		require.True(t, ok)
+		unlock()
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("held-elsewhere", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COALESCE\\(GET_LOCK\\(\\?, 0\\), 0\\) = 1").WithArgs("job").
+			WillReturnRows(sqlmock.NewRows([]string{"ok"}).AddRow(0))
+
+		unlock, ok, err := locker.TryLock(context.TODO(), "job")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Nil(t, unlock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresLocker(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	locker := lock.NewPostgresLocker(db)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1\\)").WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(\\$1\\)").WithArgs(sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlock, ok, err := locker.TryLock(context.TODO(), "job")
+	require.NoError(t, err)
+	require.True(t, ok)
+	unlock()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLocalLocker(t *testing.T) {
+	locker := lock.NewLocalLocker()
+
+	unlock, ok, err := locker.TryLock(context.TODO(), "job")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, err = locker.TryLock(context.TODO(), "job")
+	assert.NoError(t, err)
+	assert.False(t, ok, "the lock is held")
+	other, ok, err := locker.TryLock(context.TODO(), "other")
+	assert.NoError(t, err)
+	assert.True(t, ok, "locks of other names are free")
+	other()
+
+	unlock()
+	unlock, ok, err = locker.TryLock(context.TODO(), "job")
+	assert.NoError(t, err)
+	assert.True(t, ok, "the lock was released")
+	unlock()
+}
@@ -0,0 +1,179 @@
+// Package memdb is an in-memory stand-in for the SQL schema, used by the
+// memory repositories for tests and demos run without any database server.
+package memdb
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+)
+
+// ArticleCategory is a row of the article_category table
+type ArticleCategory struct {
+	ArticleID  int64
+	CategoryID int64
+}
+
+// UserRole is a row of the user_role table
+type UserRole struct {
+	UserID int64
+	Role   domain.Role
+}
+
+// DB holds the rows of every table. Repositories sharing a DB see each other's
+// writes the way repositories sharing a *sql.DB do, so they must hold the lock
+// while reading or writing the tables.
+type DB struct {
+	sync.RWMutex
+	tx                sync.Mutex
+	Articles          map[int64]domain.Article
+	Authors           map[int64]domain.Author
+	Categories        map[int64]domain.Category
+	ArticleCategories map[ArticleCategory]struct{}
+	Users             map[int64]domain.User
+	UserRoles         map[UserRole]struct{}
+	RefreshTokens     map[int64]domain.RefreshToken
+	ArticleRevisions  map[int64]domain.ArticleRevision
+	ArticleSlugs      map[string]int64
+
+	sequences map[string]int64
+}
+
+// New will create an empty DB
+func New() *DB {
+	return &DB{
+		Articles:          map[int64]domain.Article{},
+		Authors:           map[int64]domain.Author{},
+		Categories:        map[int64]domain.Category{},
+		ArticleCategories: map[ArticleCategory]struct{}{},
+		Users:             map[int64]domain.User{},
+		UserRoles:         map[UserRole]struct{}{},
+		RefreshTokens:     map[int64]domain.RefreshToken{},
+		ArticleRevisions:  map[int64]domain.ArticleRevision{},
+		ArticleSlugs:      map[string]int64{},
+		sequences:         map[string]int64{},
+	}
+}
+
+// NextID returns the next auto increment value of table. The caller must hold
+// the write lock.
+func (db *DB) NextID(table string) int64 {
+	db.sequences[table]++
+	return db.sequences[table]
+}
+
+// Page sorts the ids in the scan order of keyset, key giving the position of
+// the row with the given id, and keeps the first keyset.Limit() of them past
+// its position, like the keyset query of the SQL repositories.
+func Page(ids []int64, key func(id int64) pagination.Key, keyset pagination.Keyset) []int64 {
+	sort.Slice(ids, func(i, j int) bool {
+		return keyset.Less(key(ids[i]), key(ids[j]))
+	})
+
+	res := make([]int64, 0)
+	for _, id := range ids {
+		if int64(len(res)) == keyset.Limit() {
+			break
+		}
+		if keyset.Admits(key(id)) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+type txKey struct{}
+
+// tables is a copy of the rows of every table
+type tables struct {
+	articles          map[int64]domain.Article
+	authors           map[int64]domain.Author
+	categories        map[int64]domain.Category
+	articleCategories map[ArticleCategory]struct{}
+	users             map[int64]domain.User
+	userRoles         map[UserRole]struct{}
+	refreshTokens     map[int64]domain.RefreshToken
+	articleRevisions  map[int64]domain.ArticleRevision
+	articleSlugs      map[string]int64
+}
+
+// WithinTx makes the DB a domain.Transactor. Transactions run one at a time and
+// a failing fn puts back the rows as they were when it started, auto increment
+// values aside like in MySQL. Writes made outside a transaction while it runs
+// are lost when it rolls back, which is fine for tests and demos only.
+func (db *DB) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	db.tx.Lock()
+	defer db.tx.Unlock()
+
+	snapshot := db.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{}, struct{}{})); err != nil {
+		db.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+func (db *DB) snapshot() (res tables) {
+	db.RLock()
+	defer db.RUnlock()
+
+	res.articles = make(map[int64]domain.Article, len(db.Articles))
+	for k, v := range db.Articles {
+		res.articles[k] = v
+	}
+	res.authors = make(map[int64]domain.Author, len(db.Authors))
+	for k, v := range db.Authors {
+		res.authors[k] = v
+	}
+	res.categories = make(map[int64]domain.Category, len(db.Categories))
+	for k, v := range db.Categories {
+		res.categories[k] = v
+	}
+	res.articleCategories = make(map[ArticleCategory]struct{}, len(db.ArticleCategories))
+	for k, v := range db.ArticleCategories {
+		res.articleCategories[k] = v
+	}
+	res.users = make(map[int64]domain.User, len(db.Users))
+	for k, v := range db.Users {
+		res.users[k] = v
+	}
+	res.userRoles = make(map[UserRole]struct{}, len(db.UserRoles))
+	for k, v := range db.UserRoles {
+		res.userRoles[k] = v
+	}
+	res.refreshTokens = make(map[int64]domain.RefreshToken, len(db.RefreshTokens))
+	for k, v := range db.RefreshTokens {
+		res.refreshTokens[k] = v
+	}
+	res.articleRevisions = make(map[int64]domain.ArticleRevision, len(db.ArticleRevisions))
+	for k, v := range db.ArticleRevisions {
+		res.articleRevisions[k] = v
+	}
+	res.articleSlugs = make(map[string]int64, len(db.ArticleSlugs))
+	for k, v := range db.ArticleSlugs {
+		res.articleSlugs[k] = v
+	}
+	return
+}
+
+func (db *DB) restore(t tables) {
+	db.Lock()
+	defer db.Unlock()
+
+	db.Articles = t.articles
+	db.Authors = t.authors
+	db.Categories = t.categories
+	db.ArticleCategories = t.articleCategories
+	db.Users = t.users
+	db.UserRoles = t.userRoles
+	db.RefreshTokens = t.refreshTokens
+	db.ArticleRevisions = t.articleRevisions
+	db.ArticleSlugs = t.articleSlugs
+}
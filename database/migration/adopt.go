@@ -0,0 +1,40 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+//go:embed adopt/*.sql
+var adoptions embed.FS
+
+// Adopt takes over a database created from the former article.sql dump,
+// which lacks article.version and the user, user_role and refresh_token
+// tables: it brings the schema up to the one of the first migration and
+// records that migration as applied, for Up to apply the rest. Only MySQL
+// ever had such a dump.
+func (m *Migrator) Adopt(ctx context.Context) (res Migration, err error) {
+	script, err := fs.ReadFile(adoptions, "adopt/"+m.driver+".sql")
+	if err != nil || len(m.migrations) == 0 {
+		return res, fmt.Errorf("database driver %q has no former dump to adopt", m.driver)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return
+	}
+	res = m.migrations[0]
+	if len(applied) > 0 {
+		return res, fmt.Errorf("the database is already migrated, migration %d_%s can't be adopted", res.Version, res.Name)
+	}
+
+	err = m.run(ctx, string(script), `INSERT INTO migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+		res.Version, res.Name, res.Checksum(), time.Now().UTC())
+	if err != nil {
+		return res, fmt.Errorf("adopting %d_%s: %v", res.Version, res.Name, err)
+	}
+	return
+}
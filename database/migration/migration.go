@@ -0,0 +1,359 @@
+// Package migration applies the versioned schema migrations embedded in the
+// binary and keeps track of them in the migrations table. The demo data is kept
+// apart, loaded by Seed only when asked for.
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// ErrNoApplied will throw when rolling back while no migration was applied
+var ErrNoApplied = errors.New("no migration has been applied")
+
+// Migration is a single schema change, read from the files
+// <version>_<name>.up.sql and <version>_<name>.down.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum identifies the content of the migration, so that editing a
+// migration after it has been applied is detected
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up + "\x00" + m.Down))
+	return hex.EncodeToString(sum[:])
+}
+
+// Status describes a migration and whether it was applied
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Source returns the embedded migrations written for the given database driver
+func Source(driver string) (fs.FS, error) {
+	sub, err := fs.Sub(files, driver)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = fs.Stat(sub, "."); err != nil {
+		return nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+	return sub, nil
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load reads the migrations of fsys sorted by version. Every version must come
+// with both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has files named %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	res := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		res = append(res, *m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
+
+// Migrator applies and rolls back migrations on a database
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+}
+
+// New will create a Migrator for the given migrations, sorted by version. The
+// driver picks the placeholders of the bookkeeping queries.
+func New(db *sql.DB, driver string, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		driver:     driver,
+		migrations: migrations,
+	}
+}
+
+// rebind replaces the ? placeholders of query with $n ones for PostgreSQL
+func (m *Migrator) rebind(query string) string {
+	if m.driver != "postgres" {
+		return query
+	}
+
+	var res strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			res.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		res.WriteRune(r)
+	}
+	return res.String()
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS migrations (
+  version BIGINT NOT NULL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  checksum CHAR(64) NOT NULL,
+  applied_at TIMESTAMP NOT NULL
+)`)
+	return err
+}
+
+// applied returns the applied migrations by version, failing when one of them
+// was changed since it was applied
+func (m *Migrator) applied(ctx context.Context) (map[int64]appliedMigration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, checksum, applied_at FROM migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		var a appliedMigration
+		if err = rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		res[version] = a
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		a, ok := res[migration.Version]
+		if ok && a.checksum != migration.Checksum() {
+			return nil, fmt.Errorf("migration %d_%s was modified after it was applied", migration.Version, migration.Name)
+		}
+	}
+	return res, nil
+}
+
+// Up applies every pending migration in version order and returns the ones applied
+func (m *Migrator) Up(ctx context.Context) (res []Migration, err error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err = m.run(ctx, migration.Up, `INSERT INTO migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			migration.Version, migration.Name, migration.Checksum(), time.Now().UTC())
+		if err != nil {
+			return res, fmt.Errorf("migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		res = append(res, migration)
+	}
+	return
+}
+
+// Down rolls back the latest applied migration and returns it
+func (m *Migrator) Down(ctx context.Context) (res Migration, err error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err = m.run(ctx, migration.Down, `DELETE FROM migrations WHERE version = ?`, migration.Version)
+		if err != nil {
+			return res, fmt.Errorf("migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		return migration, nil
+	}
+	return res, ErrNoApplied
+}
+
+// Redo rolls back the latest applied migration and applies it again
+func (m *Migrator) Redo(ctx context.Context) (res Migration, err error) {
+	res, err = m.Down(ctx)
+	if err != nil {
+		return
+	}
+
+	err = m.run(ctx, res.Up, `INSERT INTO migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+		res.Version, res.Name, res.Checksum(), time.Now().UTC())
+	if err != nil {
+		return res, fmt.Errorf("migration %d_%s: %v", res.Version, res.Name, err)
+	}
+	return
+}
+
+// Baseline records every migration up to version as applied without running
+// them, for a database whose schema was created otherwise, e.g. from a dump.
+// It returns the migrations recorded, leaving alone the ones already applied.
+func (m *Migrator) Baseline(ctx context.Context, version int64) (res []Migration, err error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := false
+	for _, migration := range m.migrations {
+		known = known || migration.Version == version
+	}
+	if !known {
+		return nil, fmt.Errorf("no migration has version %d", version)
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+		err = m.run(ctx, "", `INSERT INTO migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			migration.Version, migration.Name, migration.Checksum(), time.Now().UTC())
+		if err != nil {
+			return res, fmt.Errorf("migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		res = append(res, migration)
+	}
+	return
+}
+
+// Status lists every known migration and whether it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		a, ok := applied[migration.Version]
+		res = append(res, Status{Migration: migration, Applied: ok, AppliedAt: a.appliedAt})
+	}
+	return res, nil
+}
+
+// run executes the statements of script and the bookkeeping query in one
+// transaction. MySQL commits DDL statements implicitly, so a migration failing
+// halfway there has to be fixed by hand.
+func (m *Migrator) run(ctx context.Context, script string, query string, args ...interface{}) (err error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				logrus.Error(errRollback)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	for _, statement := range splitStatements(script) {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			return
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, m.rebind(query), args...)
+	return
+}
+
+// splitStatements splits script on the semicolons ending a line, so that
+// drivers not accepting several statements per call can run it
+func splitStatements(script string) (res []string) {
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			res = append(res, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		res = append(res, rest)
+	}
+	return
+}
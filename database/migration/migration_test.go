@@ -0,0 +1,317 @@
+package migration_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/migration"
+)
+
+var testFiles = fstest.MapFS{
+	"0001_create_note.up.sql": {Data: []byte(`-- notes written by the users
+CREATE TABLE note (
+  id INTEGER PRIMARY KEY,
+  body TEXT NOT NULL
+);
+`)},
+	"0001_create_note.down.sql": {Data: []byte("DROP TABLE note;\n")},
+	"0002_seed_note.up.sql": {Data: []byte(`INSERT INTO note (id, body) VALUES (1, 'first; with a semicolon');
+INSERT INTO note (id, body) VALUES (2, 'second');
+`)},
+	"0002_seed_note.down.sql": {Data: []byte("DELETE FROM note;\n")},
+	"README.md":               {Data: []byte("not a migration")},
+}
+
+func openDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func countNotes(t *testing.T, db *sql.DB) (count int, err error) {
+	err = db.QueryRow("SELECT COUNT(*) FROM note").Scan(&count)
+	return
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		migrations, err := migration.Load(testFiles)
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+		assert.Equal(t, int64(1), migrations[0].Version)
+		assert.Equal(t, "create_note", migrations[0].Name)
+		assert.Equal(t, int64(2), migrations[1].Version)
+	})
+
+	t.Run("missing-down", func(t *testing.T) {
+		_, err := migration.Load(fstest.MapFS{
+			"0001_create_note.up.sql": {Data: []byte("CREATE TABLE note (id INTEGER);")},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		fsys, err := migration.Source("mysql")
+		require.NoError(t, err)
+		migrations, err := migration.Load(fsys)
+		require.NoError(t, err)
+		assert.NotEmpty(t, migrations)
+
+		for _, driver := range []string{"postgres", "sqlite"} {
+			fsys, err = migration.Source(driver)
+			require.NoError(t, err)
+			driverMigrations, err := migration.Load(fsys)
+			require.NoError(t, err)
+			assert.Len(t, driverMigrations, len(migrations), driver)
+		}
+
+		_, err = migration.Source("oracle")
+		assert.Error(t, err)
+	})
+}
+
+func TestUp(t *testing.T) {
+	migrations, err := migration.Load(testFiles)
+	require.NoError(t, err)
+	db := openDB(t)
+	m := migration.New(db, "sqlite", migrations)
+
+	applied, err := m.Up(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+
+	count, err := countNotes(t, db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	var checksum string
+	err = db.QueryRow("SELECT checksum FROM migrations WHERE version = 1").Scan(&checksum)
+	require.NoError(t, err)
+	assert.Equal(t, migrations[0].Checksum(), checksum)
+
+	applied, err = m.Up(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestDownAndRedo(t *testing.T) {
+	migrations, err := migration.Load(testFiles)
+	require.NoError(t, err)
+	db := openDB(t)
+	m := migration.New(db, "sqlite", migrations)
+	_, err = m.Up(context.TODO())
+	require.NoError(t, err)
+
+	res, err := m.Redo(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.Version)
+	count, err := countNotes(t, db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	res, err = m.Down(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.Version)
+	count, err = countNotes(t, db)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	_, err = m.Down(context.TODO())
+	require.NoError(t, err)
+	_, err = countNotes(t, db)
+	assert.Error(t, err)
+
+	_, err = m.Down(context.TODO())
+	assert.Equal(t, migration.ErrNoApplied, err)
+}
+
+func TestStatus(t *testing.T) {
+	migrations, err := migration.Load(testFiles)
+	require.NoError(t, err)
+	db := openDB(t)
+	m := migration.New(db, "sqlite", migrations[:1])
+	_, err = m.Up(context.TODO())
+	require.NoError(t, err)
+
+	statuses, err := migration.New(db, "sqlite", migrations).Status(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[0].AppliedAt.IsZero())
+	assert.False(t, statuses[1].Applied)
+}
+
+func TestBaseline(t *testing.T) {
+	migrations, err := migration.Load(testFiles)
+	require.NoError(t, err)
+	db := openDB(t)
+	_, err = db.Exec("CREATE TABLE note (id INTEGER PRIMARY KEY, body TEXT NOT NULL)")
+	require.NoError(t, err)
+	m := migration.New(db, "sqlite", migrations)
+
+	_, err = m.Baseline(context.TODO(), 3)
+	assert.Error(t, err, "unknown version")
+
+	recorded, err := m.Baseline(context.TODO(), 1)
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, int64(1), recorded[0].Version)
+
+	recorded, err = m.Baseline(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, recorded)
+
+	applied, err := m.Up(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, applied, 1, "the existing table is not created again")
+	assert.Equal(t, int64(2), applied[0].Version)
+	count, err := countNotes(t, db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestAdopt(t *testing.T) {
+	fsys, err := migration.Source("mysql")
+	require.NoError(t, err)
+	migrations, err := migration.Load(fsys)
+	require.NoError(t, err)
+
+	t.Run("dump", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		m := migration.New(db, "mysql", migrations)
+
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version, checksum, applied_at FROM migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}))
+		mock.ExpectBegin()
+		mock.ExpectExec("ALTER TABLE `article` ADD COLUMN `version`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE `user` ").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE `user_role` ").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE `refresh_token` ").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO migrations").
+			WithArgs(int64(1), "create_tables", migrations[0].Checksum(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		adopted, err := m.Adopt(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), adopted.Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already-migrated", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		m := migration.New(db, "mysql", migrations)
+
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version, checksum, applied_at FROM migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).
+				AddRow(1, migrations[0].Checksum(), time.Now()))
+
+		_, err = m.Adopt(context.TODO())
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no-dump", func(t *testing.T) {
+		_, err := migration.New(openDB(t), "sqlite", migrations).Adopt(context.TODO())
+		assert.Error(t, err)
+	})
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	migrations, err := migration.Load(testFiles)
+	require.NoError(t, err)
+	db := openDB(t)
+	_, err = migration.New(db, "sqlite", migrations).Up(context.TODO())
+	require.NoError(t, err)
+
+	edited := append([]migration.Migration{}, migrations...)
+	edited[1].Up = "INSERT INTO note (id, body) VALUES (3, 'third');"
+
+	_, err = migration.New(db, "sqlite", edited).Up(context.TODO())
+	assert.Error(t, err)
+	_, err = migration.New(db, "sqlite", edited).Status(context.TODO())
+	assert.Error(t, err)
+}
+
+func TestFailedMigrationIsNotRecorded(t *testing.T) {
+	db := openDB(t)
+	m := migration.New(db, "sqlite", []migration.Migration{{
+		Version: 1,
+		Name:    "broken",
+		Up:      "CREATE TABLE note (id INTEGER);\nINSERT INTO missing VALUES (1);",
+		Down:    "DROP TABLE note;",
+	}})
+
+	_, err := m.Up(context.TODO())
+	assert.Error(t, err)
+
+	statuses, err := m.Status(context.TODO())
+	require.NoError(t, err)
+	assert.False(t, statuses[0].Applied)
+	_, err = countNotes(t, db)
+	assert.Error(t, err)
+}
+
+func TestPostgresPlaceholders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	m := migration.New(db, "postgres", []migration.Migration{{
+		Version: 1,
+		Name:    "create_note",
+		Up:      "CREATE TABLE note (id BIGSERIAL PRIMARY KEY);",
+		Down:    "DROP TABLE note;",
+	}})
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE note").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO migrations \\(version, name, checksum, applied_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	applied, err := m.Up(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSeed(t *testing.T) {
+	fsys, err := migration.Source("sqlite")
+	require.NoError(t, err)
+	migrations, err := migration.Load(fsys)
+	require.NoError(t, err)
+	db := openDB(t)
+	_, err = migration.New(db, "sqlite", migrations).Up(context.TODO())
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM article").Scan(&count))
+	assert.Zero(t, count, "migrations hold no data")
+
+	require.NoError(t, migration.Seed(context.TODO(), db, "sqlite"))
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM article WHERE status = 'published'").Scan(&count))
+	assert.Equal(t, 3, count)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM article_revision WHERE rev = 1").Scan(&count))
+	assert.Equal(t, 3, count)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM article_category WHERE article_id NOT IN (SELECT id FROM article)").Scan(&count))
+	assert.Zero(t, count, "no link to a missing article")
+
+	assert.Equal(t, migration.ErrNotEmpty, migration.Seed(context.TODO(), db, "sqlite"))
+	assert.Error(t, migration.Seed(context.TODO(), db, "oracle"))
+}
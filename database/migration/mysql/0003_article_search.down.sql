@@ -0,0 +1,2 @@
+ALTER TABLE `article` DROP KEY `article_author`;
+ALTER TABLE `article` DROP KEY `article_search`;
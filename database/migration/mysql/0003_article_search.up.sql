@@ -0,0 +1,2 @@
+ALTER TABLE `article` ADD FULLTEXT KEY `article_search` (`title`, `content`);
+ALTER TABLE `article` ADD KEY `article_author` (`author_id`, `created_at`, `id`);
@@ -0,0 +1,4 @@
+-- articles already stored were live, they stay published
+ALTER TABLE `article` ADD COLUMN `status` varchar(16) COLLATE utf8_unicode_ci NOT NULL DEFAULT 'draft', ADD COLUMN `published_at` datetime DEFAULT NULL;
+UPDATE `article` SET `status` = 'published', `published_at` = `created_at`;
+ALTER TABLE `article` ADD KEY `article_status` (`status`, `created_at`, `id`);
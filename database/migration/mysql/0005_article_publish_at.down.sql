@@ -0,0 +1 @@
+ALTER TABLE `article` DROP KEY `article_publish_at`, DROP COLUMN `publish_at`;
@@ -0,0 +1,2 @@
+ALTER TABLE `article` ADD COLUMN `publish_at` datetime DEFAULT NULL;
+ALTER TABLE `article` ADD KEY `article_publish_at` (`publish_at`);
@@ -0,0 +1,14 @@
+CREATE TABLE `article_revision` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(11) unsigned NOT NULL,
+  `rev` int(11) unsigned NOT NULL,
+  `title` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `content` longtext COLLATE utf8_unicode_ci NOT NULL,
+  `user_id` int(11) unsigned DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `article_revision_rev` (`article_id`, `rev`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+INSERT INTO `article_revision` (`article_id`, `rev`, `title`, `content`, `created_at`)
+  SELECT `id`, 1, `title`, `content`, COALESCE(`updated_at`, `created_at`) FROM `article`;
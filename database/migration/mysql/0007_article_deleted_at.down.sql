@@ -0,0 +1,5 @@
+-- the articles in the trash would be live again, they are deleted for good
+DELETE FROM `article_category` WHERE `article_id` IN (SELECT `id` FROM `article` WHERE `deleted_at` IS NOT NULL);
+DELETE FROM `article_revision` WHERE `article_id` IN (SELECT `id` FROM `article` WHERE `deleted_at` IS NOT NULL);
+DELETE FROM `article` WHERE `deleted_at` IS NOT NULL;
+ALTER TABLE `article` DROP KEY `article_deleted_at`, DROP COLUMN `deleted_at`;
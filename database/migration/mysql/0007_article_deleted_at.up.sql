@@ -0,0 +1,2 @@
+ALTER TABLE `article` ADD COLUMN `deleted_at` datetime DEFAULT NULL;
+ALTER TABLE `article` ADD KEY `article_deleted_at` (`deleted_at`);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `article_slug`;
+ALTER TABLE `article` DROP KEY `article_current_slug`;
+ALTER TABLE `article` DROP COLUMN `slug`;
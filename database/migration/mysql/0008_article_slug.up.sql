@@ -0,0 +1,12 @@
+ALTER TABLE `article` ADD COLUMN `slug` varchar(100) COLLATE utf8_unicode_ci DEFAULT NULL;
+ALTER TABLE `article` ADD UNIQUE KEY `article_current_slug` (`slug`);
+
+-- every slug an article ever had, so that none is given twice and the old ones
+-- lead to the article under its current slug
+CREATE TABLE `article_slug` (
+  `slug` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `article_id` int(11) unsigned NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`slug`),
+  KEY `article_slug_article` (`article_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
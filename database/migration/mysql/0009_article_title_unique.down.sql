@@ -0,0 +1,2 @@
+ALTER TABLE `article` DROP KEY `article_title`;
+ALTER TABLE `article` DROP COLUMN `live_title`;
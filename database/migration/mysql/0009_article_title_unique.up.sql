@@ -0,0 +1,5 @@
+-- the title of a live article is unique regardless of case and accents, as its
+-- collation compares them; articles in the trash give NULL, which never collides
+ALTER TABLE `article` ADD COLUMN `live_title` varchar(45) COLLATE utf8_unicode_ci
+  GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `title`, NULL)) VIRTUAL;
+ALTER TABLE `article` ADD UNIQUE KEY `article_title` (`live_title`);
@@ -0,0 +1 @@
+ALTER TABLE `category` DROP KEY `category_tag`;
@@ -0,0 +1,2 @@
+-- a tag names a single category, as the category filters and facets find it by
+ALTER TABLE `category` ADD UNIQUE KEY `category_tag` (`tag`);
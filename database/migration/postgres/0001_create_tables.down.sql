@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS refresh_token;
+DROP TABLE IF EXISTS user_role;
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS category;
+DROP TABLE IF EXISTS author;
+DROP TABLE IF EXISTS article_category;
+DROP TABLE IF EXISTS article;
@@ -0,0 +1,63 @@
+CREATE TABLE article (
+  id BIGSERIAL PRIMARY KEY,
+  title VARCHAR(45) NOT NULL,
+  content TEXT NOT NULL,
+  author_id BIGINT DEFAULT 0,
+  updated_at TIMESTAMPTZ DEFAULT NULL,
+  created_at TIMESTAMPTZ DEFAULT NULL,
+  version BIGINT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE article_category (
+  id BIGSERIAL PRIMARY KEY,
+  article_id BIGINT NOT NULL,
+  category_id BIGINT NOT NULL,
+  CONSTRAINT article_category_composite UNIQUE (article_id, category_id)
+);
+
+CREATE TABLE author (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(200) DEFAULT '""',
+  created_at TIMESTAMPTZ DEFAULT NULL,
+  updated_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE TABLE category (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(45) NOT NULL,
+  tag VARCHAR(45) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NULL,
+  updated_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE TABLE "user" (
+  id BIGSERIAL PRIMARY KEY,
+  fullname VARCHAR(200) NOT NULL DEFAULT '',
+  username VARCHAR(45) NOT NULL,
+  email VARCHAR(200) NOT NULL,
+  password VARCHAR(60) NOT NULL,
+  author_id BIGINT DEFAULT NULL,
+  created_at TIMESTAMPTZ DEFAULT NULL,
+  updated_at TIMESTAMPTZ DEFAULT NULL,
+  CONSTRAINT user_username UNIQUE (username),
+  CONSTRAINT user_email UNIQUE (email),
+  CONSTRAINT user_author UNIQUE (author_id)
+);
+
+CREATE TABLE user_role (
+  user_id BIGINT NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  PRIMARY KEY (user_id, role)
+);
+
+CREATE TABLE refresh_token (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ DEFAULT NULL,
+  created_at TIMESTAMPTZ DEFAULT NULL,
+  CONSTRAINT refresh_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX refresh_token_user ON refresh_token (user_id);
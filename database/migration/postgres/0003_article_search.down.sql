@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS article_author;
+DROP INDEX IF EXISTS article_search;
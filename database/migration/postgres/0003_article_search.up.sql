@@ -0,0 +1,2 @@
+CREATE INDEX article_search ON article USING GIN (to_tsvector('simple', title || ' ' || content));
+CREATE INDEX article_author ON article (author_id, created_at, id);
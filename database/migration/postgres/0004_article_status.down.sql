@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS article_status;
+ALTER TABLE article DROP COLUMN published_at, DROP COLUMN status;
@@ -0,0 +1,4 @@
+-- articles already stored were live, they stay published
+ALTER TABLE article ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft', ADD COLUMN published_at TIMESTAMPTZ DEFAULT NULL;
+UPDATE article SET status = 'published', published_at = created_at;
+CREATE INDEX article_status ON article (status, created_at, id);
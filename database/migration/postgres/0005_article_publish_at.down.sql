@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS article_publish_at;
+ALTER TABLE article DROP COLUMN publish_at;
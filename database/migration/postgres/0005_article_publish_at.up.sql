@@ -0,0 +1,2 @@
+ALTER TABLE article ADD COLUMN publish_at TIMESTAMPTZ DEFAULT NULL;
+CREATE INDEX article_publish_at ON article (publish_at);
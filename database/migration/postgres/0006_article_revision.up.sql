@@ -0,0 +1,13 @@
+CREATE TABLE article_revision (
+  id BIGSERIAL PRIMARY KEY,
+  article_id BIGINT NOT NULL,
+  rev BIGINT NOT NULL,
+  title VARCHAR(45) NOT NULL,
+  content TEXT NOT NULL,
+  user_id BIGINT DEFAULT NULL,
+  created_at TIMESTAMPTZ DEFAULT NULL,
+  CONSTRAINT article_revision_rev UNIQUE (article_id, rev)
+);
+
+INSERT INTO article_revision (article_id, rev, title, content, created_at)
+  SELECT id, 1, title, content, COALESCE(updated_at, created_at) FROM article;
@@ -0,0 +1,11 @@
+ALTER TABLE article ADD COLUMN slug VARCHAR(100) DEFAULT NULL;
+ALTER TABLE article ADD CONSTRAINT article_current_slug UNIQUE (slug);
+
+-- every slug an article ever had, so that none is given twice and the old ones
+-- lead to the article under its current slug
+CREATE TABLE article_slug (
+  slug VARCHAR(100) PRIMARY KEY,
+  article_id BIGINT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NULL
+);
+CREATE INDEX article_slug_article ON article_slug (article_id);
@@ -0,0 +1,2 @@
+-- the title of a live article is unique regardless of case
+CREATE UNIQUE INDEX article_title ON article (lower(title)) WHERE deleted_at IS NULL;
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed seed/*.sql
+var seeds embed.FS
+
+// ErrNotEmpty will throw when seeding a database that already holds data
+var ErrNotEmpty = errors.New("the database already has authors, categories or articles")
+
+// Seed fills an empty database, migrated up to date, with the demo authors,
+// categories and articles written for the given database driver. It is never
+// run by the migrations, only when asked for.
+func Seed(ctx context.Context, db *sql.DB, driver string) (err error) {
+	script, err := fs.ReadFile(seeds, "seed/"+driver+".sql")
+	if err != nil {
+		return fmt.Errorf("no seed for database driver %q", driver)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				logrus.Error(errRollback)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	for _, table := range []string{"author", "category", "article"} {
+		var count int
+		if err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count); err != nil {
+			return
+		}
+		if count > 0 {
+			return ErrNotEmpty
+		}
+	}
+
+	for _, statement := range splitStatements(string(script)) {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,59 @@
+CREATE TABLE article (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(45) NOT NULL,
+  content TEXT NOT NULL,
+  author_id INTEGER DEFAULT 0,
+  updated_at DATETIME DEFAULT NULL,
+  created_at DATETIME DEFAULT NULL,
+  version INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE article_category (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  article_id INTEGER NOT NULL,
+  category_id INTEGER NOT NULL,
+  UNIQUE (article_id, category_id)
+);
+
+CREATE TABLE author (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(200) DEFAULT '""',
+  created_at DATETIME DEFAULT NULL,
+  updated_at DATETIME DEFAULT NULL
+);
+
+CREATE TABLE category (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(45) NOT NULL,
+  tag VARCHAR(45) NOT NULL,
+  created_at DATETIME DEFAULT NULL,
+  updated_at DATETIME DEFAULT NULL
+);
+
+CREATE TABLE user (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  fullname VARCHAR(200) NOT NULL DEFAULT '',
+  username VARCHAR(45) NOT NULL UNIQUE,
+  email VARCHAR(200) NOT NULL UNIQUE,
+  password VARCHAR(60) NOT NULL,
+  author_id INTEGER DEFAULT NULL UNIQUE,
+  created_at DATETIME DEFAULT NULL,
+  updated_at DATETIME DEFAULT NULL
+);
+
+CREATE TABLE user_role (
+  user_id INTEGER NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  PRIMARY KEY (user_id, role)
+);
+
+CREATE TABLE refresh_token (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at DATETIME NOT NULL,
+  revoked_at DATETIME DEFAULT NULL,
+  created_at DATETIME DEFAULT NULL
+);
+
+CREATE INDEX refresh_token_user ON refresh_token (user_id);
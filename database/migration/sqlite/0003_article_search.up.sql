@@ -0,0 +1 @@
+CREATE INDEX article_author ON article (author_id, created_at, id);
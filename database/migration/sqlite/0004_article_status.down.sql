@@ -0,0 +1,17 @@
+-- SQLite cannot drop columns before 3.35, the table is rebuilt without them
+DROP INDEX IF EXISTS article_status;
+DROP INDEX IF EXISTS article_author;
+CREATE TABLE article_without_status (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(45) NOT NULL,
+  content TEXT NOT NULL,
+  author_id INTEGER DEFAULT 0,
+  updated_at DATETIME DEFAULT NULL,
+  created_at DATETIME DEFAULT NULL,
+  version INTEGER NOT NULL DEFAULT 1
+);
+INSERT INTO article_without_status (id, title, content, author_id, updated_at, created_at, version)
+  SELECT id, title, content, author_id, updated_at, created_at, version FROM article;
+DROP TABLE article;
+ALTER TABLE article_without_status RENAME TO article;
+CREATE INDEX article_author ON article (author_id, created_at, id);
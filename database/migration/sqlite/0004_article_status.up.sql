@@ -0,0 +1,5 @@
+-- articles already stored were live, they stay published
+ALTER TABLE article ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft';
+ALTER TABLE article ADD COLUMN published_at DATETIME DEFAULT NULL;
+UPDATE article SET status = 'published', published_at = created_at;
+CREATE INDEX article_status ON article (status, created_at, id);
@@ -0,0 +1,21 @@
+-- SQLite cannot drop columns before 3.35, the table is rebuilt without it
+DROP INDEX IF EXISTS article_publish_at;
+DROP INDEX IF EXISTS article_status;
+DROP INDEX IF EXISTS article_author;
+CREATE TABLE article_without_publish_at (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(45) NOT NULL,
+  content TEXT NOT NULL,
+  author_id INTEGER DEFAULT 0,
+  updated_at DATETIME DEFAULT NULL,
+  created_at DATETIME DEFAULT NULL,
+  version INTEGER NOT NULL DEFAULT 1,
+  status VARCHAR(16) NOT NULL DEFAULT 'draft',
+  published_at DATETIME DEFAULT NULL
+);
+INSERT INTO article_without_publish_at (id, title, content, author_id, updated_at, created_at, version, status, published_at)
+  SELECT id, title, content, author_id, updated_at, created_at, version, status, published_at FROM article;
+DROP TABLE article;
+ALTER TABLE article_without_publish_at RENAME TO article;
+CREATE INDEX article_author ON article (author_id, created_at, id);
+CREATE INDEX article_status ON article (status, created_at, id);
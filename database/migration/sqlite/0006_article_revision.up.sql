@@ -0,0 +1,13 @@
+CREATE TABLE article_revision (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  article_id INTEGER NOT NULL,
+  rev INTEGER NOT NULL,
+  title VARCHAR(45) NOT NULL,
+  content TEXT NOT NULL,
+  user_id INTEGER DEFAULT NULL,
+  created_at DATETIME DEFAULT NULL,
+  UNIQUE (article_id, rev)
+);
+
+INSERT INTO article_revision (article_id, rev, title, content, created_at)
+  SELECT id, 1, title, content, COALESCE(updated_at, created_at) FROM article;
@@ -0,0 +1,2 @@
+ALTER TABLE article ADD COLUMN deleted_at DATETIME DEFAULT NULL;
+CREATE INDEX article_deleted_at ON article (deleted_at);
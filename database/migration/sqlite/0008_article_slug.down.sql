@@ -0,0 +1,28 @@
+DROP TABLE IF EXISTS article_slug;
+DROP INDEX IF EXISTS article_current_slug;
+-- SQLite cannot drop columns before 3.35, the table is rebuilt without it
+DROP INDEX IF EXISTS article_deleted_at;
+DROP INDEX IF EXISTS article_publish_at;
+DROP INDEX IF EXISTS article_status;
+DROP INDEX IF EXISTS article_author;
+CREATE TABLE article_without_slug (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  title VARCHAR(45) NOT NULL,
+  content TEXT NOT NULL,
+  author_id INTEGER DEFAULT 0,
+  updated_at DATETIME DEFAULT NULL,
+  created_at DATETIME DEFAULT NULL,
+  version INTEGER NOT NULL DEFAULT 1,
+  status VARCHAR(16) NOT NULL DEFAULT 'draft',
+  published_at DATETIME DEFAULT NULL,
+  publish_at DATETIME DEFAULT NULL,
+  deleted_at DATETIME DEFAULT NULL
+);
+INSERT INTO article_without_slug (id, title, content, author_id, updated_at, created_at, version, status, published_at, publish_at, deleted_at)
+  SELECT id, title, content, author_id, updated_at, created_at, version, status, published_at, publish_at, deleted_at FROM article;
+DROP TABLE article;
+ALTER TABLE article_without_slug RENAME TO article;
+CREATE INDEX article_author ON article (author_id, created_at, id);
+CREATE INDEX article_status ON article (status, created_at, id);
+CREATE INDEX article_publish_at ON article (publish_at);
+CREATE INDEX article_deleted_at ON article (deleted_at);
@@ -0,0 +1,11 @@
+ALTER TABLE article ADD COLUMN slug VARCHAR(100) DEFAULT NULL;
+CREATE UNIQUE INDEX article_current_slug ON article (slug);
+
+-- every slug an article ever had, so that none is given twice and the old ones
+-- lead to the article under its current slug
+CREATE TABLE article_slug (
+  slug VARCHAR(100) PRIMARY KEY,
+  article_id INTEGER NOT NULL,
+  created_at DATETIME DEFAULT NULL
+);
+CREATE INDEX article_slug_article ON article_slug (article_id);
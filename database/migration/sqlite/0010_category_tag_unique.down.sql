@@ -0,0 +1 @@
+DROP INDEX IF EXISTS category_tag;
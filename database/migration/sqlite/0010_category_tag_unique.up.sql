@@ -0,0 +1,2 @@
+-- a tag names a single category, as the category filters and facets find it by
+CREATE UNIQUE INDEX category_tag ON category (tag);
@@ -0,0 +1,211 @@
+// Package pagination implements the keyset pagination shared by the
+// repositories. Listings are ordered by (created_at, id), so rows created at
+// the same instant are neither skipped nor repeated between pages, and a
+// cursor can lead forward to the next page or backward to the previous one.
+// Listings sorted by relevance are ordered by (rank, created_at, id), best
+// and newest first.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// Key is the position of a row in a listing. Rank only matters to listings
+// sorted by relevance.
+type Key struct {
+	Rank      float64
+	CreatedAt time.Time
+	ID        int64
+}
+
+// before and after are keys around every row, used in place of a cursor on
+// the first page so that every query has the same arguments
+var (
+	before = Key{Rank: -math.MaxFloat64, CreatedAt: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), ID: 0}
+	after  = Key{Rank: math.MaxFloat64, CreatedAt: time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), ID: math.MaxInt64}
+)
+
+// cursor is the content of an encoded cursor
+type cursor struct {
+	Rank      float64   `json:"r,omitempty"`
+	CreatedAt time.Time `json:"t"`
+	ID        int64     `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+func encode(key Key, backward bool) string {
+	byt, _ := json.Marshal(cursor{Rank: key.Rank, CreatedAt: key.CreatedAt.UTC(), ID: key.ID, Backward: backward})
+	return base64.RawURLEncoding.EncodeToString(byt)
+}
+
+func decode(encoded string) (c cursor, err error) {
+	byt, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(byt, &c)
+	return
+}
+
+// Keyset selects the rows of a page. The repositories query the rows whose
+// (created_at, id), preceded by the rank when Ranked, compares with Op to the
+// position in Args, in the order of OrderBy, and hand them to Page.
+type Keyset struct {
+	key       Key
+	hasCursor bool
+	backward  bool
+	desc      bool
+	ranked    bool
+	num       int64
+}
+
+// New will create the Keyset of page. A malformed cursor, an unknown sort
+// direction or a page size below one is an ErrBadParamInput.
+func New(page domain.Page) (k Keyset, err error) {
+	if !page.Sort.Valid() || page.Num < 1 {
+		return Keyset{}, errHandle.ErrBadParamInput
+	}
+
+	k = Keyset{
+		desc:   page.Sort == domain.SortDesc || page.Sort == domain.SortRelevance,
+		ranked: page.Sort == domain.SortRelevance,
+		num:    page.Num,
+	}
+	if page.Cursor == "" {
+		return
+	}
+
+	c, err := decode(page.Cursor)
+	if err != nil {
+		return Keyset{}, errHandle.ErrBadParamInput
+	}
+	k.key = Key{Rank: c.Rank, CreatedAt: c.CreatedAt, ID: c.ID}
+	k.hasCursor = true
+	k.backward = c.Backward
+	return
+}
+
+// descending reports whether the rows are scanned newest first, which is the
+// case for the next pages of a descending listing and the previous pages of
+// an ascending one
+func (k Keyset) descending() bool {
+	return k.desc != k.backward
+}
+
+func (k Keyset) start() Key {
+	if k.hasCursor {
+		return k.key
+	}
+	if k.descending() {
+		return after
+	}
+	return before
+}
+
+// Ranked reports whether the listing is sorted by relevance, the rank then
+// coming first in the position and the order
+func (k Keyset) Ranked() bool {
+	return k.ranked
+}
+
+// Op is the operator comparing (created_at, id) to the position
+func (k Keyset) Op() string {
+	if k.descending() {
+		return "<"
+	}
+	return ">"
+}
+
+// OrderBy is the ORDER BY clause on the given columns, the rank if Ranked,
+// created_at and id
+func (k Keyset) OrderBy(columns ...string) string {
+	order := " ASC"
+	if k.descending() {
+		order = " DESC"
+	}
+	return strings.Join(columns, order+", ") + order
+}
+
+// Args are the rank if Ranked, created_at and id of the position followed by
+// the LIMIT
+func (k Keyset) Args() []interface{} {
+	start := k.start()
+	if k.ranked {
+		return []interface{}{start.Rank, start.CreatedAt.UTC(), start.ID, k.Limit()}
+	}
+	return []interface{}{start.CreatedAt.UTC(), start.ID, k.Limit()}
+}
+
+// Limit is the number of rows to query, one more than the page holds to tell
+// whether another page follows
+func (k Keyset) Limit() int64 {
+	return k.num + 1
+}
+
+// Less reports whether the row at a is scanned before the row at b
+func (k Keyset) Less(a, b Key) bool {
+	if k.ranked && a.Rank != b.Rank {
+		return (a.Rank < b.Rank) != k.descending()
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt) != k.descending()
+	}
+	if a.ID == b.ID {
+		return false
+	}
+	return (a.ID < b.ID) != k.descending()
+}
+
+// Admits reports whether the row at key is past the position
+func (k Keyset) Admits(key Key) bool {
+	return k.Less(k.start(), key)
+}
+
+// Page cuts rows, the slice of rows queried with the keyset in scan order,
+// down to the page in listing order. It returns the length the slice must be
+// truncated to and the cursors around the page; key gives the key of row i.
+func (k Keyset) Page(rows interface{}, key func(i int) Key) (n int, cursors domain.Cursors) {
+	n = reflect.ValueOf(rows).Len()
+	more := int64(n) > k.num
+	if more {
+		n = int(k.num)
+	}
+
+	if n == 0 {
+		// past either end, the way back starts at the cursor itself
+		if k.hasCursor && k.backward {
+			cursors.Next = encode(k.key, false)
+		} else if k.hasCursor {
+			cursors.Prev = encode(k.key, true)
+		}
+		return
+	}
+
+	if k.backward {
+		swap := reflect.Swapper(rows)
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+		cursors.Next = encode(key(n-1), false)
+		if more {
+			cursors.Prev = encode(key(0), true)
+		}
+		return
+	}
+
+	if more {
+		cursors.Next = encode(key(n-1), false)
+	}
+	if k.hasCursor {
+		cursors.Prev = encode(key(0), true)
+	}
+	return
+}
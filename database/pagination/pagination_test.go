@@ -0,0 +1,71 @@
+package pagination_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+var createdAt = time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC)
+
+func keys(rows []int64) func(i int) pagination.Key {
+	return func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: createdAt, ID: rows[i]}
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := pagination.New(domain.Page{Num: 2, Cursor: "not a cursor"})
+	assert.Equal(t, errHandle.ErrBadParamInput, err)
+	_, err = pagination.New(domain.Page{Num: 2, Sort: "sideways"})
+	assert.Equal(t, errHandle.ErrBadParamInput, err)
+	_, err = pagination.New(domain.Page{Num: 0})
+	assert.Equal(t, errHandle.ErrBadParamInput, err)
+
+	keyset, err := pagination.New(domain.Page{Num: 2, Sort: domain.SortDesc})
+	require.NoError(t, err)
+	assert.Equal(t, "<", keyset.Op())
+	assert.Equal(t, "created_at DESC, id DESC", keyset.OrderBy("created_at", "id"))
+	assert.Equal(t, int64(3), keyset.Limit())
+}
+
+func TestPage(t *testing.T) {
+	keyset, err := pagination.New(domain.Page{Num: 2})
+	require.NoError(t, err)
+	assert.Equal(t, ">", keyset.Op())
+
+	rows := []int64{1, 2, 3}
+	n, cursors := keyset.Page(rows, keys(rows))
+	assert.Equal(t, []int64{1, 2}, rows[:n])
+	assert.Empty(t, cursors.Prev, "the first page has nothing before it")
+	require.NotEmpty(t, cursors.Next)
+
+	next, err := pagination.New(domain.Page{Num: 2, Cursor: cursors.Next})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{createdAt, int64(2), int64(3)}, next.Args(), "the cursor keeps the nanoseconds")
+	assert.True(t, next.Admits(pagination.Key{CreatedAt: createdAt, ID: 3}))
+	assert.False(t, next.Admits(pagination.Key{CreatedAt: createdAt, ID: 2}))
+
+	rows = []int64{3}
+	n, cursors = next.Page(rows, keys(rows))
+	assert.Equal(t, []int64{3}, rows[:n])
+	assert.Empty(t, cursors.Next)
+	require.NotEmpty(t, cursors.Prev)
+
+	prev, err := pagination.New(domain.Page{Num: 2, Cursor: cursors.Prev})
+	require.NoError(t, err)
+	assert.Equal(t, "<", prev.Op(), "a previous page is scanned backward")
+
+	// scanned newest first, one more row than the page holds
+	rows = []int64{2, 1, 0}
+	n, cursors = prev.Page(rows, keys(rows))
+	assert.Equal(t, []int64{1, 2}, rows[:n], "the rows come back in listing order")
+	assert.NotEmpty(t, cursors.Prev)
+	assert.NotEmpty(t, cursors.Next)
+}
@@ -0,0 +1,115 @@
+package pagination
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// Sealer is the domain.CursorSealer handing out cursors signed with
+// HMAC-SHA256. A sealed cursor is the base64 of its content, a dot and the
+// base64 of the signature of that content.
+type Sealer struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewSealer will create a Sealer signing with key whose cursors expire after ttl
+func NewSealer(key []byte, ttl time.Duration) *Sealer {
+	return &Sealer{key: key, ttl: ttl, now: time.Now}
+}
+
+// sealed is the content of a sealed cursor
+type sealed struct {
+	Cursor  string          `json:"c"`
+	Scope   json.RawMessage `json:"s"`
+	Expires int64           `json:"exp"`
+}
+
+// scope describes the listing a cursor is valid for
+type scope struct {
+	Listing string               `json:"l"`
+	Sort    domain.SortDirection `json:"o"`
+	Filter  interface{}          `json:"f,omitempty"`
+}
+
+func newScope(page domain.Page, listing string, filter interface{}) (json.RawMessage, error) {
+	sort := page.Sort
+	if sort == "" {
+		sort = domain.SortAsc
+	}
+	return json.Marshal(scope{Listing: listing, Sort: sort, Filter: filter})
+}
+
+func (s *Sealer) sign(content []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(content)
+	return mac.Sum(nil)
+}
+
+func (s *Sealer) seal(cursor string, sc json.RawMessage, expires int64) string {
+	if cursor == "" {
+		return ""
+	}
+	content, _ := json.Marshal(sealed{Cursor: cursor, Scope: sc, Expires: expires})
+	return base64.RawURLEncoding.EncodeToString(content) + "." + base64.RawURLEncoding.EncodeToString(s.sign(content))
+}
+
+// Seal signs cursors for the given listing, sort and filter
+func (s *Sealer) Seal(page domain.Page, listing string, filter interface{}, cursors domain.Cursors) domain.Cursors {
+	sc, err := newScope(page, listing, filter)
+	if err != nil {
+		return domain.Cursors{}
+	}
+
+	expires := s.now().Add(s.ttl).Unix()
+	return domain.Cursors{
+		Next: s.seal(cursors.Next, sc, expires),
+		Prev: s.seal(cursors.Prev, sc, expires),
+	}
+}
+
+// Open checks the cursor of page was sealed for the given listing, sort and
+// filter and has not expired, and replaces it with the cursor it seals
+func (s *Sealer) Open(page domain.Page, listing string, filter interface{}) (domain.Page, error) {
+	if page.Cursor == "" {
+		return page, nil
+	}
+
+	parts := strings.Split(page.Cursor, ".")
+	if len(parts) != 2 {
+		return domain.Page{}, errHandle.ErrBadParamInput
+	}
+	content, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return domain.Page{}, errHandle.ErrBadParamInput
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, s.sign(content)) {
+		return domain.Page{}, errHandle.ErrBadParamInput
+	}
+
+	var c sealed
+	if err = json.Unmarshal(content, &c); err != nil {
+		return domain.Page{}, errHandle.ErrBadParamInput
+	}
+	if s.now().Unix() >= c.Expires {
+		return domain.Page{}, errHandle.ErrBadParamInput
+	}
+
+	sc, err := newScope(page, listing, filter)
+	if err != nil || !bytes.Equal(sc, c.Scope) {
+		return domain.Page{}, errHandle.ErrBadParamInput
+	}
+
+	page.Cursor = c.Cursor
+	return page, nil
+}
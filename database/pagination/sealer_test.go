@@ -0,0 +1,62 @@
+package pagination_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestSealer(t *testing.T) {
+	sealer := pagination.NewSealer([]byte("secret"), time.Minute)
+	page := domain.Page{Num: 10}
+	cursors := sealer.Seal(page, "articles", map[string]string{"q": "go"}, domain.Cursors{Next: "next"})
+	require.NotEmpty(t, cursors.Next)
+	assert.Empty(t, cursors.Prev)
+	assert.NotContains(t, cursors.Next, "next")
+
+	t.Run("open", func(t *testing.T) {
+		opened, err := sealer.Open(domain.Page{Cursor: cursors.Next, Num: 5, Sort: domain.SortAsc}, "articles", map[string]string{"q": "go"})
+		require.NoError(t, err)
+		assert.Equal(t, domain.Page{Cursor: "next", Num: 5, Sort: domain.SortAsc}, opened)
+	})
+
+	t.Run("first-page", func(t *testing.T) {
+		opened, err := sealer.Open(page, "articles", nil)
+		require.NoError(t, err)
+		assert.Equal(t, page, opened)
+	})
+
+	rejected := map[string]struct {
+		sealer  *pagination.Sealer
+		page    domain.Page
+		listing string
+		filter  interface{}
+	}{
+		"other-filter":  {sealer, domain.Page{Cursor: cursors.Next}, "articles", map[string]string{"q": "rust"}},
+		"other-sort":    {sealer, domain.Page{Cursor: cursors.Next, Sort: domain.SortDesc}, "articles", map[string]string{"q": "go"}},
+		"other-listing": {sealer, domain.Page{Cursor: cursors.Next}, "authors", map[string]string{"q": "go"}},
+		"other-key":     {pagination.NewSealer([]byte("other"), time.Minute), domain.Page{Cursor: cursors.Next}, "articles", map[string]string{"q": "go"}},
+		"tampered":      {sealer, domain.Page{Cursor: "x" + cursors.Next}, "articles", map[string]string{"q": "go"}},
+		"unsigned":      {sealer, domain.Page{Cursor: strings.Split(cursors.Next, ".")[0]}, "articles", map[string]string{"q": "go"}},
+	}
+	for name, tc := range rejected {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.sealer.Open(tc.page, tc.listing, tc.filter)
+			assert.Equal(t, errHandle.ErrBadParamInput, err)
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		expiring := pagination.NewSealer([]byte("secret"), -time.Second)
+		expired := expiring.Seal(page, "articles", nil, domain.Cursors{Next: "next"})
+		_, err := expiring.Open(domain.Page{Cursor: expired.Next}, "articles", nil)
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+}
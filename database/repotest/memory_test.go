@@ -0,0 +1,63 @@
+package repotest_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/repotest"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	_articleRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/memory"
+	_authRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/memory"
+	_authorRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/memory"
+	_categoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/memory"
+	_userRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/memory"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestMemory(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db := memdb.New()
+
+		return repotest.Repositories{
+			Article:      _articleRepo.NewMemoryArticleRepository(db),
+			Revision:     _articleRepo.NewMemoryArticleRevisionRepository(db),
+			Author:       _authorRepo.NewMemoryAuthorRepository(db),
+			Category:     _categoryRepo.NewMemoryCategoryRepository(db),
+			User:         _userRepo.NewMemoryUserRepository(db),
+			RefreshToken: _authRepo.NewMemoryRefreshTokenRepository(db),
+			Transactor:   db,
+		}
+	})
+}
+
+func TestMemoryConcurrentStore(t *testing.T) {
+	repo := _userRepo.NewMemoryUserRepository(memdb.New())
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			u := domain.User{Username: "iman", Email: fmt.Sprintf("iman%d@example.com", i)}
+			errs <- repo.Store(context.TODO(), &u)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	stored := 0
+	for err := range errs {
+		if err == nil {
+			stored++
+			continue
+		}
+		assert.Equal(t, errHandle.ErrConflict, err)
+	}
+	assert.Equal(t, 1, stored)
+}
@@ -0,0 +1,47 @@
+package repotest_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/repotest"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	_articleRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/mysql"
+	_authRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/mysql"
+	_authorRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/mysql"
+	_categoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/mysql"
+	_userRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/mysql"
+)
+
+// TestMysql runs against the database of MYSQL_TEST_DSN, which must have
+// parseTime enabled, e.g. root:secret@tcp(localhost:3306)/article_test?parseTime=1.
+// Every table of that database is emptied.
+func TestMysql(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db, err := sql.Open("mysql", dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		migrate(t, db, "mysql")
+		repotest.Truncate(t, db)
+
+		return repotest.Repositories{
+			Article:      _articleRepo.NewMysqlArticleRepository(db),
+			Revision:     _articleRepo.NewMysqlArticleRevisionRepository(db),
+			Author:       _authorRepo.NewMysqlAuthorRepository(db),
+			Category:     _categoryRepo.NewMysqlCategoryRepository(db),
+			User:         _userRepo.NewMysqlUserRepository(db),
+			RefreshToken: _authRepo.NewMysqlRefreshTokenRepository(db),
+			Transactor:   transaction.NewSQLTransactor(db),
+		}
+	})
+}
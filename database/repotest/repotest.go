@@ -0,0 +1,979 @@
+// Package repotest holds the conformance suite every repository backend has to
+// pass, so that the service behaves the same whichever database.driver is set.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// Repositories groups the repositories of one backend
+type Repositories struct {
+	Article      domain.ArticleRepository
+	Revision     domain.ArticleRevisionRepository
+	Author       domain.AuthorRepository
+	Category     domain.CategoryRepository
+	User         domain.UserRepository
+	RefreshToken domain.RefreshTokenRepository
+	Transactor   domain.Transactor
+}
+
+// Factory returns the repositories of a backend on an empty database
+type Factory func(t *testing.T) Repositories
+
+// tables lists every table written by the repositories
+var tables = []string{"refresh_token", "user_role", "user", "article_slug", "article_revision", "article_category", "category", "article", "author"}
+
+// Truncate deletes the rows of every repository table, those left by earlier
+// runs included
+func Truncate(t *testing.T, db *sql.DB) {
+	for _, table := range tables {
+		_, err := db.Exec("DELETE FROM " + table)
+		require.NoError(t, err)
+	}
+}
+
+// base is whole seconds so that backends storing DATETIME without fraction
+// give back exactly what was stored
+var base = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func at(i int) time.Time {
+	return base.Add(time.Duration(i) * time.Minute)
+}
+
+// Run runs the whole suite, each test on a fresh set of repositories
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("Author", func(t *testing.T) { testAuthor(t, newRepos(t)) })
+	t.Run("Article", func(t *testing.T) { testArticle(t, newRepos(t)) })
+	t.Run("ArticleFetchBy", func(t *testing.T) { testArticleFetchBy(t, newRepos(t)) })
+	t.Run("ArticleStatus", func(t *testing.T) { testArticleStatus(t, newRepos(t)) })
+	t.Run("ArticleDue", func(t *testing.T) { testArticleDue(t, newRepos(t)) })
+	t.Run("ArticleRevision", func(t *testing.T) { testArticleRevision(t, newRepos(t)) })
+	t.Run("ArticleTrash", func(t *testing.T) { testArticleTrash(t, newRepos(t)) })
+	t.Run("ArticleSlug", func(t *testing.T) { testArticleSlug(t, newRepos(t)) })
+	t.Run("ArticleTitle", func(t *testing.T) { testArticleTitle(t, newRepos(t)) })
+	t.Run("Pagination", func(t *testing.T) { testPagination(t, newRepos(t)) })
+	t.Run("ArticleFilter", func(t *testing.T) { testArticleFilter(t, newRepos(t)) })
+	t.Run("Category", func(t *testing.T) { testCategory(t, newRepos(t)) })
+	t.Run("User", func(t *testing.T) { testUser(t, newRepos(t)) })
+	t.Run("RefreshToken", func(t *testing.T) { testRefreshToken(t, newRepos(t)) })
+	t.Run("Transaction", func(t *testing.T) { testTransaction(t, newRepos(t)) })
+}
+
+func storeAuthor(t *testing.T, repos Repositories, name string, i int) domain.Author {
+	a := domain.Author{Name: name, CreatedAt: at(i), UpdatedAt: at(i)}
+	require.NoError(t, repos.Author.Store(context.TODO(), &a))
+	require.NotZero(t, a.ID)
+	return a
+}
+
+func storeArticle(t *testing.T, repos Repositories, title string, authorID int64, i int) domain.Article {
+	a := domain.Article{
+		Title:     title,
+		Content:   "content of " + title,
+		Author:    domain.Author{ID: authorID},
+		Status:    domain.StatusDraft,
+		CreatedAt: at(i),
+		UpdatedAt: at(i),
+	}
+	require.NoError(t, repos.Article.Store(context.TODO(), &a))
+	require.NotZero(t, a.ID)
+	return a
+}
+
+func articleIDs(list []domain.Article) (res []int64) {
+	for _, a := range list {
+		res = append(res, a.ID)
+	}
+	return
+}
+
+func testAuthor(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	first := storeAuthor(t, repos, "Iman Tumorang", 1)
+	second := storeAuthor(t, repos, "Rachadian", 2)
+	third := storeAuthor(t, repos, "Novansyah", 3)
+
+	res, err := repos.Author.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Iman Tumorang", res.Name)
+	assert.True(t, first.CreatedAt.Equal(res.CreatedAt))
+
+	_, err = repos.Author.GetByID(ctx, third.ID+100)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+
+	require.NoError(t, repos.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		return repos.Author.Lock(ctx, first.ID)
+	}))
+	assert.Equal(t, errHandle.ErrNotFound, repos.Author.Lock(ctx, third.ID+100))
+
+	byID, err := repos.Author.GetByIDs(ctx, []int64{first.ID, third.ID, third.ID + 100})
+	require.NoError(t, err)
+	assert.Len(t, byID, 2)
+	assert.Equal(t, "Novansyah", byID[third.ID].Name)
+
+	page, cursors, err := repos.Author.Fetch(ctx, domain.Page{Num: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, []int64{first.ID, second.ID}, []int64{page[0].ID, page[1].ID})
+	require.NotEmpty(t, cursors.Next)
+
+	page, _, err = repos.Author.Fetch(ctx, domain.Page{Cursor: cursors.Next, Num: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, third.ID, page[0].ID)
+
+	second.Name = "Rachadian Novansyah"
+	second.UpdatedAt = at(10)
+	require.NoError(t, repos.Author.Update(ctx, &second))
+	res, err = repos.Author.GetByID(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Rachadian Novansyah", res.Name)
+
+	require.NoError(t, repos.Author.Delete(ctx, second.ID))
+	_, err = repos.Author.GetByID(ctx, second.ID)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+	assert.Equal(t, errHandle.ErrNotFound, repos.Author.Delete(ctx, second.ID))
+}
+
+func testArticle(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	author := storeAuthor(t, repos, "Iman Tumorang", 0)
+	first := storeArticle(t, repos, "Makan Ayam", author.ID, 1)
+	second := storeArticle(t, repos, "Makan Ikan", author.ID, 2)
+	third := storeArticle(t, repos, "Makan Sapi", author.ID, 3)
+
+	res, err := repos.Article.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.Title, res.Title)
+	assert.Equal(t, first.Content, res.Content)
+	assert.Equal(t, author.ID, res.Author.ID)
+	assert.Equal(t, int64(1), res.Version)
+	assert.True(t, first.CreatedAt.Equal(res.CreatedAt))
+
+	res, err = repos.Article.GetByTitle(ctx, "Makan Ikan")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, res.ID)
+
+	_, err = repos.Article.GetByID(ctx, third.ID+100)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+	_, err = repos.Article.GetByTitle(ctx, "Makan Kambing")
+	assert.Equal(t, errHandle.ErrNotFound, err)
+
+	t.Run("cursor", func(t *testing.T) {
+		page, cursors, err := repos.Article.Fetch(ctx, domain.ArticleFilter{}, domain.Page{Num: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{first.ID, second.ID}, articleIDs(page))
+		require.NotEmpty(t, cursors.Next)
+
+		page, cursors, err = repos.Article.Fetch(ctx, domain.ArticleFilter{}, domain.Page{Cursor: cursors.Next, Num: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{third.ID}, articleIDs(page))
+		assert.Empty(t, cursors.Next)
+
+		_, _, err = repos.Article.Fetch(ctx, domain.ArticleFilter{}, domain.Page{Cursor: "not a cursor", Num: 2})
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		ar, err := repos.Article.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		stale := ar
+
+		ar.Title = "Makan Ayam Goreng"
+		ar.UpdatedAt = at(10)
+		require.NoError(t, repos.Article.Update(ctx, &ar))
+		assert.Equal(t, int64(2), ar.Version)
+
+		res, err := repos.Article.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Makan Ayam Goreng", res.Title)
+		assert.Equal(t, int64(2), res.Version)
+
+		stale.Title = "Makan Ayam Bakar"
+		assert.Equal(t, errHandle.ErrPreconditionFailed, repos.Article.Update(ctx, &stale))
+
+		missing := domain.Article{ID: third.ID + 100, Title: "Missing", Version: 1}
+		assert.Equal(t, errHandle.ErrNotFound, repos.Article.Update(ctx, &missing))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Equal(t, errHandle.ErrPreconditionFailed, repos.Article.Delete(ctx, third.ID, 2, at(20)))
+		require.NoError(t, repos.Article.Delete(ctx, third.ID, 1, at(20)))
+		_, err := repos.Article.GetByID(ctx, third.ID)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		assert.Equal(t, errHandle.ErrNotFound, repos.Article.Delete(ctx, third.ID, 1, at(21)))
+		assert.Equal(t, errHandle.ErrNotFound, repos.Article.Delete(ctx, third.ID+100, 1, at(21)))
+	})
+}
+
+func testArticleFetchBy(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	iman := storeAuthor(t, repos, "Iman Tumorang", 0)
+	rachadian := storeAuthor(t, repos, "Rachadian", 0)
+	first := storeArticle(t, repos, "Makan Ayam", iman.ID, 1)
+	second := storeArticle(t, repos, "Makan Ikan", rachadian.ID, 2)
+	third := storeArticle(t, repos, "Makan Sapi", iman.ID, 3)
+
+	food := domain.Category{Name: "Food", Tag: "food", CreatedAt: at(0), UpdatedAt: at(0)}
+	require.NoError(t, repos.Category.Store(ctx, &food))
+	require.NoError(t, repos.Category.AddArticle(ctx, second.ID, food.ID))
+	require.NoError(t, repos.Category.AddArticle(ctx, third.ID, food.ID))
+
+	page, cursors, err := repos.Article.FetchByAuthor(ctx, iman.ID, "", domain.Page{Num: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{first.ID}, articleIDs(page))
+	page, _, err = repos.Article.FetchByAuthor(ctx, iman.ID, "", domain.Page{Cursor: cursors.Next, Num: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{third.ID}, articleIDs(page))
+
+	page, cursors, err = repos.Article.FetchByCategory(ctx, food.ID, "", domain.Page{Num: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{second.ID}, articleIDs(page))
+	page, _, err = repos.Article.FetchByCategory(ctx, food.ID, "", domain.Page{Cursor: cursors.Next, Num: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{third.ID}, articleIDs(page))
+
+	page, _, err = repos.Article.FetchByCategory(ctx, food.ID+100, "", domain.Page{Num: 5})
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
+
+func testArticleStatus(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	author := storeAuthor(t, repos, "Iman Tumorang", 0)
+	draft := storeArticle(t, repos, "Makan Ayam", author.ID, 1)
+	published := storeArticle(t, repos, "Makan Ikan", author.ID, 2)
+
+	food := domain.Category{Name: "Food", Tag: "food", CreatedAt: at(0), UpdatedAt: at(0)}
+	require.NoError(t, repos.Category.Store(ctx, &food))
+	require.NoError(t, repos.Category.AddArticle(ctx, draft.ID, food.ID))
+	require.NoError(t, repos.Category.AddArticle(ctx, published.ID, food.ID))
+
+	res, err := repos.Article.GetByID(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusDraft, res.Status)
+	assert.Nil(t, res.PublishedAt)
+
+	ar, err := repos.Article.GetByID(ctx, published.ID)
+	require.NoError(t, err)
+	stale := ar
+	publishedAt := at(10)
+	ar.Status, ar.PublishedAt, ar.UpdatedAt = domain.StatusPublished, &publishedAt, at(10)
+	require.NoError(t, repos.Article.UpdateStatus(ctx, &ar))
+	assert.Equal(t, int64(2), ar.Version)
+	assert.Equal(t, errHandle.ErrPreconditionFailed, repos.Article.UpdateStatus(ctx, &stale))
+
+	res, err = repos.Article.GetByID(ctx, published.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, res.Status)
+	require.NotNil(t, res.PublishedAt)
+	assert.True(t, publishedAt.Equal(*res.PublishedAt))
+	assert.Equal(t, published.Title, res.Title, "the content is left alone")
+
+	page, _, err := repos.Article.Fetch(ctx, domain.ArticleFilter{Status: domain.StatusPublished}, domain.Page{Num: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{published.ID}, articleIDs(page))
+	page, _, err = repos.Article.Fetch(ctx, domain.ArticleFilter{}, domain.Page{Num: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{draft.ID, published.ID}, articleIDs(page))
+
+	page, _, err = repos.Article.FetchByAuthor(ctx, author.ID, domain.StatusDraft, domain.Page{Num: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{draft.ID}, articleIDs(page))
+	page, _, err = repos.Article.FetchByCategory(ctx, food.ID, domain.StatusPublished, domain.Page{Num: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{published.ID}, articleIDs(page))
+
+	missing := domain.Article{ID: published.ID + 100, Status: domain.StatusArchived, Version: 1}
+	assert.Equal(t, errHandle.ErrNotFound, repos.Article.UpdateStatus(ctx, &missing))
+}
+
+func testArticleDue(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	author := storeAuthor(t, repos, "Iman Tumorang", 0)
+	schedule := func(title string, i int, status domain.ArticleStatus, publishAt time.Time) domain.Article {
+		ar, err := repos.Article.GetByID(ctx, storeArticle(t, repos, title, author.ID, i).ID)
+		require.NoError(t, err)
+		ar.Status, ar.PublishAt = status, &publishAt
+		require.NoError(t, repos.Article.UpdateStatus(ctx, &ar))
+		return ar
+	}
+	later := schedule("Makan Ayam", 1, domain.StatusDraft, at(30))
+	second := schedule("Makan Ikan", 2, domain.StatusInReview, at(20))
+	first := schedule("Makan Sapi", 3, domain.StatusDraft, at(10))
+	schedule("Makan Tahu", 4, domain.StatusArchived, at(10))
+	storeArticle(t, repos, "Makan Tempe", author.ID, 5)
+
+	due, err := repos.Article.FetchDue(ctx, at(20), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{first.ID, second.ID}, articleIDs(due))
+	require.NotNil(t, due[0].PublishAt)
+	assert.True(t, at(10).Equal(*due[0].PublishAt))
+
+	due, err = repos.Article.FetchDue(ctx, at(40), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{first.ID}, articleIDs(due))
+
+	res, err := repos.Article.GetByID(ctx, later.ID)
+	require.NoError(t, err)
+	require.NotNil(t, res.PublishAt)
+	assert.True(t, at(30).Equal(*res.PublishAt))
+}
+
+func testArticleRevision(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	author := storeAuthor(t, repos, "Iman Tumorang", 0)
+	ar := storeArticle(t, repos, "Makan Ayam", author.ID, 1)
+	other := storeArticle(t, repos, "Makan Ikan", author.ID, 2)
+
+	store := func(articleID int64, title string, userID int64, i int) domain.ArticleRevision {
+		r := domain.ArticleRevision{ArticleID: articleID, Title: title, Content: "content of " + title, UserID: userID, CreatedAt: at(i)}
+		require.NoError(t, repos.Revision.Store(ctx, &r))
+		require.NotZero(t, r.ID)
+		return r
+	}
+	first := store(ar.ID, "Makan Ayam", 1, 1)
+	store(other.ID, "Makan Ikan", 0, 2)
+	second := store(ar.ID, "Makan Ayam Goreng", 0, 3)
+	third := store(ar.ID, "Makan Ayam Bakar", 2, 4)
+	assert.Equal(t, []int64{1, 2, 3}, []int64{first.Number, second.Number, third.Number}, "numbered per article")
+
+	res, err := repos.Revision.GetByNumber(ctx, ar.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, res.ID)
+	assert.Equal(t, "Makan Ayam Goreng", res.Title)
+	assert.Equal(t, "content of Makan Ayam Goreng", res.Content)
+	assert.Equal(t, int64(0), res.UserID)
+	assert.True(t, at(3).Equal(res.CreatedAt))
+	_, err = repos.Revision.GetByNumber(ctx, ar.ID, 4)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+
+	page, cursors, err := repos.Revision.FetchByArticle(ctx, ar.ID, domain.Page{Num: 2, Sort: domain.SortDesc})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, []int64{3, 2}, []int64{page[0].Number, page[1].Number})
+	assert.Equal(t, int64(2), page[0].UserID)
+	page, _, err = repos.Revision.FetchByArticle(ctx, ar.ID, domain.Page{Num: 2, Sort: domain.SortDesc, Cursor: cursors.Next})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, first.ID, page[0].ID)
+}
+
+func testArticleTrash(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	iman := storeAuthor(t, repos, "Iman Tumorang", 0)
+	rachadian := storeAuthor(t, repos, "Rachadian", 0)
+	kept := storeArticle(t, repos, "Makan Ayam", iman.ID, 1)
+	first := storeArticle(t, repos, "Makan Ikan", iman.ID, 2)
+	second := storeArticle(t, repos, "Makan Sapi", rachadian.ID, 3)
+	third := storeArticle(t, repos, "Makan Tahu", iman.ID, 4)
+
+	food := domain.Category{Name: "Food", Tag: "food", CreatedAt: at(0), UpdatedAt: at(0)}
+	require.NoError(t, repos.Category.Store(ctx, &food))
+	for _, ar := range []domain.Article{kept, first, second, third} {
+		require.NoError(t, repos.Category.AddArticle(ctx, ar.ID, food.ID))
+		r := domain.ArticleRevision{ArticleID: ar.ID, Title: ar.Title, Content: ar.Content, CreatedAt: ar.CreatedAt}
+		require.NoError(t, repos.Revision.Store(ctx, &r))
+	}
+
+	require.NoError(t, repos.Article.Delete(ctx, first.ID, 1, at(10)))
+	require.NoError(t, repos.Article.Delete(ctx, second.ID, 1, at(20)))
+	require.NoError(t, repos.Article.Delete(ctx, third.ID, 1, at(30)))
+
+	t.Run("hidden", func(t *testing.T) {
+		page, _, err := repos.Article.Fetch(ctx, domain.ArticleFilter{}, domain.Page{Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{kept.ID}, articleIDs(page))
+		page, _, err = repos.Article.FetchByAuthor(ctx, iman.ID, "", domain.Page{Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{kept.ID}, articleIDs(page))
+		page, _, err = repos.Article.FetchByCategory(ctx, food.ID, "", domain.Page{Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{kept.ID}, articleIDs(page))
+
+		_, err = repos.Article.GetByTitle(ctx, "Makan Ikan")
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		ar := first
+		ar.Version = 1
+		assert.Equal(t, errHandle.ErrNotFound, repos.Article.Update(ctx, &ar))
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		page, cursors, err := repos.Article.FetchTrash(ctx, 0, domain.Page{Num: 2, Sort: domain.SortDesc})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{third.ID, second.ID}, articleIDs(page), "latest trashed first")
+		require.NotNil(t, page[0].DeletedAt)
+		assert.True(t, at(30).Equal(*page[0].DeletedAt))
+		page, _, err = repos.Article.FetchTrash(ctx, 0, domain.Page{Num: 2, Sort: domain.SortDesc, Cursor: cursors.Next})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{first.ID}, articleIDs(page))
+
+		page, _, err = repos.Article.FetchTrash(ctx, iman.ID, domain.Page{Num: 10, Sort: domain.SortDesc})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{third.ID, first.ID}, articleIDs(page))
+
+		res, err := repos.Article.GetTrashed(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Makan Sapi", res.Title)
+		_, err = repos.Article.GetTrashed(ctx, kept.ID)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
+
+	t.Run("counted", func(t *testing.T) {
+		n, err := repos.Article.CountByAuthor(ctx, iman.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+		n, err = repos.Article.CountByAuthor(ctx, rachadian.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n, "only has an article in the trash")
+		n, err = repos.Article.CountByAuthor(ctx, rachadian.ID+100)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		assert.Equal(t, errHandle.ErrPreconditionFailed, repos.Article.Restore(ctx, third.ID, 2))
+		_, err := repos.Article.GetTrashed(ctx, third.ID)
+		require.NoError(t, err, "left in the trash at another version")
+
+		require.NoError(t, repos.Article.Restore(ctx, third.ID, 1))
+		res, err := repos.Article.GetByID(ctx, third.ID)
+		require.NoError(t, err)
+		assert.Nil(t, res.DeletedAt)
+		assert.Equal(t, errHandle.ErrNotFound, repos.Article.Restore(ctx, third.ID, 1))
+		assert.Equal(t, errHandle.ErrNotFound, repos.Article.Restore(ctx, third.ID+100, 1))
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		n, err := repos.Article.Purge(ctx, at(20))
+		require.NoError(t, err)
+		assert.Equal(t, 1, n, "trashed before the given time only")
+
+		_, err = repos.Article.GetTrashed(ctx, first.ID)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		_, err = repos.Article.GetTrashed(ctx, second.ID)
+		assert.NoError(t, err)
+
+		_, err = repos.Revision.GetByNumber(ctx, first.ID, 1)
+		assert.Equal(t, errHandle.ErrNotFound, err, "revisions purged along")
+		_, err = repos.Revision.GetByNumber(ctx, second.ID, 1)
+		assert.NoError(t, err)
+
+		categories, err := repos.Category.GetByArticleIDs(ctx, []int64{first.ID, second.ID})
+		require.NoError(t, err)
+		assert.Empty(t, categories[first.ID], "category links purged along")
+		assert.Len(t, categories[second.ID], 1)
+	})
+}
+
+func testArticleSlug(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	iman := storeAuthor(t, repos, "Iman Tumorang", 0)
+	first := storeArticle(t, repos, "Kopi", iman.ID, 1)
+	second := storeArticle(t, repos, "Kopi!", iman.ID, 2)
+	other := storeArticle(t, repos, "Kopi Susu", iman.ID, 3)
+
+	t.Run("without-slug", func(t *testing.T) {
+		list, err := repos.Article.FetchWithoutSlug(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{first.ID, second.ID}, articleIDs(list))
+	})
+
+	require.NoError(t, repos.Article.SetSlug(ctx, first.ID, "kopi", at(1)))
+	require.NoError(t, repos.Article.SetSlug(ctx, second.ID, "kopi-2", at(2)))
+	require.NoError(t, repos.Article.SetSlug(ctx, other.ID, "kopi-susu", at(3)))
+
+	t.Run("set", func(t *testing.T) {
+		res, err := repos.Article.GetByID(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "kopi-2", res.Slug)
+
+		list, err := repos.Article.FetchWithoutSlug(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+
+		assert.Equal(t, errHandle.ErrConflict, repos.Article.SetSlug(ctx, second.ID, "kopi", at(4)))
+		assert.NoError(t, repos.Article.SetSlug(ctx, second.ID, "kopi-2", at(4)), "its own slug")
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		require.NoError(t, repos.Article.SetSlug(ctx, first.ID, "teh", at(5)))
+
+		res, err := repos.Article.GetBySlug(ctx, "kopi")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, res.ID, "old slugs still lead to the article")
+		assert.Equal(t, "teh", res.Slug)
+		res, err = repos.Article.GetBySlug(ctx, "teh")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, res.ID)
+		_, err = repos.Article.GetBySlug(ctx, "kopi-3")
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		taken, err := repos.Article.FetchSlugs(ctx, "kopi")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, taken["kopi"])
+		assert.Equal(t, second.ID, taken["kopi-2"])
+		assert.NotContains(t, taken, "teh")
+	})
+
+	t.Run("taken-meanwhile", func(t *testing.T) {
+		rachadian := storeAuthor(t, repos, "Rachadian", 1)
+		mine := storeArticle(t, repos, "Es Teh", iman.ID, 6)
+		theirs := storeArticle(t, repos, "Es teh!", rachadian.ID, 7)
+
+		// both authors found es-teh free, the first to save it gets it
+		require.NoError(t, repos.Article.SetSlug(ctx, mine.ID, "es-teh", at(6)))
+		err := repos.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+			assert.Equal(t, errHandle.ErrConflict, repos.Article.SetSlug(ctx, theirs.ID, "es-teh", at(7)))
+			return repos.Article.SetSlug(ctx, theirs.ID, "es-teh-2", at(7))
+		})
+		require.NoError(t, err, "the transaction goes on with the next slug")
+
+		res, err := repos.Article.GetByID(ctx, theirs.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "es-teh-2", res.Slug)
+		res, err = repos.Article.GetBySlug(ctx, "es-teh")
+		require.NoError(t, err)
+		assert.Equal(t, mine.ID, res.ID)
+	})
+
+	t.Run("trash", func(t *testing.T) {
+		require.NoError(t, repos.Article.Delete(ctx, second.ID, 1, at(10)))
+		_, err := repos.Article.GetBySlug(ctx, "kopi-2")
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		assert.Equal(t, errHandle.ErrConflict, repos.Article.SetSlug(ctx, other.ID, "kopi-2", at(11)), "kept while in the trash")
+
+		_, err = repos.Article.Purge(ctx, at(20))
+		require.NoError(t, err)
+		taken, err := repos.Article.FetchSlugs(ctx, "kopi")
+		require.NoError(t, err)
+		assert.NotContains(t, taken, "kopi-2", "purged along")
+	})
+}
+
+func testArticleTitle(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	author := storeAuthor(t, repos, "Iman Tumorang", 0)
+	first := storeArticle(t, repos, "Makan Ayam", author.ID, 1)
+	second := storeArticle(t, repos, "Makan Ikan", author.ID, 2)
+
+	t.Run("store", func(t *testing.T) {
+		ar := domain.Article{Title: "makan AYAM", Content: "again", Author: author, Status: domain.StatusDraft, CreatedAt: at(3), UpdatedAt: at(3)}
+		assert.Equal(t, errHandle.ErrConflict, repos.Article.Store(ctx, &ar), "regardless of case")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		ar := second
+		ar.Version = 1
+		ar.Title = "MAKAN AYAM"
+		assert.Equal(t, errHandle.ErrConflict, repos.Article.Update(ctx, &ar))
+		res, err := repos.Article.GetByID(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Makan Ikan", res.Title)
+		assert.Equal(t, int64(1), res.Version)
+
+		ar = first
+		ar.Version = 1
+		ar.Title = "Makan ayam"
+		assert.NoError(t, repos.Article.Update(ctx, &ar), "its own title")
+	})
+
+	t.Run("trash", func(t *testing.T) {
+		require.NoError(t, repos.Article.Delete(ctx, first.ID, 2, at(10)))
+		again := storeArticle(t, repos, "Makan Ayam", author.ID, 11)
+		assert.NotEqual(t, first.ID, again.ID, "a trashed title is free")
+
+		assert.Equal(t, errHandle.ErrConflict, repos.Article.Restore(ctx, first.ID, 2))
+		_, err := repos.Article.GetTrashed(ctx, first.ID)
+		assert.NoError(t, err, "left in the trash")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		// the stores are let go at once, racing on the backends with
+		// connections of their own
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ar := domain.Article{Title: "Makan Bersama", Content: "content", Author: author, Status: domain.StatusDraft, CreatedAt: at(20 + i), UpdatedAt: at(20 + i)}
+				<-start
+				errs <- repos.Article.Store(ctx, &ar)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+		close(errs)
+
+		stored := 0
+		for err := range errs {
+			if err == nil {
+				stored++
+				continue
+			}
+			assert.Equal(t, errHandle.ErrConflict, err)
+		}
+		assert.Equal(t, 1, stored)
+	})
+}
+
+func testPagination(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	author := storeAuthor(t, repos, "Iman Tumorang", 0)
+	// three of the articles share their creation time
+	ids := []int64{
+		storeArticle(t, repos, "Makan Ayam", author.ID, 1).ID,
+		storeArticle(t, repos, "Makan Ikan", author.ID, 2).ID,
+		storeArticle(t, repos, "Makan Sapi", author.ID, 2).ID,
+		storeArticle(t, repos, "Makan Kambing", author.ID, 2).ID,
+		storeArticle(t, repos, "Makan Bebek", author.ID, 3).ID,
+	}
+
+	// walk forward to the end, then backward to the start
+	walk := func(t *testing.T, sort domain.SortDirection) (forward, backward [][]int64) {
+		page, cursors, err := repos.Article.FetchByAuthor(ctx, author.ID, "", domain.Page{Num: 2, Sort: sort})
+		require.NoError(t, err)
+		assert.Empty(t, cursors.Prev)
+		forward = append(forward, articleIDs(page))
+		for cursors.Next != "" {
+			page, cursors, err = repos.Article.FetchByAuthor(ctx, author.ID, "", domain.Page{Cursor: cursors.Next, Num: 2, Sort: sort})
+			require.NoError(t, err)
+			forward = append(forward, articleIDs(page))
+		}
+		backward = append(backward, forward[len(forward)-1])
+		for cursors.Prev != "" {
+			page, cursors, err = repos.Article.FetchByAuthor(ctx, author.ID, "", domain.Page{Cursor: cursors.Prev, Num: 2, Sort: sort})
+			require.NoError(t, err)
+			backward = append([][]int64{articleIDs(page)}, backward...)
+		}
+		return
+	}
+
+	t.Run("ascending", func(t *testing.T) {
+		forward, backward := walk(t, domain.SortAsc)
+		assert.Equal(t, [][]int64{ids[0:2], ids[2:4], ids[4:5]}, forward)
+		assert.Equal(t, forward, backward)
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		forward, backward := walk(t, domain.SortDesc)
+		assert.Equal(t, [][]int64{{ids[4], ids[3]}, {ids[2], ids[1]}, {ids[0]}}, forward)
+		assert.Equal(t, forward, backward)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := repos.Article.Fetch(ctx, domain.ArticleFilter{}, domain.Page{Num: 2, Sort: "sideways"})
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		_, _, err = repos.Article.Fetch(ctx, domain.ArticleFilter{}, domain.Page{Num: -1})
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+}
+
+func testArticleFilter(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	iman := storeAuthor(t, repos, "Iman Tumorang", 0)
+	rachadian := storeAuthor(t, repos, "Rachadian", 0)
+	store := func(title, content string, authorID int64, i int) int64 {
+		a := domain.Article{Title: title, Content: content, Author: domain.Author{ID: authorID}, Status: domain.StatusDraft, CreatedAt: at(i), UpdatedAt: at(i)}
+		require.NoError(t, repos.Article.Store(ctx, &a))
+		return a.ID
+	}
+	gophers := store("Gophers united", "gopher gopher gopher", iman.ID, 1)
+	crabs := store("Rustaceans", "crab crab", rachadian.ID, 2)
+	notes := store("Gopher notes", "a gopher note", iman.ID, 3)
+	cooking := store("Cooking", "recipes with gopher", rachadian.ID, 4)
+
+	tech := domain.Category{Name: "Tech", Tag: "tech", CreatedAt: at(0), UpdatedAt: at(0)}
+	require.NoError(t, repos.Category.Store(ctx, &tech))
+	require.NoError(t, repos.Category.AddArticle(ctx, gophers, tech.ID))
+	require.NoError(t, repos.Category.AddArticle(ctx, cooking, tech.ID))
+
+	fetch := func(t *testing.T, filter domain.ArticleFilter, sort domain.SortDirection) []int64 {
+		page, _, err := repos.Article.Fetch(ctx, filter, domain.Page{Num: 10, Sort: sort})
+		require.NoError(t, err)
+		return articleIDs(page)
+	}
+
+	t.Run("author", func(t *testing.T) {
+		assert.Equal(t, []int64{gophers, notes}, fetch(t, domain.ArticleFilter{AuthorID: iman.ID}, ""))
+	})
+
+	t.Run("category", func(t *testing.T) {
+		assert.Equal(t, []int64{gophers, cooking}, fetch(t, domain.ArticleFilter{Category: "tech"}, ""))
+		assert.Empty(t, fetch(t, domain.ArticleFilter{Category: "sport"}, ""))
+	})
+
+	t.Run("created", func(t *testing.T) {
+		filter := domain.ArticleFilter{CreatedAfter: at(2), CreatedBefore: at(4)}
+		assert.Equal(t, []int64{crabs, notes}, fetch(t, filter, ""))
+	})
+
+	t.Run("search", func(t *testing.T) {
+		filter := domain.ArticleFilter{Query: "gopher"}
+		assert.Equal(t, []int64{cooking, notes, gophers}, fetch(t, filter, domain.SortDesc))
+		assert.Equal(t, []int64{gophers, notes, cooking}, fetch(t, filter, domain.SortRelevance))
+		assert.Len(t, fetch(t, domain.ArticleFilter{Query: "crab gopher"}, domain.SortRelevance), 4)
+		assert.Equal(t, []int64{notes}, fetch(t, domain.ArticleFilter{Query: "gopher", AuthorID: iman.ID, CreatedAfter: at(2)}, ""))
+	})
+
+	t.Run("relevance-pages", func(t *testing.T) {
+		filter := domain.ArticleFilter{Query: "gopher"}
+		page, cursors, err := repos.Article.Fetch(ctx, filter, domain.Page{Num: 2, Sort: domain.SortRelevance})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{gophers, notes}, articleIDs(page))
+
+		page, cursors, err = repos.Article.Fetch(ctx, filter, domain.Page{Cursor: cursors.Next, Num: 2, Sort: domain.SortRelevance})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{cooking}, articleIDs(page))
+		assert.Empty(t, cursors.Next)
+
+		page, _, err = repos.Article.Fetch(ctx, filter, domain.Page{Cursor: cursors.Prev, Num: 2, Sort: domain.SortRelevance})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{gophers, notes}, articleIDs(page))
+	})
+}
+
+func testCategory(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	food := domain.Category{Name: "Food", Tag: "food", CreatedAt: at(1), UpdatedAt: at(1)}
+	require.NoError(t, repos.Category.Store(ctx, &food))
+	drink := domain.Category{Name: "Drink", Tag: "drink", CreatedAt: at(2), UpdatedAt: at(2)}
+	require.NoError(t, repos.Category.Store(ctx, &drink))
+
+	list, err := repos.Category.Fetch(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "Drink", list[0].Name)
+
+	res, err := repos.Category.GetByTag(ctx, "food")
+	require.NoError(t, err)
+	assert.Equal(t, food.ID, res.ID)
+	_, err = repos.Category.GetByTag(ctx, "music")
+	assert.Equal(t, errHandle.ErrNotFound, err)
+	foods := domain.Category{Name: "Foods", Tag: "food", CreatedAt: at(2), UpdatedAt: at(2)}
+	assert.Equal(t, errHandle.ErrConflict, repos.Category.Store(ctx, &foods))
+	drink.Tag = "food"
+	assert.Equal(t, errHandle.ErrConflict, repos.Category.Update(ctx, &drink))
+	drink.Tag = "drink"
+
+	article := storeArticle(t, repos, "Makan Ayam", 0, 3)
+	require.NoError(t, repos.Category.AddArticle(ctx, article.ID, food.ID))
+	require.NoError(t, repos.Category.AddArticle(ctx, article.ID, drink.ID))
+	assert.Equal(t, errHandle.ErrConflict, repos.Category.AddArticle(ctx, article.ID, food.ID))
+
+	byArticle, err := repos.Category.GetByArticleIDs(ctx, []int64{article.ID, article.ID + 100})
+	require.NoError(t, err)
+	require.Len(t, byArticle[article.ID], 2)
+	assert.Equal(t, "Drink", byArticle[article.ID][0].Name)
+	assert.Empty(t, byArticle[article.ID+100])
+
+	require.NoError(t, repos.Category.RemoveArticle(ctx, article.ID, drink.ID))
+	assert.Equal(t, errHandle.ErrNotFound, repos.Category.RemoveArticle(ctx, article.ID, drink.ID))
+
+	food.Name = "Foods"
+	food.UpdatedAt = at(4)
+	require.NoError(t, repos.Category.Update(ctx, &food))
+	res, err = repos.Category.GetByID(ctx, food.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Foods", res.Name)
+
+	require.NoError(t, repos.Category.Delete(ctx, food.ID))
+	byArticle, err = repos.Category.GetByArticleIDs(ctx, []int64{article.ID})
+	require.NoError(t, err)
+	assert.Empty(t, byArticle[article.ID])
+	assert.Equal(t, errHandle.ErrNotFound, repos.Category.Delete(ctx, food.ID))
+}
+
+func testUser(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	author := storeAuthor(t, repos, "Iman Tumorang", 0)
+	iman := domain.User{
+		Fullname:  "Iman Tumorang",
+		Username:  "iman",
+		Email:     "iman@example.com",
+		Password:  "hash",
+		AuthorID:  author.ID,
+		Roles:     []domain.Role{domain.RoleAuthor},
+		CreatedAt: at(1),
+		UpdatedAt: at(1),
+	}
+	require.NoError(t, repos.User.Store(ctx, &iman))
+	require.NotZero(t, iman.ID)
+	rachadian := domain.User{
+		Username:  "rachadian",
+		Email:     "rachadian@example.com",
+		Password:  "hash",
+		CreatedAt: at(2),
+		UpdatedAt: at(2),
+	}
+	require.NoError(t, repos.User.Store(ctx, &rachadian))
+
+	res, err := repos.User.GetByEmail(ctx, "iman@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, iman.ID, res.ID)
+	assert.Equal(t, author.ID, res.AuthorID)
+	assert.Equal(t, "hash", res.Password)
+	assert.Equal(t, []domain.Role{domain.RoleAuthor}, res.Roles)
+
+	res, err = repos.User.GetByUsername(ctx, "rachadian")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.AuthorID)
+	assert.Empty(t, res.Roles)
+
+	res, err = repos.User.GetByAuthorID(ctx, author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, iman.ID, res.ID)
+	_, err = repos.User.GetByAuthorID(ctx, author.ID+100)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+
+	_, err = repos.User.GetByID(ctx, rachadian.ID+100)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+
+	duplicate := domain.User{Username: "iman", Email: "another@example.com", Password: "hash", CreatedAt: at(3), UpdatedAt: at(3)}
+	assert.Equal(t, errHandle.ErrConflict, repos.User.Store(ctx, &duplicate))
+
+	require.NoError(t, repos.User.AddRole(ctx, iman.ID, domain.RoleEditor))
+	assert.Equal(t, errHandle.ErrConflict, repos.User.AddRole(ctx, iman.ID, domain.RoleEditor))
+	res, err = repos.User.GetByID(ctx, iman.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Role{domain.RoleAuthor, domain.RoleEditor}, res.Roles)
+
+	require.NoError(t, repos.User.RemoveRole(ctx, iman.ID, domain.RoleAuthor))
+	assert.Equal(t, errHandle.ErrNotFound, repos.User.RemoveRole(ctx, iman.ID, domain.RoleAuthor))
+
+	page, cursors, err := repos.User.Fetch(ctx, domain.Page{Num: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, iman.ID, page[0].ID)
+	assert.Equal(t, []domain.Role{domain.RoleEditor}, page[0].Roles)
+	page, _, err = repos.User.Fetch(ctx, domain.Page{Cursor: cursors.Next, Num: 5})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, rachadian.ID, page[0].ID)
+}
+
+func testRefreshToken(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	first := domain.RefreshToken{UserID: 1, TokenHash: "first", ExpiresAt: at(60), CreatedAt: at(1)}
+	require.NoError(t, repos.RefreshToken.Store(ctx, &first))
+	require.NotZero(t, first.ID)
+	second := domain.RefreshToken{UserID: 1, TokenHash: "second", ExpiresAt: at(60), CreatedAt: at(2)}
+	require.NoError(t, repos.RefreshToken.Store(ctx, &second))
+
+	res, err := repos.RefreshToken.GetByHash(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, res.ID)
+	assert.True(t, first.ExpiresAt.Equal(res.ExpiresAt))
+	assert.Nil(t, res.RevokedAt)
+
+	_, err = repos.RefreshToken.GetByHash(ctx, "missing")
+	assert.Equal(t, errHandle.ErrNotFound, err)
+
+	require.NoError(t, repos.RefreshToken.Revoke(ctx, first.ID, at(5)))
+	assert.Equal(t, errHandle.ErrNotFound, repos.RefreshToken.Revoke(ctx, first.ID, at(6)))
+	res, err = repos.RefreshToken.GetByHash(ctx, "first")
+	require.NoError(t, err)
+	require.NotNil(t, res.RevokedAt)
+	assert.True(t, at(5).Equal(*res.RevokedAt))
+
+	require.NoError(t, repos.RefreshToken.RevokeByUser(ctx, 1, at(7)))
+	res, err = repos.RefreshToken.GetByHash(ctx, "second")
+	require.NoError(t, err)
+	require.NotNil(t, res.RevokedAt)
+}
+
+func testTransaction(t *testing.T, repos Repositories) {
+	ctx := context.TODO()
+	category := domain.Category{Name: "Food", Tag: "food", CreatedAt: at(0), UpdatedAt: at(0)}
+	require.NoError(t, repos.Category.Store(ctx, &category))
+
+	t.Run("commit", func(t *testing.T) {
+		var article domain.Article
+		err := repos.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+			author := domain.Author{Name: "Committed", CreatedAt: at(1), UpdatedAt: at(1)}
+			if err := repos.Author.Store(ctx, &author); err != nil {
+				return err
+			}
+			article = domain.Article{Title: "committed", Author: author, CreatedAt: at(1), UpdatedAt: at(1)}
+			if err := repos.Article.Store(ctx, &article); err != nil {
+				return err
+			}
+			return repos.Category.AddArticle(ctx, article.ID, category.ID)
+		})
+		require.NoError(t, err)
+
+		stored, err := repos.Article.GetByID(ctx, article.ID)
+		require.NoError(t, err)
+		_, err = repos.Author.GetByID(ctx, stored.Author.ID)
+		assert.NoError(t, err)
+		list, _, err := repos.Article.FetchByCategory(ctx, category.ID, "", domain.Page{Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{article.ID}, articleIDs(list))
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		failure := errors.New("failure")
+		var authorID, articleID int64
+		err := repos.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+			author := domain.Author{Name: "Rolled back", CreatedAt: at(2), UpdatedAt: at(2)}
+			if err := repos.Author.Store(ctx, &author); err != nil {
+				return err
+			}
+			authorID = author.ID
+			article := domain.Article{Title: "rolled back", Author: author, CreatedAt: at(2), UpdatedAt: at(2)}
+			if err := repos.Article.Store(ctx, &article); err != nil {
+				return err
+			}
+			articleID = article.ID
+			if err := repos.Category.AddArticle(ctx, article.ID, category.ID); err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.Equal(t, failure, err)
+
+		_, err = repos.Author.GetByID(ctx, authorID)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		_, err = repos.Article.GetByID(ctx, articleID)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		list, _, err := repos.Article.FetchByCategory(ctx, category.ID, "", domain.Page{Num: 10})
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("nested-joins-outer", func(t *testing.T) {
+		failure := errors.New("failure")
+		var authorID int64
+		err := repos.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+			err := repos.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+				author := domain.Author{Name: "Inner", CreatedAt: at(3), UpdatedAt: at(3)}
+				err := repos.Author.Store(ctx, &author)
+				authorID = author.ID
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.Equal(t, failure, err)
+
+		_, err = repos.Author.GetByID(ctx, authorID)
+		assert.Equal(t, errHandle.ErrNotFound, err, "the inner unit of work is rolled back with the outer one")
+	})
+}
@@ -0,0 +1,53 @@
+package repotest_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/migration"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/repotest"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	_articleRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/sqlite"
+	_authRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/sqlite"
+	_authorRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/sqlite"
+	_categoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/sqlite"
+	_userRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/sqlite"
+)
+
+// migrate applies the embedded migrations of driver, so the suite also checks
+// they produce the schema the repositories expect
+func migrate(t *testing.T, db *sql.DB, driver string) {
+	fsys, err := migration.Source(driver)
+	require.NoError(t, err)
+	migrations, err := migration.Load(fsys)
+	require.NoError(t, err)
+	_, err = migration.New(db, driver, migrations).Up(context.TODO())
+	require.NoError(t, err)
+}
+
+func TestSqlite(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "test.db")+"?_busy_timeout=5000")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+
+		migrate(t, db, "sqlite")
+		repotest.Truncate(t, db)
+
+		return repotest.Repositories{
+			Article:      _articleRepo.NewSqliteArticleRepository(db),
+			Revision:     _articleRepo.NewSqliteArticleRevisionRepository(db),
+			Author:       _authorRepo.NewSqliteAuthorRepository(db),
+			Category:     _categoryRepo.NewSqliteCategoryRepository(db),
+			User:         _userRepo.NewSqliteUserRepository(db),
+			RefreshToken: _authRepo.NewSqliteRefreshTokenRepository(db),
+			Transactor:   transaction.NewSQLTransactor(db),
+		}
+	})
+}
@@ -0,0 +1,132 @@
+// Package transaction lets the SQL repositories share a transaction opened by
+// a usecase, by carrying the *sql.Tx in the context.
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+)
+
+const (
+	// mysqlErrDeadlock is the MySQL error number of a transaction chosen as deadlock victim
+	mysqlErrDeadlock = 1213
+	// mysqlErrDuplicateEntry is the MySQL error number for unique key violations
+	mysqlErrDuplicateEntry = 1062
+	// pgErrDeadlock is the PostgreSQL error code of a detected deadlock
+	pgErrDeadlock = "40P01"
+	// pgErrUniqueViolation is the PostgreSQL error code for unique key violations
+	pgErrUniqueViolation = "23505"
+)
+
+// DBTX is what *sql.DB and *sql.Tx have in common for the repositories
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// Conn returns the transaction carried by ctx, or db when there is none
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// WithinTx runs fn in the transaction carried by ctx, or in a new transaction
+// on db committed when fn succeeds and rolled back otherwise
+func WithinTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				logrus.Error(errRollback)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+type sqlTransactor struct {
+	db          *sql.DB
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewSQLTransactor will create a domain.Transactor on db. A transaction failing
+// on a deadlock is run again from the start, up to three times in total.
+func NewSQLTransactor(db *sql.DB) domain.Transactor {
+	return &sqlTransactor{
+		db:          db,
+		maxAttempts: 3,
+		backoff:     20 * time.Millisecond,
+	}
+}
+
+func (t *sqlTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		// only the outermost transaction can be run again
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = WithinTx(ctx, t.db, fn)
+		if !IsDeadlock(err) || attempt == t.maxAttempts {
+			return
+		}
+
+		logrus.Warnf("transaction deadlocked, attempt %d of %d: %v", attempt, t.maxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * t.backoff):
+		}
+	}
+}
+
+// IsDeadlock reports whether err aborted the transaction to break a deadlock
+func IsDeadlock(err error) bool {
+	switch e := err.(type) {
+	case *mysql.MySQLError:
+		return e.Number == mysqlErrDeadlock
+	case *pq.Error:
+		return e.Code == pgErrDeadlock
+	}
+	return false
+}
+
+// IsUniqueViolation reports whether err is a unique or primary key constraint
+// failure
+func IsUniqueViolation(err error) bool {
+	switch e := err.(type) {
+	case *mysql.MySQLError:
+		return e.Number == mysqlErrDuplicateEntry
+	case *pq.Error:
+		return e.Code == pgErrUniqueViolation
+	case sqlite3.Error:
+		return e.ExtendedCode == sqlite3.ErrConstraintUnique || e.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
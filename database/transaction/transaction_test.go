@@ -0,0 +1,103 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+)
+
+func TestWithinTxRetriesDeadlock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE author").WillReturnError(deadlock)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE author").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	attempts := 0
+	err = transaction.NewSQLTransactor(db).WithinTx(context.TODO(), func(ctx context.Context) error {
+		attempts++
+		_, err := transaction.Conn(ctx, db).ExecContext(ctx, "UPDATE author SET name = ?", "Iman")
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTxGivesUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	for i := 0; i < 3; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE author").WillReturnError(deadlock)
+		mock.ExpectRollback()
+	}
+
+	attempts := 0
+	err = transaction.NewSQLTransactor(db).WithinTx(context.TODO(), func(ctx context.Context) error {
+		attempts++
+		_, err := transaction.Conn(ctx, db).ExecContext(ctx, "UPDATE author SET name = ?", "Iman")
+		return err
+	})
+
+	assert.Equal(t, deadlock, err)
+	assert.Equal(t, 3, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTxDoesNotRetryOtherErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO author").WillReturnError(duplicate)
+	mock.ExpectRollback()
+
+	attempts := 0
+	err = transaction.NewSQLTransactor(db).WithinTx(context.TODO(), func(ctx context.Context) error {
+		attempts++
+		_, err := transaction.Conn(ctx, db).ExecContext(ctx, "INSERT INTO author (name) VALUES (?)", "Iman")
+		return err
+	})
+
+	assert.Equal(t, duplicate, err)
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsDeadlock(t *testing.T) {
+	assert.True(t, transaction.IsDeadlock(&mysql.MySQLError{Number: 1213}))
+	assert.True(t, transaction.IsDeadlock(&pq.Error{Code: "40P01"}))
+	assert.False(t, transaction.IsDeadlock(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, transaction.IsDeadlock(nil))
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	assert.True(t, transaction.IsUniqueViolation(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, transaction.IsUniqueViolation(&pq.Error{Code: "23505"}))
+	assert.True(t, transaction.IsUniqueViolation(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}))
+	assert.True(t, transaction.IsUniqueViolation(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey}))
+	assert.False(t, transaction.IsUniqueViolation(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintNotNull}))
+	assert.False(t, transaction.IsUniqueViolation(&mysql.MySQLError{Number: 1213}))
+	assert.False(t, transaction.IsUniqueViolation(nil))
+}
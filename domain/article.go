@@ -5,32 +5,130 @@ import (
 	"time"
 )
 
+// ArticleStatus is the step of the editorial workflow an article is at
+type ArticleStatus string
+
+// Article statuses. An article is stored as a draft, submitted for review,
+// then published; unpublishing sends it back to the drafts and an archived
+// article stays archived.
+const (
+	StatusDraft     ArticleStatus = "draft"
+	StatusInReview  ArticleStatus = "in_review"
+	StatusPublished ArticleStatus = "published"
+	StatusArchived  ArticleStatus = "archived"
+)
+
+// Valid reports whether s is one of the known statuses
+func (s ArticleStatus) Valid() bool {
+	switch s {
+	case StatusDraft, StatusInReview, StatusPublished, StatusArchived:
+		return true
+	}
+	return false
+}
+
 // Article ...
+// Slug names the article in its URL, made of its title and unique among
+// every slug any article ever had. DeletedAt is when the article was moved to
+// the trash, nil outside of it.
 type Article struct {
-	ID        int64     `json:"id"`
-	Title     string    `json:"title" validate:"required"`
-	Content   string    `json:"content" validate:"required"`
-	Author    Author    `json:"author"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int64         `json:"id"`
+	Title       string        `json:"title" validate:"required"`
+	Slug        string        `json:"slug"`
+	Content     string        `json:"content" validate:"required"`
+	Author      Author        `json:"author" validate:"-"`
+	Categories  []Category    `json:"categories"`
+	Status      ArticleStatus `json:"status"`
+	PublishedAt *time.Time    `json:"published_at"`
+	PublishAt   *time.Time    `json:"publish_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	DeletedAt   *time.Time    `json:"deleted_at,omitempty"`
+	Version     int64         `json:"-"`
+}
+
+// ArticleFilter narrows a listing of articles, zero fields matching every
+// article. Query matches articles containing any of its words, Category is
+// the tag of a category and the range of creation time includes CreatedAfter
+// but not CreatedBefore.
+type ArticleFilter struct {
+	Query         string        `json:"q,omitempty"`
+	AuthorID      int64         `json:"author_id,omitempty"`
+	Category      string        `json:"category,omitempty"`
+	Status        ArticleStatus `json:"status,omitempty"`
+	CreatedAfter  time.Time     `json:"created_after"`
+	CreatedBefore time.Time     `json:"created_before"`
 }
 
 // ArticleUsecase represent the article's usecases
 type ArticleUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]Article, string, error)
+	Fetch(ctx context.Context, filter ArticleFilter, page Page) ([]Article, Cursors, error)
+	FetchByCategory(ctx context.Context, tag string, page Page) ([]Article, Cursors, error)
+	FetchByAuthor(ctx context.Context, authorID int64, page Page) ([]Article, Cursors, error)
 	GetByID(ctx context.Context, id int64) (Article, error)
 	Update(ctx context.Context, ar *Article) error
 	GetByTitle(ctx context.Context, title string) (Article, error)
+	GetBySlug(ctx context.Context, slug string) (Article, error)
+	FillSlugs(ctx context.Context) (int, error)
 	Store(context.Context, *Article) error
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, version int64) error
+	Submit(ctx context.Context, id int64, version int64) (Article, error)
+	Publish(ctx context.Context, id int64, version int64) (Article, error)
+	Unpublish(ctx context.Context, id int64, version int64) (Article, error)
+	Archive(ctx context.Context, id int64, version int64) (Article, error)
+	Schedule(ctx context.Context, id int64, version int64, publishAt *time.Time) (Article, error)
+	PublishDue(ctx context.Context, now time.Time) (int, error)
+	Search(ctx context.Context, query ArticleSearchQuery) (ArticleSearchResult, error)
+	Reindex(ctx context.Context) (int, error)
+	FetchRevisions(ctx context.Context, id int64, page Page) ([]ArticleRevision, Cursors, error)
+	DiffRevisions(ctx context.Context, id int64, rev int64, against int64, by DiffUnit) (ArticleRevisionDiff, error)
+	RestoreRevision(ctx context.Context, id int64, rev int64, version int64) (Article, error)
+	FetchTrash(ctx context.Context, authorID int64, page Page) ([]Article, Cursors, error)
+	Restore(ctx context.Context, id int64, version int64) (Article, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
 }
 
-// ArticleRepository represent the article's repository contract
+// ArticleRepository represent the article's repository contract. The
+// listings by category and author only hold articles in the given status, any
+// status when it is empty. Update leaves the status alone, UpdateStatus only
+// changes the status, publication time and scheduled publication time.
+// FetchDue lists the drafts and articles in review scheduled at or before
+// now, the earliest first. Store, Update and Restore give ErrConflict when
+// another article out of the trash has the same title, regardless of case.
+//
+// Delete moves an article at the given version to the trash, which only
+// FetchTrash and GetTrashed see, until Restore takes it out of it or Purge
+// removes it for good along with its revisions, category links and slugs;
+// both give ErrPreconditionFailed when the article is at another version.
+// FetchTrash lists the articles of the given author, of any author when 0,
+// the latest trashed first.
+// CountByAuthor counts the articles of an author, those in the trash included.
+//
+// SetSlug makes slug the current one of an article, keeping the slugs it had
+// before; a slug had by another article, even one given it by a transaction
+// running meanwhile, is an ErrConflict that leaves the transaction of ctx
+// usable for another slug. GetBySlug finds the
+// article that has or had a slug and FetchSlugs maps the slugs made of base,
+// base itself and base-2, base-3..., to the article that had them.
+// FetchWithoutSlug lists the articles yet to get a slug, trashed ones included.
 type ArticleRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []Article, nextCursor string, err error)
+	Fetch(ctx context.Context, filter ArticleFilter, page Page) (res []Article, cursors Cursors, err error)
+	FetchByCategory(ctx context.Context, categoryID int64, status ArticleStatus, page Page) (res []Article, cursors Cursors, err error)
+	FetchByAuthor(ctx context.Context, authorID int64, status ArticleStatus, page Page) (res []Article, cursors Cursors, err error)
+	CountByAuthor(ctx context.Context, authorID int64) (int64, error)
 	GetByID(ctx context.Context, id int64) (Article, error)
 	GetByTitle(ctx context.Context, title string) (Article, error)
+	GetBySlug(ctx context.Context, slug string) (Article, error)
+	FetchSlugs(ctx context.Context, base string) (map[string]int64, error)
+	SetSlug(ctx context.Context, id int64, slug string, at time.Time) error
+	FetchWithoutSlug(ctx context.Context, num int64) ([]Article, error)
 	Update(ctx context.Context, ar *Article) error
+	UpdateStatus(ctx context.Context, ar *Article) error
+	FetchDue(ctx context.Context, now time.Time, num int64) ([]Article, error)
 	Store(ctx context.Context, a *Article) error
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error
+	FetchTrash(ctx context.Context, authorID int64, page Page) (res []Article, cursors Cursors, err error)
+	GetTrashed(ctx context.Context, id int64) (Article, error)
+	Restore(ctx context.Context, id int64, version int64) error
+	Purge(ctx context.Context, before time.Time) (int, error)
 }
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TokenPair is the set of tokens handed out to an authenticated user
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// RefreshToken is the server-side record of an issued refresh token.
+// Only the SHA-256 hash of the token is kept.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// AuthUsecase represent the authentication's usecases
+type AuthUsecase interface {
+	Login(ctx context.Context, login, password string) (TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Authenticate(ctx context.Context, accessToken string) (User, error)
+}
+
+// RefreshTokenRepository represent the refresh token's repository contract
+type RefreshTokenRepository interface {
+	GetByHash(ctx context.Context, hash string) (RefreshToken, error)
+	Store(ctx context.Context, t *RefreshToken) error
+	Revoke(ctx context.Context, id int64, at time.Time) error
+	RevokeByUser(ctx context.Context, userID int64, at time.Time) error
+}
@@ -1,16 +1,37 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Author ...
 type Author struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" validate:"required"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// AuthorRepository represent the author's repository contract
+// AuthorUsecase represent the author's usecases
+type AuthorUsecase interface {
+	Fetch(ctx context.Context, page Page) ([]Author, Cursors, error)
+	GetByID(ctx context.Context, id int64) (Author, error)
+	Store(ctx context.Context, a *Author) error
+	Update(ctx context.Context, a *Author) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// AuthorRepository represent the author's repository contract. Lock holds the
+// author until the end of the transaction carried by ctx, so that it can't be
+// deleted while it is given an article nor given one while it is deleted; it
+// gives ErrNotFound when there is no such author.
 type AuthorRepository interface {
+	Fetch(ctx context.Context, page Page) (res []Author, cursors Cursors, err error)
 	GetByID(ctx context.Context, id int64) (Author, error)
+	Lock(ctx context.Context, id int64) error
+	GetByIDs(ctx context.Context, ids []int64) (map[int64]Author, error)
+	Store(ctx context.Context, a *Author) error
+	Update(ctx context.Context, a *Author) error
+	Delete(ctx context.Context, id int64) error
 }
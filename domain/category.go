@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Category ...
+type Category struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" validate:"required"`
+	Tag       string    `json:"tag" validate:"required"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CategoryUsecase represent the category's usecases
+type CategoryUsecase interface {
+	Fetch(ctx context.Context) ([]Category, error)
+	GetByID(ctx context.Context, id int64) (Category, error)
+	Store(ctx context.Context, c *Category) error
+	Update(ctx context.Context, c *Category) error
+	Delete(ctx context.Context, id int64) error
+	AssignArticle(ctx context.Context, articleID int64, categoryID int64) error
+	UnassignArticle(ctx context.Context, articleID int64, categoryID int64) error
+}
+
+// CategoryRepository represent the category's repository contract
+type CategoryRepository interface {
+	Fetch(ctx context.Context) ([]Category, error)
+	GetByID(ctx context.Context, id int64) (Category, error)
+	GetByTag(ctx context.Context, tag string) (Category, error)
+	GetByArticleIDs(ctx context.Context, articleIDs []int64) (map[int64][]Category, error)
+	Store(ctx context.Context, c *Category) error
+	Update(ctx context.Context, c *Category) error
+	Delete(ctx context.Context, id int64) error
+	AddArticle(ctx context.Context, articleID int64, categoryID int64) error
+	RemoveArticle(ctx context.Context, articleID int64, categoryID int64) error
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// Locker takes named locks shared by every replica of the service, so that a
+// background job runs on a single replica at a time. TryLock does not wait:
+// it reports whether the lock was taken, and a lock taken is held until
+// unlock is called.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
@@ -4,19 +4,41 @@ package mocks
 import context "context"
 import domain "github.com/rachadiannovansyah/go-echo-clean-arch/domain"
 import mock "github.com/stretchr/testify/mock"
+import time "time"
 
 // ArticleRepository is an autogenerated mock type for the ArticleRepository type
 type ArticleRepository struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// CountByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *ArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (int64, error) {
+	ret := _m.Called(ctx, authorID)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id, version, deletedAt
+func (_m *ArticleRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	ret := _m.Called(ctx, id, version, deletedAt)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, version, deletedAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -24,29 +46,59 @@ func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, filter, page
+func (_m *ArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, filter, page)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleFilter, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ArticleFilter, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, domain.ArticleFilter, domain.Page) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchByAuthor provides a mock function with given fields: ctx, authorID, status, page
+func (_m *ArticleRepository) FetchByAuthor(ctx context.Context, authorID int64, status domain.ArticleStatus, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, authorID, status, page)
 
 	var r0 []domain.Article
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ArticleStatus, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, authorID, status, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.ArticleStatus, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, authorID, status, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.Cursors)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.ArticleStatus, domain.Page) error); ok {
+		r2 = rf(ctx, authorID, status, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -54,6 +106,135 @@ func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64
 	return r0, r1, r2
 }
 
+// FetchByCategory provides a mock function with given fields: ctx, categoryID, status, page
+func (_m *ArticleRepository) FetchByCategory(ctx context.Context, categoryID int64, status domain.ArticleStatus, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, categoryID, status, page)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ArticleStatus, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, categoryID, status, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.ArticleStatus, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, categoryID, status, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.ArticleStatus, domain.Page) error); ok {
+		r2 = rf(ctx, categoryID, status, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchDue provides a mock function with given fields: ctx, now, num
+func (_m *ArticleRepository) FetchDue(ctx context.Context, now time.Time, num int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, now, num)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64) []domain.Article); ok {
+		r0 = rf(ctx, now, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int64) error); ok {
+		r1 = rf(ctx, now, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchSlugs provides a mock function with given fields: ctx, base
+func (_m *ArticleRepository) FetchSlugs(ctx context.Context, base string) (map[string]int64, error) {
+	ret := _m.Called(ctx, base)
+
+	var r0 map[string]int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]int64); ok {
+		r0 = rf(ctx, base)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, base)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTrash provides a mock function with given fields: ctx, authorID, page
+func (_m *ArticleRepository) FetchTrash(ctx context.Context, authorID int64, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, authorID, page)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, authorID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, authorID, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.Page) error); ok {
+		r2 = rf(ctx, authorID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchWithoutSlug provides a mock function with given fields: ctx, num
+func (_m *ArticleRepository) FetchWithoutSlug(ctx context.Context, num int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, num)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.Article); ok {
+		r0 = rf(ctx, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Article, error) {
 	ret := _m.Called(ctx, id)
@@ -75,6 +256,27 @@ func (_m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Arti
 	return r0, r1
 }
 
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Article); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByTitle provides a mock function with given fields: ctx, title
 func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
 	ret := _m.Called(ctx, title)
@@ -96,13 +298,83 @@ func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (doma
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: _a0, _a1
-func (_m *ArticleRepository) Store(_a0 context.Context, _a1 *domain.Article) error {
-	ret := _m.Called(_a0, _a1)
+// GetTrashed provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) GetTrashed(ctx context.Context, id int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, before
+func (_m *ArticleRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id, version
+func (_m *ArticleRepository) Restore(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSlug provides a mock function with given fields: ctx, id, slug, at
+func (_m *ArticleRepository) SetSlug(ctx context.Context, id int64, slug string, at time.Time) error {
+	ret := _m.Called(ctx, id, slug, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Time) error); ok {
+		r0 = rf(ctx, id, slug, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *ArticleRepository) Store(ctx context.Context, a *domain.Article) error {
+	ret := _m.Called(ctx, a)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) error); ok {
-		r0 = rf(_a0, _a1)
+		r0 = rf(ctx, a)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -123,3 +395,17 @@ func (_m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) err
 
 	return r0
 }
+
+// UpdateStatus provides a mock function with given fields: ctx, ar
+func (_m *ArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article) error {
+	ret := _m.Called(ctx, ar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) error); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
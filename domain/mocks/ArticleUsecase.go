@@ -4,19 +4,41 @@ package mocks
 import context "context"
 import domain "github.com/rachadiannovansyah/go-echo-clean-arch/domain"
 import mock "github.com/stretchr/testify/mock"
+import time "time"
 
 // ArticleUsecase is an autogenerated mock type for the ArticleUsecase type
 type ArticleUsecase struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *ArticleUsecase) Delete(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// Archive provides a mock function with given fields: ctx, id, version
+func (_m *ArticleUsecase) Archive(ctx context.Context, id int64, version int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Article); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *ArticleUsecase) Delete(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -24,29 +46,110 @@ func (_m *ArticleUsecase) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleUsecase) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// DiffRevisions provides a mock function with given fields: ctx, id, rev, against, by
+func (_m *ArticleUsecase) DiffRevisions(ctx context.Context, id int64, rev int64, against int64, by domain.DiffUnit) (domain.ArticleRevisionDiff, error) {
+	ret := _m.Called(ctx, id, rev, against, by)
+
+	var r0 domain.ArticleRevisionDiff
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, domain.DiffUnit) domain.ArticleRevisionDiff); ok {
+		r0 = rf(ctx, id, rev, against, by)
+	} else {
+		r0 = ret.Get(0).(domain.ArticleRevisionDiff)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64, domain.DiffUnit) error); ok {
+		r1 = rf(ctx, id, rev, against, by)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, page
+func (_m *ArticleUsecase) Fetch(ctx context.Context, filter domain.ArticleFilter, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, filter, page)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleFilter, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ArticleFilter, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, domain.ArticleFilter, domain.Page) error); ok {
+		r2 = rf(ctx, filter, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchByAuthor provides a mock function with given fields: ctx, authorID, page
+func (_m *ArticleUsecase) FetchByAuthor(ctx context.Context, authorID int64, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, authorID, page)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, authorID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, authorID, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.Page) error); ok {
+		r2 = rf(ctx, authorID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchByCategory provides a mock function with given fields: ctx, tag, page
+func (_m *ArticleUsecase) FetchByCategory(ctx context.Context, tag string, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, tag, page)
 
 	var r0 []domain.Article
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, tag, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, tag, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.Cursors)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.Page) error); ok {
+		r2 = rf(ctx, tag, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -54,6 +157,87 @@ func (_m *ArticleUsecase) Fetch(ctx context.Context, cursor string, num int64) (
 	return r0, r1, r2
 }
 
+// FetchRevisions provides a mock function with given fields: ctx, id, page
+func (_m *ArticleUsecase) FetchRevisions(ctx context.Context, id int64, page domain.Page) ([]domain.ArticleRevision, domain.Cursors, error) {
+	ret := _m.Called(ctx, id, page)
+
+	var r0 []domain.ArticleRevision
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Page) []domain.ArticleRevision); ok {
+		r0 = rf(ctx, id, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ArticleRevision)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, id, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.Page) error); ok {
+		r2 = rf(ctx, id, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchTrash provides a mock function with given fields: ctx, authorID, page
+func (_m *ArticleUsecase) FetchTrash(ctx context.Context, authorID int64, page domain.Page) ([]domain.Article, domain.Cursors, error) {
+	ret := _m.Called(ctx, authorID, page)
+
+	var r0 []domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Page) []domain.Article); ok {
+		r0 = rf(ctx, authorID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, authorID, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.Page) error); ok {
+		r2 = rf(ctx, authorID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FillSlugs provides a mock function with given fields: ctx
+func (_m *ArticleUsecase) FillSlugs(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *ArticleUsecase) GetByID(ctx context.Context, id int64) (domain.Article, error) {
 	ret := _m.Called(ctx, id)
@@ -75,6 +259,27 @@ func (_m *ArticleUsecase) GetByID(ctx context.Context, id int64) (domain.Article
 	return r0, r1
 }
 
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *ArticleUsecase) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Article); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByTitle provides a mock function with given fields: ctx, title
 func (_m *ArticleUsecase) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
 	ret := _m.Called(ctx, title)
@@ -96,6 +301,174 @@ func (_m *ArticleUsecase) GetByTitle(ctx context.Context, title string) (domain.
 	return r0, r1
 }
 
+// Publish provides a mock function with given fields: ctx, id, version
+func (_m *ArticleUsecase) Publish(ctx context.Context, id int64, version int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Article); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishDue provides a mock function with given fields: ctx, now
+func (_m *ArticleUsecase) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeTrash provides a mock function with given fields: ctx, before
+func (_m *ArticleUsecase) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reindex provides a mock function with given fields: ctx
+func (_m *ArticleUsecase) Reindex(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id, version
+func (_m *ArticleUsecase) Restore(ctx context.Context, id int64, version int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Article); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreRevision provides a mock function with given fields: ctx, id, rev, version
+func (_m *ArticleUsecase) RestoreRevision(ctx context.Context, id int64, rev int64, version int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id, rev, version)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) domain.Article); ok {
+		r0 = rf(ctx, id, rev, version)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = rf(ctx, id, rev, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Schedule provides a mock function with given fields: ctx, id, version, publishAt
+func (_m *ArticleUsecase) Schedule(ctx context.Context, id int64, version int64, publishAt *time.Time) (domain.Article, error) {
+	ret := _m.Called(ctx, id, version, publishAt)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, *time.Time) domain.Article); ok {
+		r0 = rf(ctx, id, version, publishAt)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, *time.Time) error); ok {
+		r1 = rf(ctx, id, version, publishAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query
+func (_m *ArticleUsecase) Search(ctx context.Context, query domain.ArticleSearchQuery) (domain.ArticleSearchResult, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 domain.ArticleSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ArticleSearchQuery) domain.ArticleSearchResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(domain.ArticleSearchResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ArticleSearchQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *ArticleUsecase) Store(_a0 context.Context, _a1 *domain.Article) error {
 	ret := _m.Called(_a0, _a1)
@@ -110,6 +483,48 @@ func (_m *ArticleUsecase) Store(_a0 context.Context, _a1 *domain.Article) error
 	return r0
 }
 
+// Submit provides a mock function with given fields: ctx, id, version
+func (_m *ArticleUsecase) Submit(ctx context.Context, id int64, version int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Article); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unpublish provides a mock function with given fields: ctx, id, version
+func (_m *ArticleUsecase) Unpublish(ctx context.Context, id int64, version int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 domain.Article
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Article); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, ar
 func (_m *ArticleUsecase) Update(ctx context.Context, ar *domain.Article) error {
 	ret := _m.Called(ctx, ar)
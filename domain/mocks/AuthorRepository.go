@@ -10,6 +10,50 @@ type AuthorRepository struct {
 	mock.Mock
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *AuthorRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *AuthorRepository) Fetch(ctx context.Context, page domain.Page) ([]domain.Author, domain.Cursors, error) {
+	ret := _m.Called(ctx, page)
+
+	var r0 []domain.Author
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Page) []domain.Author); ok {
+		r0 = rf(ctx, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Author)
+		}
+	}
+
+	var r1 domain.Cursors
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Page) domain.Cursors); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Get(1).(domain.Cursors)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, domain.Page) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *AuthorRepository) GetByID(ctx context.Context, id int64) (domain.Author, error) {
 	ret := _m.Called(ctx, id)
@@ -30,3 +74,68 @@ func (_m *AuthorRepository) GetByID(ctx context.Context, id int64) (domain.Autho
 
 	return r0, r1
 }
+
+// GetByIDs provides a mock function with given fields: ctx, ids
+func (_m *AuthorRepository) GetByIDs(ctx context.Context, ids []int64) (map[int64]domain.Author, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 map[int64]domain.Author
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64]domain.Author); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]domain.Author)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Lock provides a mock function with given fields: ctx, id
+func (_m *AuthorRepository) Lock(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *AuthorRepository) Store(ctx context.Context, a *domain.Author) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Author) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, a
+func (_m *AuthorRepository) Update(ctx context.Context, a *domain.Author) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Author) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
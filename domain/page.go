@@ -0,0 +1,42 @@
+package domain
+
+// SortDirection orders a listing by creation time, or by relevance to a search
+// then creation time
+type SortDirection string
+
+// Available sort directions, oldest first being the default
+const (
+	SortAsc       SortDirection = "asc"
+	SortDesc      SortDirection = "desc"
+	SortRelevance SortDirection = "relevance"
+)
+
+// Valid reports whether d is a known sort direction, the empty one included
+func (d SortDirection) Valid() bool {
+	return d == "" || d == SortAsc || d == SortDesc || d == SortRelevance
+}
+
+// Page selects a page of a listing. Cursor is empty for the first page or
+// one of the cursors returned with another page of the same listing.
+type Page struct {
+	Cursor string
+	Num    int64
+	Sort   SortDirection
+}
+
+// Cursors lead to the pages around the one returned, each empty when there is
+// no such page
+type Cursors struct {
+	Next string
+	Prev string
+}
+
+// CursorSealer hands the cursors of the repositories out to clients. Seal
+// signs the cursors of a page of the named listing, binding them to its sort
+// and filter, and Open gives back the page to query, failing with
+// ErrBadParamInput when the cursor is forged, expired or was issued for
+// another listing, sort or filter.
+type CursorSealer interface {
+	Seal(page Page, listing string, filter interface{}, cursors Cursors) Cursors
+	Open(page Page, listing string, filter interface{}) (Page, error)
+}
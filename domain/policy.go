@@ -0,0 +1,60 @@
+package domain
+
+import "context"
+
+// Role is a named set of permissions granted to a user
+type Role string
+
+// Roles known by the application
+const (
+	RoleAuthor Role = "author"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// Permission is a single action a user may be allowed to perform
+type Permission string
+
+// Permissions known by the application
+const (
+	// PermissionArticleWrite allows creating articles and editing one's own
+	PermissionArticleWrite Permission = "article:write"
+	// PermissionArticleEditAny allows editing and deleting any article
+	PermissionArticleEditAny Permission = "article:edit-any"
+	// PermissionArticlePostAsAny allows publishing articles on behalf of any author
+	PermissionArticlePostAsAny Permission = "article:post-as-any"
+	// PermissionArticlePublish allows reviewing, publishing, unpublishing and archiving any article
+	PermissionArticlePublish Permission = "article:publish"
+	// PermissionUserManage allows listing users and granting or revoking roles
+	PermissionUserManage Permission = "user:manage"
+	// PermissionAuthorManage allows creating, editing and deleting authors
+	PermissionAuthorManage Permission = "author:manage"
+	// PermissionCategoryManage allows creating, editing and deleting categories
+	PermissionCategoryManage Permission = "category:manage"
+)
+
+// RolePermissions maps every role to the permissions it grants
+var RolePermissions = map[Role][]Permission{
+	RoleAuthor: {PermissionArticleWrite},
+	RoleEditor: {PermissionArticleWrite, PermissionArticleEditAny, PermissionArticlePublish, PermissionAuthorManage, PermissionCategoryManage},
+	RoleAdmin:  {PermissionArticleWrite, PermissionArticleEditAny, PermissionArticlePostAsAny, PermissionArticlePublish, PermissionUserManage, PermissionAuthorManage, PermissionCategoryManage},
+}
+
+// Valid reports whether r is one of the known roles
+func (r Role) Valid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+// Policy decides whether the caller carried by the context may perform an
+// action. It returns ErrUnauthorized for anonymous callers and ErrForbidden
+// when the caller lacks the permission.
+type Policy interface {
+	CanCreateArticle(ctx context.Context) error
+	CanModifyArticle(ctx context.Context, a Article) error
+	CanPostAsAuthor(ctx context.Context, authorID int64) error
+	CanPublishArticle(ctx context.Context) error
+	CanManageUsers(ctx context.Context) error
+	CanManageAuthors(ctx context.Context) error
+	CanManageCategories(ctx context.Context) error
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ArticleRevision is an immutable copy of the title and content of an
+// article, stored when the article is created and on every update. The
+// revisions of an article are numbered from 1, UserID being the user who
+// wrote it, 0 when unknown.
+type ArticleRevision struct {
+	ID        int64     `json:"-"`
+	ArticleID int64     `json:"article_id"`
+	Number    int64     `json:"rev"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DiffUnit is the granularity of a diff
+type DiffUnit string
+
+// Available diff units, lines being the default
+const (
+	DiffByLine DiffUnit = "line"
+	DiffByWord DiffUnit = "word"
+)
+
+// Valid reports whether u is a known diff unit
+func (u DiffUnit) Valid() bool {
+	return u == DiffByLine || u == DiffByWord
+}
+
+// DiffOp tells what a diff does with a chunk of text
+type DiffOp string
+
+// Diff operations
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffChunk is a run of lines or words kept, inserted or deleted. Joining
+// the text of the chunks other than the inserted ones gives back the old
+// text, and of the chunks other than the deleted ones the new text.
+type DiffChunk struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// ArticleRevisionDiff is the diff of the title and content of an article from
+// revision From to revision To, revision 0 standing for an empty article
+type ArticleRevisionDiff struct {
+	ArticleID int64       `json:"article_id"`
+	From      int64       `json:"from"`
+	To        int64       `json:"to"`
+	By        DiffUnit    `json:"by"`
+	Title     []DiffChunk `json:"title"`
+	Content   []DiffChunk `json:"content"`
+}
+
+// ArticleRevisionRepository represent the article revision's repository
+// contract. Store numbers the revision after the latest one of its article.
+type ArticleRevisionRepository interface {
+	FetchByArticle(ctx context.Context, articleID int64, page Page) (res []ArticleRevision, cursors Cursors, err error)
+	GetByNumber(ctx context.Context, articleID int64, number int64) (ArticleRevision, error)
+	Store(ctx context.Context, r *ArticleRevision) error
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "context"
+
+// ArticleSearchQuery is a full-text search of the articles. An empty Query
+// matches every article, Category is the tag of a category, and From and Num
+// select the hits returned among the best ones.
+type ArticleSearchQuery struct {
+	Query    string
+	AuthorID int64
+	Category string
+	From     int
+	Num      int
+}
+
+// ArticleHit is an article found by a search, with snippets of its title and
+// content highlighting the words searched
+type ArticleHit struct {
+	Article    Article             `json:"article"`
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+}
+
+// FacetTerm counts the hits sharing a value, Term being the category tag or
+// the author id and Label its display name
+type FacetTerm struct {
+	Term  string `json:"term"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// Facets names
+const (
+	FacetCategory = "category"
+	FacetAuthor   = "author"
+)
+
+// ArticleSearchResult is a page of hits, the total number of matching
+// articles and the facets of all of them by category and author
+type ArticleSearchResult struct {
+	Total  uint64                 `json:"total"`
+	Hits   []ArticleHit           `json:"hits"`
+	Facets map[string][]FacetTerm `json:"facets"`
+}
+
+// ArticleSearcher is a full-text index of the articles kept beside their
+// repository. Index adds or replaces the given articles, categories included,
+// and the hits of Search carry the author and categories by id or tag only.
+type ArticleSearcher interface {
+	Index(ctx context.Context, articles []Article) error
+	Delete(ctx context.Context, id int64) error
+	Search(ctx context.Context, query ArticleSearchQuery) (ArticleSearchResult, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// Transactor runs a unit of work atomically. The repositories called with the
+// context given to fn take part in the transaction, and a transaction already
+// carried by ctx is joined instead of starting a new one.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
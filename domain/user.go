@@ -5,22 +5,71 @@ import (
 	"time"
 )
 
+// User ...
 type User struct {
 	ID        int64     `json:"ID"`
 	Fullname  string    `json:"fullname"`
-	Username  string    `json:"username"`
+	Username  string    `json:"username" validate:"required"`
 	Email     string    `json:"email" validate:"required,email"`
-	Password  string    `json:"passwd"`
+	Password  string    `json:"-"`
+	AuthorID  int64     `json:"author_id"`
+	Roles     []Role    `json:"roles"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// HasRole reports whether the user was granted the role
+func (u User) HasRole(r Role) bool {
+	for _, role := range u.Roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether any of the user's roles grants the permission
+func (u User) Can(p Permission) bool {
+	for _, role := range u.Roles {
+		for _, perm := range RolePermissions[role] {
+			if perm == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // UserUsecase ..
 type UserUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]User, string, error)
+	Fetch(ctx context.Context, page Page) ([]User, Cursors, error)
+	Store(ctx context.Context, u *User) error
+	GrantRole(ctx context.Context, userID int64, role Role) error
+	RevokeRole(ctx context.Context, userID int64, role Role) error
+	Promote(ctx context.Context, email string, role Role) error
 }
 
 // UserRepository ..
 type UserRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []User, nextCursor string, err error)
+	Fetch(ctx context.Context, page Page) (res []User, cursors Cursors, err error)
+	GetByID(ctx context.Context, id int64) (User, error)
+	GetByEmail(ctx context.Context, email string) (User, error)
+	GetByUsername(ctx context.Context, username string) (User, error)
+	GetByAuthorID(ctx context.Context, authorID int64) (User, error)
+	Store(ctx context.Context, u *User) error
+	AddRole(ctx context.Context, userID int64, role Role) error
+	RemoveRole(ctx context.Context, userID int64, role Role) error
+}
+
+type userContextKey struct{}
+
+// NewContextWithUser returns a copy of ctx carrying the authenticated user
+func NewContextWithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, u)
+}
+
+// UserFromContext returns the authenticated user carried by ctx, if any
+func UserFromContext(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userContextKey{}).(User)
+	return u, ok
 }
@@ -1,37 +1,28 @@
 module github.com/rachadiannovansyah/go-echo-clean-arch
 
-go 1.12
+go 1.16
 
 require (
-	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/blevesearch/bleve/v2 v2.0.6
 	github.com/bxcodec/faker v1.4.2
-	github.com/davecgh/go-spew v1.1.0 // indirect
-	github.com/go-playground/locales v0.12.1 // indirect
-	github.com/go-playground/universal-translator v0.16.0 // indirect
+	github.com/go-playground/locales v0.12.1
+	github.com/go-playground/universal-translator v0.16.0
 	github.com/go-sql-driver/mysql v1.3.0
-	github.com/hashicorp/hcl v0.0.0-20180404174102-ef8a98b0bbce // indirect
+	github.com/golang-jwt/jwt/v4 v4.5.2
 	github.com/labstack/echo v3.3.5+incompatible
 	github.com/labstack/gommon v0.0.0-20180426014445-588f4e8bddc6 // indirect
-	github.com/magiconair/properties v1.7.6 // indirect
+	github.com/lib/pq v1.9.0
 	github.com/mattn/go-colorable v0.0.9 // indirect
 	github.com/mattn/go-isatty v0.0.3 // indirect
-	github.com/mitchellh/mapstructure v0.0.0-20180220230111-00c29f56e238 // indirect
-	github.com/onsi/ginkgo v1.8.0 // indirect
-	github.com/onsi/gomega v1.5.0 // indirect
-	github.com/pelletier/go-toml v1.1.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/sirupsen/logrus v1.0.5
-	github.com/spf13/afero v1.1.0 // indirect
-	github.com/spf13/cast v1.2.0 // indirect
-	github.com/spf13/jwalterweatherman v0.0.0-20180109140146-7c0cea34c8ec // indirect
-	github.com/spf13/pflag v1.0.1 // indirect
-	github.com/spf13/viper v1.0.2
-	github.com/stretchr/objx v0.1.0 // indirect
-	github.com/stretchr/testify v1.2.1
+	github.com/spf13/viper v1.3.2
+	github.com/stretchr/testify v1.4.0
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/fasttemplate v0.0.0-20170224212429-dcecefd839c4 // indirect
-	golang.org/x/crypto v0.0.0-20180426230345-b49d69b5da94 // indirect
-	golang.org/x/sync v0.0.0-20181108010431-42b317875d0f
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
+	golang.org/x/text v0.14.0
 	gopkg.in/DATA-DOG/go-sqlmock.v1 v1.3.0
 	gopkg.in/airbrake/gobrake.v2 v2.0.9 // indirect
 	gopkg.in/gemnasium/logrus-airbrake-hook.v2 v2.1.2 // indirect
@@ -0,0 +1,33 @@
+// Package testutil holds the test doubles shared by the tests of several
+// modules.
+package testutil
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+)
+
+// NewTransactor returns a Transactor that runs the unit of work right away
+func NewTransactor() *mocks.Transactor {
+	transactor := new(mocks.Transactor)
+	transactor.On("WithinTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	return transactor
+}
+
+// NewSealer returns a CursorSealer handing the cursors of the repositories as they are
+func NewSealer() *mocks.CursorSealer {
+	sealer := new(mocks.CursorSealer)
+	sealer.On("Open", mock.Anything, mock.Anything, mock.Anything).Return(func(page domain.Page, listing string, filter interface{}) domain.Page {
+		return page
+	}, nil)
+	sealer.On("Seal", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(page domain.Page, listing string, filter interface{}, cursors domain.Cursors) domain.Cursors {
+		return cursors
+	})
+	return sealer
+}
@@ -1,18 +1,25 @@
 package http
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo"
 	"github.com/sirupsen/logrus"
-	validator "gopkg.in/go-playground/validator.v9"
 
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
 	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
+// MIMEApplicationMergePatchJSON is the media type of RFC 7396 merge-patch documents
+const MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
 // ResponseError represent the reseponse error struct
 type ResponseError struct {
 	Message string `json:"message"`
@@ -29,24 +36,159 @@ func NewArticleHandler(e *echo.Echo, us domain.ArticleUsecase) {
 		AUsecase: us,
 	}
 	e.GET("/articles", handler.FetchArticle)
+	e.GET("/categories/:tag/articles", handler.FetchByCategory)
+	e.GET("/authors/:id/articles", handler.FetchByAuthor)
+	e.GET("/search/articles", handler.Search)
+	e.GET("/articles/trash", handler.FetchTrash)
+	e.GET("/articles/by-slug/:slug", handler.GetBySlug)
 	e.POST("/articles", handler.Store)
 	e.GET("/articles/:id", handler.GetByID)
+	e.PUT("/articles/:id", handler.Update)
+	e.PATCH("/articles/:id", handler.Patch)
 	e.DELETE("/articles/:id", handler.Delete)
+	e.POST("/articles/:id/restore", handler.Restore)
+	e.POST("/articles/:id/submit", handler.Submit)
+	e.POST("/articles/:id/publish", handler.Publish)
+	e.POST("/articles/:id/unpublish", handler.Unpublish)
+	e.POST("/articles/:id/archive", handler.Archive)
+	e.PUT("/articles/:id/schedule", handler.Schedule)
+	e.DELETE("/articles/:id/schedule", handler.Unschedule)
+	e.GET("/articles/:id/revisions", handler.FetchRevisions)
+	e.GET("/articles/:id/revisions/:rev/diff", handler.DiffRevisions)
+	e.POST("/articles/:id/revisions/:rev/restore", handler.RestoreRevision)
 }
 
-// FetchArticle will fetch the article based on given params
+// FetchArticle will fetch the article based on given params, narrowed by the
+// q, author_id, category, status, created_after and created_before ones
 func (a *ArticleHandler) FetchArticle(c echo.Context) error {
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
-	cursor := c.QueryParam("cursor")
+	page := domain.Page{
+		Cursor: c.QueryParam("cursor"),
+		Num:    int64(num),
+		Sort:   domain.SortDirection(c.QueryParam("sort")),
+	}
+	filter, err := articleFilter(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+
+	listAr, cursors, err := a.AUsecase.Fetch(ctx, filter, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(`X-Cursor`, cursors.Next)
+	c.Response().Header().Set(`X-Prev-Cursor`, cursors.Prev)
+	return c.JSON(http.StatusOK, listAr)
+}
+
+// articleFilter reads the filter of a listing of articles from the query
+// parameters. Dates are either RFC 3339 timestamps or plain days.
+func articleFilter(c echo.Context) (filter domain.ArticleFilter, err error) {
+	filter.Query = c.QueryParam("q")
+	filter.Category = c.QueryParam("category")
+	filter.Status = domain.ArticleStatus(c.QueryParam("status"))
+	if authorID := c.QueryParam("author_id"); authorID != "" {
+		if filter.AuthorID, err = strconv.ParseInt(authorID, 10, 64); err != nil {
+			return domain.ArticleFilter{}, errHandle.ErrBadParamInput
+		}
+	}
+	if filter.CreatedAfter, err = parseDate(c.QueryParam("created_after")); err != nil {
+		return domain.ArticleFilter{}, err
+	}
+	if filter.CreatedBefore, err = parseDate(c.QueryParam("created_before")); err != nil {
+		return domain.ArticleFilter{}, err
+	}
+	return
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errHandle.ErrBadParamInput
+}
+
+// Search will search the articles for the words in q, narrowed by the
+// category and author_id params, returning num hits from the from-th best one
+// with their highlighted snippets and the category and author facets
+func (a *ArticleHandler) Search(c echo.Context) error {
+	num, _ := strconv.Atoi(c.QueryParam("num"))
+	from, _ := strconv.Atoi(c.QueryParam("from"))
+	query := domain.ArticleSearchQuery{
+		Query:    c.QueryParam("q"),
+		Category: c.QueryParam("category"),
+		From:     from,
+		Num:      num,
+	}
+	if authorID := c.QueryParam("author_id"); authorID != "" {
+		var err error
+		if query.AuthorID, err = strconv.ParseInt(authorID, 10, 64); err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: errHandle.ErrBadParamInput.Error()})
+		}
+	}
+	ctx := c.Request().Context()
+
+	res, err := a.AUsecase.Search(ctx, query)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// FetchByCategory will fetch the articles of the category given by tag
+func (a *ArticleHandler) FetchByCategory(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	page := domain.Page{
+		Cursor: c.QueryParam("cursor"),
+		Num:    int64(num),
+		Sort:   domain.SortDirection(c.QueryParam("sort")),
+	}
+	tag := c.Param("tag")
 	ctx := c.Request().Context()
 
-	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num))
+	listAr, cursors, err := a.AUsecase.FetchByCategory(ctx, tag, page)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
-	fmt.Println(nextCursor)
-	c.Response().Header().Set(`X-Cursor`, nextCursor)
+
+	c.Response().Header().Set(`X-Cursor`, cursors.Next)
+	c.Response().Header().Set(`X-Prev-Cursor`, cursors.Prev)
+	return c.JSON(http.StatusOK, listAr)
+}
+
+// FetchByAuthor will fetch the articles written by the given author
+func (a *ArticleHandler) FetchByAuthor(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	page := domain.Page{
+		Cursor: c.QueryParam("cursor"),
+		Num:    int64(num),
+		Sort:   domain.SortDirection(c.QueryParam("sort")),
+	}
+	ctx := c.Request().Context()
+
+	listAr, cursors, err := a.AUsecase.FetchByAuthor(ctx, int64(idP), page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(`X-Cursor`, cursors.Next)
+	c.Response().Header().Set(`X-Prev-Cursor`, cursors.Prev)
 	return c.JSON(http.StatusOK, listAr)
 }
 
@@ -65,16 +207,26 @@ func (a *ArticleHandler) GetByID(c echo.Context) error {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
 
+	c.Response().Header().Set(headerETag, formatETag(art.Version))
 	return c.JSON(http.StatusOK, art)
 }
 
-func isRequestValid(m *domain.Article) (bool, error) {
-	validate := validator.New()
-	err := validate.Struct(m)
+// GetBySlug will get article by given slug, redirecting permanently to its
+// current slug when given one it had before
+func (a *ArticleHandler) GetBySlug(c echo.Context) error {
+	ctx := c.Request().Context()
+	slug := c.Param("slug")
+
+	art, err := a.AUsecase.GetBySlug(ctx, slug)
 	if err != nil {
-		return false, err
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
-	return true, nil
+	if art.Slug != slug {
+		return c.Redirect(http.StatusMovedPermanently, "/articles/by-slug/"+art.Slug)
+	}
+
+	c.Response().Header().Set(headerETag, formatETag(art.Version))
+	return c.JSON(http.StatusOK, art)
 }
 
 // Store will store the article by given request body
@@ -85,9 +237,8 @@ func (a *ArticleHandler) Store(c echo.Context) (err error) {
 		return c.JSON(http.StatusUnprocessableEntity, err.Error())
 	}
 
-	var ok bool
-	if ok, err = isRequestValid(&article); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err = validation.Struct(&article); err != nil {
+		return validation.Respond(c, err)
 	}
 
 	ctx := c.Request().Context()
@@ -99,17 +250,120 @@ func (a *ArticleHandler) Store(c echo.Context) (err error) {
 	return c.JSON(http.StatusCreated, article)
 }
 
-// Delete will delete article by given param
+// Update will replace the article by given param and request body
+func (a *ArticleHandler) Update(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	var article domain.Article
+	err = c.Bind(&article)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	article.ID = int64(idP)
+	article.Version = version
+
+	if err = validation.Struct(&article); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = a.AUsecase.Update(ctx, &article)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(headerETag, formatETag(article.Version))
+	return c.JSON(http.StatusOK, article)
+}
+
+// Patch will partially update the article by applying an RFC 7396 merge-patch request body
+func (a *ArticleHandler) Patch(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || (mediaType != MIMEApplicationMergePatchJSON && mediaType != echo.MIMEApplicationJSON) {
+		return c.JSON(http.StatusUnsupportedMediaType, ResponseError{Message: "Content-Type must be " + MIMEApplicationMergePatchJSON})
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	patch, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	id := int64(idP)
+	ctx := c.Request().Context()
+
+	art, err := a.AUsecase.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if art.Version != version {
+		return c.JSON(getStatusCode(errHandle.ErrPreconditionFailed), ResponseError{Message: errHandle.ErrPreconditionFailed.Error()})
+	}
+
+	original, err := json.Marshal(art)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	patched, err := mergePatch(original, patch)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	var article domain.Article
+	err = json.Unmarshal(patched, &article)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	article.ID = id
+	article.Version = version
+
+	if err = validation.Struct(&article); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	err = a.AUsecase.Update(ctx, &article)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(headerETag, formatETag(article.Version))
+	return c.JSON(http.StatusOK, article)
+}
+
+// Delete will move the article by given param to the trash
 func (a *ArticleHandler) Delete(c echo.Context) error {
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
 	}
 
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
 	id := int64(idP)
 	ctx := c.Request().Context()
 
-	err = a.AUsecase.Delete(ctx, id)
+	err = a.AUsecase.Delete(ctx, id, version)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
@@ -117,6 +371,214 @@ func (a *ArticleHandler) Delete(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// FetchTrash will fetch the articles in the trash, of the author_id param
+// when given
+func (a *ArticleHandler) FetchTrash(c echo.Context) error {
+	var authorID int64
+	if authorIDP := c.QueryParam("author_id"); authorIDP != "" {
+		var err error
+		if authorID, err = strconv.ParseInt(authorIDP, 10, 64); err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: errHandle.ErrBadParamInput.Error()})
+		}
+	}
+
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	page := domain.Page{
+		Cursor: c.QueryParam("cursor"),
+		Num:    int64(num),
+		Sort:   domain.SortDirection(c.QueryParam("sort")),
+	}
+	ctx := c.Request().Context()
+
+	listAr, cursors, err := a.AUsecase.FetchTrash(ctx, authorID, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(`X-Cursor`, cursors.Next)
+	c.Response().Header().Set(`X-Prev-Cursor`, cursors.Prev)
+	return c.JSON(http.StatusOK, listAr)
+}
+
+// Restore will take the article by given param out of the trash
+func (a *ArticleHandler) Restore(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	art, err := a.AUsecase.Restore(ctx, int64(idP), version)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(headerETag, formatETag(art.Version))
+	return c.JSON(http.StatusOK, art)
+}
+
+// Submit will send the draft article by given param for review
+func (a *ArticleHandler) Submit(c echo.Context) error {
+	return a.transition(c, a.AUsecase.Submit)
+}
+
+// Publish will publish the article by given param
+func (a *ArticleHandler) Publish(c echo.Context) error {
+	return a.transition(c, a.AUsecase.Publish)
+}
+
+// Unpublish will send the published article by given param back to the drafts
+func (a *ArticleHandler) Unpublish(c echo.Context) error {
+	return a.transition(c, a.AUsecase.Unpublish)
+}
+
+// Archive will archive the article by given param
+func (a *ArticleHandler) Archive(c echo.Context) error {
+	return a.transition(c, a.AUsecase.Archive)
+}
+
+// scheduleRequest is the body of a publication schedule
+type scheduleRequest struct {
+	PublishAt *time.Time `json:"publish_at" validate:"required"`
+}
+
+// Schedule will publish the article by given param at the publish_at time of
+// the request body
+func (a *ArticleHandler) Schedule(c echo.Context) error {
+	var req scheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err := validation.Struct(req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	return a.transition(c, func(ctx context.Context, id int64, version int64) (domain.Article, error) {
+		return a.AUsecase.Schedule(ctx, id, version, req.PublishAt)
+	})
+}
+
+// Unschedule will cancel the scheduled publication of the article by given param
+func (a *ArticleHandler) Unschedule(c echo.Context) error {
+	return a.transition(c, func(ctx context.Context, id int64, version int64) (domain.Article, error) {
+		return a.AUsecase.Schedule(ctx, id, version, nil)
+	})
+}
+
+// transition moves the article by given param, at the version of the If-Match
+// header, to another status and responds with the article moved
+func (a *ArticleHandler) transition(c echo.Context, move func(ctx context.Context, id int64, version int64) (domain.Article, error)) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	art, err := move(ctx, int64(idP), version)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(headerETag, formatETag(art.Version))
+	return c.JSON(http.StatusOK, art)
+}
+
+// FetchRevisions will fetch the revisions of the article by given param
+func (a *ArticleHandler) FetchRevisions(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	page := domain.Page{
+		Cursor: c.QueryParam("cursor"),
+		Num:    int64(num),
+		Sort:   domain.SortDirection(c.QueryParam("sort")),
+	}
+	ctx := c.Request().Context()
+
+	list, cursors, err := a.AUsecase.FetchRevisions(ctx, int64(idP), page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(`X-Cursor`, cursors.Next)
+	c.Response().Header().Set(`X-Prev-Cursor`, cursors.Prev)
+	return c.JSON(http.StatusOK, list)
+}
+
+// revisionParams reads the article id and revision number of the path
+func revisionParams(c echo.Context) (id int64, rev int64, err error) {
+	if id, err = strconv.ParseInt(c.Param("id"), 10, 64); err != nil {
+		return 0, 0, errHandle.ErrNotFound
+	}
+	if rev, err = strconv.ParseInt(c.Param("rev"), 10, 64); err != nil || rev < 1 {
+		return 0, 0, errHandle.ErrNotFound
+	}
+	return id, rev, nil
+}
+
+// DiffRevisions will compare the revision by given param with the one given
+// by the against query param, the previous revision by default, line by line
+// or word by word as the by query param says
+func (a *ArticleHandler) DiffRevisions(c echo.Context) error {
+	id, rev, err := revisionParams(c)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, err.Error())
+	}
+
+	against := rev - 1
+	if raw := c.QueryParam("against"); raw != "" {
+		if against, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: errHandle.ErrBadParamInput.Error()})
+		}
+	}
+
+	ctx := c.Request().Context()
+	d, err := a.AUsecase.DiffRevisions(ctx, id, rev, against, domain.DiffUnit(c.QueryParam("by")))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, d)
+}
+
+// RestoreRevision will put back the title and content of the revision by
+// given param, the If-Match header giving the version of the article replaced
+func (a *ArticleHandler) RestoreRevision(c echo.Context) error {
+	id, rev, err := revisionParams(c)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, err.Error())
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	art, err := a.AUsecase.RestoreRevision(ctx, id, rev, version)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(headerETag, formatETag(art.Version))
+	return c.JSON(http.StatusOK, art)
+}
+
 func getStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
@@ -128,8 +590,18 @@ func getStatusCode(err error) int {
 		return http.StatusInternalServerError
 	case errHandle.ErrNotFound:
 		return http.StatusNotFound
-	case errHandle.ErrConflict:
+	case errHandle.ErrConflict, errHandle.ErrInvalidTransition:
 		return http.StatusConflict
+	case errHandle.ErrBadParamInput:
+		return http.StatusBadRequest
+	case errHandle.ErrPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case errHandle.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case errHandle.ErrForbidden:
+		return http.StatusForbidden
+	case errIfMatchRequired:
+		return http.StatusPreconditionRequired
 	default:
 		return http.StatusInternalServerError
 	}
@@ -18,21 +18,32 @@ import (
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
 	articleHttp "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/delivery/http"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
+// fakeArticle fakes a published article field by field, faker being unable
+// to fill the status of its own type
+func fakeArticle(t *testing.T) domain.Article {
+	ar := domain.Article{Status: domain.StatusPublished}
+	for _, field := range []interface{}{&ar.ID, &ar.Title, &ar.Content, &ar.Author, &ar.Categories, &ar.UpdatedAt, &ar.CreatedAt, &ar.Version} {
+		require.NoError(t, faker.FakeData(field))
+	}
+	return ar
+}
+
 func TestFetch(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
-	assert.NoError(t, err)
+	mockArticle := fakeArticle(t)
 	mockUCase := new(mocks.ArticleUsecase)
 	mockListArticle := make([]domain.Article, 0)
 	mockListArticle = append(mockListArticle, mockArticle)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListArticle, "10", nil)
+	page := domain.Page{Cursor: cursor, Num: int64(num), Sort: domain.SortDesc}
+	mockUCase.On("Fetch", mock.Anything, domain.ArticleFilter{}, page).Return(mockListArticle, domain.Cursors{Next: "10", Prev: "8"}, nil)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/article?num=1&sort=desc&cursor="+cursor, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
@@ -45,6 +56,7 @@ func TestFetch(t *testing.T) {
 
 	responseCursor := rec.Header().Get("X-Cursor")
 	assert.Equal(t, "10", responseCursor)
+	assert.Equal(t, "8", rec.Header().Get("X-Prev-Cursor"))
 	assert.Equal(t, http.StatusOK, rec.Code)
 	mockUCase.AssertExpectations(t)
 }
@@ -53,7 +65,8 @@ func TestFetchError(t *testing.T) {
 	mockUCase := new(mocks.ArticleUsecase)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(nil, "", domain.ErrInternalServerError)
+	page := domain.Page{Cursor: cursor, Num: int64(num)}
+	mockUCase.On("Fetch", mock.Anything, domain.ArticleFilter{}, page).Return(nil, domain.Cursors{}, errHandle.ErrInternalServerError)
 
 	e := echo.New()
 	req, err := http.NewRequest(echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
@@ -73,11 +86,92 @@ func TestFetchError(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
-func TestGetByID(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
+func TestFetchFiltered(t *testing.T) {
+	mockUCase := new(mocks.ArticleUsecase)
+	filter := domain.ArticleFilter{
+		Query:         "clean architecture",
+		AuthorID:      3,
+		Category:      "tech",
+		CreatedAfter:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedBefore: time.Date(2021, 2, 1, 12, 0, 0, 0, time.FixedZone("", 7*3600)),
+	}
+	page := domain.Page{Sort: domain.SortRelevance}
+	mockUCase.On("Fetch", mock.Anything, mock.MatchedBy(func(f domain.ArticleFilter) bool {
+		return f.Query == filter.Query && f.AuthorID == filter.AuthorID && f.Category == filter.Category &&
+			f.CreatedAfter.Equal(filter.CreatedAfter) && f.CreatedBefore.Equal(filter.CreatedBefore)
+	}), page).Return([]domain.Article{}, domain.Cursors{}, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/articles?q=clean+architecture&author_id=3&category=tech"+
+		"&created_after=2021-01-01&created_before=2021-02-01T12:00:00%2B07:00&sort=relevance", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	err = handler.FetchArticle(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+
+	for _, query := range []string{"author_id=three", "created_after=yesterday", "created_before=2021-13-01"} {
+		req, err = http.NewRequest(echo.GET, "/articles?"+query, strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec = httptest.NewRecorder()
+		err = handler.FetchArticle(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	mockUCase := new(mocks.ArticleUsecase)
+	query := domain.ArticleSearchQuery{Query: "lari pagi", Category: "sport", AuthorID: 3, From: 10, Num: 5}
+	mockUCase.On("Search", mock.Anything, query).Return(domain.ArticleSearchResult{
+		Total: 11,
+		Hits: []domain.ArticleHit{{
+			Article:    domain.Article{ID: 1, Title: "Lari pagi"},
+			Score:      0.5,
+			Highlights: map[string][]string{"title": {"<mark>Lari</mark> <mark>pagi</mark>"}},
+		}},
+		Facets: map[string][]domain.FacetTerm{domain.FacetCategory: {{Term: "sport", Label: "Olahraga", Count: 11}}},
+	}, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/search/articles?q=lari+pagi&category=sport&author_id=3&from=10&num=5", strings.NewReader(""))
 	assert.NoError(t, err)
 
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	err = handler.Search(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var res domain.ArticleSearchResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, uint64(11), res.Total)
+	assert.Equal(t, []string{"<mark>Lari</mark> <mark>pagi</mark>"}, res.Hits[0].Highlights["title"])
+	assert.Equal(t, "Olahraga", res.Facets[domain.FacetCategory][0].Label)
+	mockUCase.AssertExpectations(t)
+
+	req, err = http.NewRequest(echo.GET, "/search/articles?author_id=three", strings.NewReader(""))
+	assert.NoError(t, err)
+	rec = httptest.NewRecorder()
+	err = handler.Search(e.NewContext(req, rec))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetByID(t *testing.T) {
+	mockArticle := fakeArticle(t)
+
 	mockUCase := new(mocks.ArticleUsecase)
 
 	num := int(mockArticle.ID)
@@ -100,6 +194,40 @@ func TestGetByID(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"`+strconv.FormatInt(mockArticle.Version, 10)+`"`, rec.Header().Get("ETag"))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetBySlug(t *testing.T) {
+	ar := domain.Article{ID: 12, Title: "Teh Manis", Slug: "teh-manis", Content: "Content", Version: 3}
+	mockUCase := new(mocks.ArticleUsecase)
+	mockUCase.On("GetBySlug", mock.Anything, "teh-manis").Return(ar, nil).Once()
+	mockUCase.On("GetBySlug", mock.Anything, "teh").Return(ar, nil).Once()
+	mockUCase.On("GetBySlug", mock.Anything, "kopi").Return(domain.Article{}, errHandle.ErrNotFound).Once()
+
+	e := echo.New()
+	articleHttp.NewArticleHandler(e, mockUCase)
+
+	req := httptest.NewRequest(echo.GET, "/articles/by-slug/teh-manis", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), `"slug":"teh-manis"`)
+
+	req = httptest.NewRequest(echo.GET, "/articles/by-slug/teh", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code, "an old slug")
+	assert.Equal(t, "/articles/by-slug/teh-manis", rec.Header().Get("Location"))
+
+	req = httptest.NewRequest(echo.GET, "/articles/by-slug/kopi", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
 	mockUCase.AssertExpectations(t)
 }
 
@@ -140,32 +268,640 @@ func TestStore(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
+	for name, tc := range map[string]struct {
+		ifMatch string
+		err     error
+		code    int
+	}{
+		"success":          {`"3"`, nil, http.StatusNoContent},
+		"stale-version":    {`"2"`, errHandle.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		"missing-if-match": {``, nil, http.StatusPreconditionRequired},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleUsecase)
+			if tc.ifMatch != "" {
+				version, _ := strconv.ParseInt(strings.Trim(tc.ifMatch, `"`), 10, 64)
+				mockUCase.On("Delete", mock.Anything, int64(12), version).Return(tc.err).Once()
+			}
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.DELETE, "/articles/12", strings.NewReader(""))
+			assert.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("articles/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("12")
+			handler := articleHttp.ArticleHandler{
+				AUsecase: mockUCase,
+			}
+			err = handler.Delete(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.code, rec.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	mockArticle := domain.Article{
+		Title:   "Title",
+		Content: "Content",
+	}
+	j, err := json.Marshal(mockArticle)
 	assert.NoError(t, err)
 
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 12 && ar.Title == mockArticle.Title && ar.Version == 3
+		})).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PUT, "/articles/12", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `"3"`)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Update(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(errHandle.ErrNotFound).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PUT, "/articles/12", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `"3"`)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Update(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-if-match", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PUT, "/articles/12", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Update(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("stale-version", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(errHandle.ErrPreconditionFailed).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PUT, "/articles/12", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `"2"`)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Update(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-body", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PUT, "/articles/12", strings.NewReader(`{"title":"Title"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", `"3"`)
+		req.Header.Set("Accept-Language", "id")
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Update(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, validation.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+		var problem validation.Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, []validation.FieldError{{Field: "content", Code: "required", Message: "content wajib diisi"}}, problem.Errors)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	mockArticle := domain.Article{
+		ID:      12,
+		Title:   "Title",
+		Content: "Content",
+		Author:  domain.Author{ID: 1, Name: "Iman Tumorang"},
+		Version: 3,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(mockArticle, nil).Once()
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 12 && ar.Title == "New Title" && ar.Content == mockArticle.Content && ar.Author.ID == 1 && ar.Version == 3
+		})).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PATCH, "/articles/12", strings.NewReader(`{"title":"New Title","id":99}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, articleHttp.MIMEApplicationMergePatchJSON)
+		req.Header.Set("If-Match", `"3"`)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Patch(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("stale-version", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(mockArticle, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PATCH, "/articles/12", strings.NewReader(`{"title":"New Title"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, articleHttp.MIMEApplicationMergePatchJSON)
+		req.Header.Set("If-Match", `"2"`)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Patch(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("remove-required-field", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(mockArticle, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PATCH, "/articles/12", strings.NewReader(`{"content":null}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, articleHttp.MIMEApplicationMergePatchJSON)
+		req.Header.Set("If-Match", `"3"`)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Patch(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(domain.Article{}, errHandle.ErrNotFound).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PATCH, "/articles/12", strings.NewReader(`{"title":"New Title"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, articleHttp.MIMEApplicationMergePatchJSON)
+		req.Header.Set("If-Match", `"3"`)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Patch(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("unsupported-media-type", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.PATCH, "/articles/12", strings.NewReader(`{"title":"New Title"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("articles/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+		handler := articleHttp.ArticleHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Patch(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestFetchByCategory(t *testing.T) {
+	mockArticle := fakeArticle(t)
 	mockUCase := new(mocks.ArticleUsecase)
+	mockListArticle := []domain.Article{mockArticle}
+	mockUCase.On("FetchByCategory", mock.Anything, "food", domain.Page{Num: 2}).Return(mockListArticle, domain.Cursors{Next: "10"}, nil)
 
-	num := int(mockArticle.ID)
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/categories/food/articles?num=2", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("categories/:tag/articles")
+	c.SetParamNames("tag")
+	c.SetParamValues("food")
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	err = handler.FetchByCategory(c)
+	require.NoError(t, err)
 
-	mockUCase.On("Delete", mock.Anything, int64(num)).Return(nil)
+	assert.Equal(t, "10", rec.Header().Get("X-Cursor"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestPublish(t *testing.T) {
+	mockArticle := fakeArticle(t)
+
+	for name, tc := range map[string]struct {
+		ifMatch string
+		err     error
+		code    int
+	}{
+		"success":            {`"3"`, nil, http.StatusOK},
+		"forbidden":          {`"3"`, errHandle.ErrForbidden, http.StatusForbidden},
+		"invalid-transition": {`"3"`, errHandle.ErrInvalidTransition, http.StatusConflict},
+		"stale-version":      {`"2"`, errHandle.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		"missing-if-match":   {``, nil, http.StatusPreconditionRequired},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleUsecase)
+			if tc.ifMatch != "" {
+				version, _ := strconv.ParseInt(strings.Trim(tc.ifMatch, `"`), 10, 64)
+				mockUCase.On("Publish", mock.Anything, int64(12), version).Return(mockArticle, tc.err).Once()
+			}
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/articles/12/publish", strings.NewReader(""))
+			assert.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("articles/:id/publish")
+			c.SetParamNames("id")
+			c.SetParamValues("12")
+			handler := articleHttp.ArticleHandler{
+				AUsecase: mockUCase,
+			}
+			err = handler.Publish(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.code == http.StatusOK {
+				assert.Equal(t, `"`+strconv.FormatInt(mockArticle.Version, 10)+`"`, rec.Header().Get("ETag"))
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	mockArticle := fakeArticle(t)
+	publishAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		method  string
+		body    string
+		ifMatch string
+		at      *time.Time
+		err     error
+		code    int
+	}{
+		"schedule":         {echo.PUT, `{"publish_at":"2030-01-02T03:04:05Z"}`, `"3"`, &publishAt, nil, http.StatusOK},
+		"unschedule":       {echo.DELETE, ``, `"3"`, nil, nil, http.StatusOK},
+		"in-the-past":      {echo.PUT, `{"publish_at":"2030-01-02T03:04:05Z"}`, `"3"`, &publishAt, errHandle.ErrBadParamInput, http.StatusBadRequest},
+		"published":        {echo.PUT, `{"publish_at":"2030-01-02T03:04:05Z"}`, `"3"`, &publishAt, errHandle.ErrInvalidTransition, http.StatusConflict},
+		"stale-version":    {echo.DELETE, ``, `"2"`, nil, errHandle.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		"missing-if-match": {echo.PUT, `{"publish_at":"2030-01-02T03:04:05Z"}`, ``, nil, nil, http.StatusPreconditionRequired},
+		"missing-time":     {echo.PUT, `{}`, `"3"`, nil, nil, http.StatusBadRequest},
+		"malformed-time":   {echo.PUT, `{"publish_at":"tomorrow"}`, `"3"`, nil, nil, http.StatusUnprocessableEntity},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleUsecase)
+			if tc.at != nil || tc.method == echo.DELETE {
+				version, _ := strconv.ParseInt(strings.Trim(tc.ifMatch, `"`), 10, 64)
+				mockUCase.On("Schedule", mock.Anything, int64(12), version, tc.at).Return(mockArticle, tc.err).Once()
+			}
+
+			e := echo.New()
+			req, err := http.NewRequest(tc.method, "/articles/12/schedule", strings.NewReader(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("articles/:id/schedule")
+			c.SetParamNames("id")
+			c.SetParamValues("12")
+			handler := articleHttp.ArticleHandler{
+				AUsecase: mockUCase,
+			}
+			if tc.method == echo.DELETE {
+				err = handler.Unschedule(c)
+			} else {
+				err = handler.Schedule(c)
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.code == http.StatusOK {
+				assert.Equal(t, `"`+strconv.FormatInt(mockArticle.Version, 10)+`"`, rec.Header().Get("ETag"))
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFetchRevisions(t *testing.T) {
+	revisions := []domain.ArticleRevision{{ArticleID: 12, Number: 1, Title: "Hello", Content: "Content"}}
+	mockUCase := new(mocks.ArticleUsecase)
+	mockUCase.On("FetchRevisions", mock.Anything, int64(12), domain.Page{Num: 5, Cursor: "abc"}).
+		Return(revisions, domain.Cursors{Next: "def"}, nil).Once()
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.DELETE, "/article/"+strconv.Itoa(num), strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/articles/12/revisions?num=5&cursor=abc", strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	c.SetPath("article/:id")
+	c.SetPath("articles/:id/revisions")
 	c.SetParamNames("id")
-	c.SetParamValues(strconv.Itoa(num))
+	c.SetParamValues("12")
 	handler := articleHttp.ArticleHandler{
 		AUsecase: mockUCase,
 	}
-	err = handler.Delete(c)
+	err = handler.FetchRevisions(c)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "def", rec.Header().Get("X-Cursor"))
+	assert.Contains(t, rec.Body.String(), `"rev":1`)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		query   string
+		against int64
+		by      domain.DiffUnit
+		code    int
+	}{
+		"previous-by-default": {"", 2, "", http.StatusOK},
+		"against-and-by":      {"?against=1&by=word", 1, domain.DiffByWord, http.StatusOK},
+		"malformed-against":   {"?against=first", 0, "", http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleUsecase)
+			if tc.code == http.StatusOK {
+				mockUCase.On("DiffRevisions", mock.Anything, int64(12), int64(3), tc.against, tc.by).
+					Return(domain.ArticleRevisionDiff{ArticleID: 12, From: tc.against, To: 3}, nil).Once()
+			}
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.GET, "/articles/12/revisions/3/diff"+tc.query, strings.NewReader(""))
+			assert.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("articles/:id/revisions/:rev/diff")
+			c.SetParamNames("id", "rev")
+			c.SetParamValues("12", "3")
+			handler := articleHttp.ArticleHandler{
+				AUsecase: mockUCase,
+			}
+			err = handler.DiffRevisions(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.code, rec.Code)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	mockArticle := fakeArticle(t)
+
+	for name, tc := range map[string]struct {
+		ifMatch string
+		err     error
+		code    int
+	}{
+		"success":         {`"3"`, nil, http.StatusOK},
+		"stale-version":   {`"2"`, errHandle.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		"unknown-version": {``, nil, http.StatusPreconditionRequired},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleUsecase)
+			if tc.ifMatch != "" {
+				version, _ := strconv.ParseInt(strings.Trim(tc.ifMatch, `"`), 10, 64)
+				mockUCase.On("RestoreRevision", mock.Anything, int64(12), int64(1), version).Return(mockArticle, tc.err).Once()
+			}
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/articles/12/revisions/1/restore", strings.NewReader(""))
+			assert.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("articles/:id/revisions/:rev/restore")
+			c.SetParamNames("id", "rev")
+			c.SetParamValues("12", "1")
+			handler := articleHttp.ArticleHandler{
+				AUsecase: mockUCase,
+			}
+			err = handler.RestoreRevision(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.code == http.StatusOK {
+				assert.Equal(t, `"`+strconv.FormatInt(mockArticle.Version, 10)+`"`, rec.Header().Get("ETag"))
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFetchTrash(t *testing.T) {
+	deletedAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	trashed := []domain.Article{{ID: 12, Title: "Hello", Content: "Content", DeletedAt: &deletedAt}}
+	mockUCase := new(mocks.ArticleUsecase)
+	mockUCase.On("FetchTrash", mock.Anything, int64(3), domain.Page{Num: 5}).
+		Return(trashed, domain.Cursors{Next: "def"}, nil).Once()
+
+	e := echo.New()
+	articleHttp.NewArticleHandler(e, mockUCase)
+
+	req := httptest.NewRequest(echo.GET, "/articles/trash?author_id=3&num=5", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code, "not taken for the article id trash")
+	assert.Equal(t, "def", rec.Header().Get("X-Cursor"))
+	assert.Contains(t, rec.Body.String(), `"deleted_at":"2021-03-04T05:06:07Z"`)
+
+	req = httptest.NewRequest(echo.GET, "/articles/trash?author_id=me", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	mockUCase.AssertExpectations(t)
+}
 
+func TestRestore(t *testing.T) {
+	for name, tc := range map[string]struct {
+		id      string
+		ifMatch string
+		err     error
+		code    int
+	}{
+		"success":          {"12", `"4"`, nil, http.StatusOK},
+		"not-in-the-trash": {"13", `"4"`, errHandle.ErrNotFound, http.StatusNotFound},
+		"stale-version":    {"12", `"3"`, errHandle.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		"missing-if-match": {"12", ``, nil, http.StatusPreconditionRequired},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleUsecase)
+			if tc.ifMatch != "" {
+				id, _ := strconv.ParseInt(tc.id, 10, 64)
+				version, _ := strconv.ParseInt(strings.Trim(tc.ifMatch, `"`), 10, 64)
+				restored := domain.Article{}
+				if tc.err == nil {
+					restored = domain.Article{ID: id, Title: "Hello", Version: version}
+				}
+				mockUCase.On("Restore", mock.Anything, id, version).Return(restored, tc.err).Once()
+			}
+
+			e := echo.New()
+			req, err := http.NewRequest(echo.POST, "/articles/"+tc.id+"/restore", strings.NewReader(""))
+			assert.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("articles/:id/restore")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+			handler := articleHttp.ArticleHandler{
+				AUsecase: mockUCase,
+			}
+			err = handler.Restore(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.code == http.StatusOK {
+				assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+				assert.NotContains(t, rec.Body.String(), "deleted_at")
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
 }
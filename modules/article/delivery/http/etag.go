@@ -0,0 +1,44 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+// errIfMatchRequired will throw if a write request does not say which version it was based on
+var errIfMatchRequired = errors.New("If-Match header is required")
+
+// formatETag renders the article version as a strong entity tag
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch reads the article version the client based its change on
+func parseIfMatch(c echo.Context) (int64, error) {
+	raw := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if raw == "" {
+		return 0, errIfMatchRequired
+	}
+
+	tag := strings.TrimPrefix(raw, "W/")
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, errHandle.ErrPreconditionFailed
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil {
+		return 0, errHandle.ErrPreconditionFailed
+	}
+
+	return version, nil
+}
@@ -1,21 +1,87 @@
 package middleware
 
-import "github.com/labstack/echo"
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
 
 // GoMiddleware represent the data-struct for middleware
 type GoMiddleware struct {
-	// another stuff , may be needed by middleware
+	authUcase domain.AuthUsecase
 }
 
-// CORS will handle the CORS middleware
+var (
+	corsAllowMethods  = strings.Join([]string{echo.GET, echo.POST, echo.PUT, echo.PATCH, echo.DELETE}, ", ")
+	corsAllowHeaders  = strings.Join([]string{echo.HeaderAuthorization, echo.HeaderContentType, "If-Match"}, ", ")
+	corsExposeHeaders = strings.Join([]string{"ETag", "X-Cursor", "X-Prev-Cursor"}, ", ")
+)
+
+// CORS will handle the CORS middleware. Preflight requests are answered here,
+// and every response exposes the headers the API answers with.
 func (m *GoMiddleware) CORS(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+		header := c.Response().Header()
+		header.Set(echo.HeaderAccessControlAllowOrigin, "*")
+
+		req := c.Request()
+		if req.Method == echo.OPTIONS && req.Header.Get(echo.HeaderAccessControlRequestMethod) != "" {
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+			header.Set(echo.HeaderAccessControlAllowMethods, corsAllowMethods)
+			header.Set(echo.HeaderAccessControlAllowHeaders, corsAllowHeaders)
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		header.Set(echo.HeaderAccessControlExposeHeaders, corsExposeHeaders)
+		return next(c)
+	}
+}
+
+// Authenticate will resolve the bearer token of the request into its user and
+// put that user into the request context. Requests without an Authorization
+// header go through anonymously; a header carrying an invalid token is rejected
+// without telling why.
+func (m *GoMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Request().Header.Get(echo.HeaderAuthorization)
+		if header == "" {
+			return next(c)
+		}
+
+		const prefix = "Bearer "
+		if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+			return unauthorized(c, "Authorization header must use the Bearer scheme")
+		}
+
+		req := c.Request()
+		user, err := m.authUcase.Authenticate(req.Context(), header[len(prefix):])
+		if err == errHandle.ErrUnauthorized {
+			return unauthorized(c, errHandle.ErrUnauthorized.Error())
+		}
+		if err != nil {
+			logrus.Error(err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": errHandle.ErrInternalServerError.Error()})
+		}
+
+		c.SetRequest(req.WithContext(domain.NewContextWithUser(req.Context(), user)))
 		return next(c)
 	}
 }
 
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+	return c.JSON(http.StatusUnauthorized, map[string]string{"message": message})
+}
+
 // InitMiddleware initialize the middleware
-func InitMiddleware() *GoMiddleware {
-	return &GoMiddleware{}
+func InitMiddleware(auth domain.AuthUsecase) *GoMiddleware {
+	return &GoMiddleware{
+		authUcase: auth,
+	}
 }
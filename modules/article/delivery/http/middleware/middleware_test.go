@@ -1,29 +1,156 @@
 package middleware_test
 
 import (
+	"errors"
 	"net/http"
 	test "net/http/httptest"
 	"testing"
 
 	"github.com/labstack/echo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/delivery/http/middleware"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 func TestCORS(t *testing.T) {
-	e := echo.New()
-	req := test.NewRequest(echo.GET, "/", nil)
-	res := test.NewRecorder()
-	c := e.NewContext(req, res)
-	m := middleware.InitMiddleware()
-
-	h := m.CORS(echo.HandlerFunc(func(c echo.Context) error {
+	handler := echo.HandlerFunc(func(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
-	}))
+	})
+
+	t.Run("request", func(t *testing.T) {
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		res := test.NewRecorder()
+		c := e.NewContext(req, res)
+		m := middleware.InitMiddleware(nil)
+
+		err := m.CORS(handler)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "ETag, X-Cursor, X-Prev-Cursor", res.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		e := echo.New()
+		req := test.NewRequest(echo.OPTIONS, "/", nil)
+		req.Header.Set("Access-Control-Request-Method", echo.PUT)
+		req.Header.Set("Access-Control-Request-Headers", "authorization, if-match")
+		res := test.NewRecorder()
+		c := e.NewContext(req, res)
+		m := middleware.InitMiddleware(nil)
+
+		err := m.CORS(echo.HandlerFunc(func(c echo.Context) error {
+			t.Fatal("preflight reached the handler")
+			return nil
+		}))(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, res.Header().Get("Access-Control-Allow-Methods"), echo.PUT)
+		assert.Equal(t, "Authorization, Content-Type, If-Match", res.Header().Get("Access-Control-Allow-Headers"))
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	user := domain.User{ID: 1, Username: "iman"}
+	handler := echo.HandlerFunc(func(c echo.Context) error {
+		u, ok := domain.UserFromContext(c.Request().Context())
+		if !ok {
+			return c.String(http.StatusOK, "anonymous")
+		}
+		return c.String(http.StatusOK, u.Username)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+		mockUCase.On("Authenticate", mock.Anything, "token").Return(user, nil).Once()
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer token")
+		res := test.NewRecorder()
+		c := e.NewContext(req, res)
+		m := middleware.InitMiddleware(mockUCase)
+
+		err := m.Authenticate(handler)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "iman", res.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		res := test.NewRecorder()
+		c := e.NewContext(req, res)
+		m := middleware.InitMiddleware(mockUCase)
+
+		err := m.Authenticate(handler)(c)
+		require.NoError(t, err)
+		assert.Equal(t, "anonymous", res.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-token", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+		mockUCase.On("Authenticate", mock.Anything, "token").Return(domain.User{}, errHandle.ErrUnauthorized).Once()
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer token")
+		res := test.NewRecorder()
+		c := e.NewContext(req, res)
+		m := middleware.InitMiddleware(mockUCase)
+
+		err := m.Authenticate(handler)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, "Bearer", res.Header().Get(echo.HeaderWWWAuthenticate))
+		assert.JSONEq(t, `{"message":"Your credentials are not valid"}`, res.Body.String())
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+		mockUCase.On("Authenticate", mock.Anything, "token").Return(domain.User{}, errors.New("dial tcp: connection refused")).Once()
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer token")
+		res := test.NewRecorder()
+		c := e.NewContext(req, res)
+		m := middleware.InitMiddleware(mockUCase)
+
+		err := m.Authenticate(handler)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.NotContains(t, res.Body.String(), "connection refused")
+		assert.Empty(t, res.Header().Get(echo.HeaderWWWAuthenticate))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("wrong-scheme", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Basic aW1hbjpzZWNyZXQ=")
+		res := test.NewRecorder()
+		c := e.NewContext(req, res)
+		m := middleware.InitMiddleware(mockUCase)
 
-	err := h(c)
-	require.NoError(t, err)
-	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+		err := m.Authenticate(handler)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		mockUCase.AssertExpectations(t)
+	})
 }
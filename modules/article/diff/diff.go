@@ -0,0 +1,73 @@
+// Package diff compares two versions of a text line by line or word by word.
+// Words are compared along with the whitespace between them, so that the
+// chunks of a diff put back together give both texts exactly.
+package diff
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+)
+
+// tokens are the words and the runs of whitespace between them
+var tokens = regexp.MustCompile(`\s+|\S+`)
+
+// split cuts text into the units of by, lines keeping their line feed
+func split(text string, by domain.DiffUnit) []string {
+	if text == "" {
+		return []string{}
+	}
+	if by == domain.DiffByWord {
+		return tokens.FindAllString(text, -1)
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// blank reports whether a unit is only whitespace
+func blank(unit string) bool {
+	return strings.TrimSpace(unit) == ""
+}
+
+// Compare returns the chunks turning from into to, compared in units of by.
+// A replaced run is a deletion followed by an insertion.
+func Compare(from, to string, by domain.DiffUnit) []domain.DiffChunk {
+	a, b := split(from, by), split(to, by)
+	res := make([]domain.DiffChunk, 0)
+	add := func(op domain.DiffOp, units []string) {
+		if len(units) == 0 {
+			return
+		}
+		text := strings.Join(units, "")
+		if n := len(res); n > 0 && res[n-1].Op == op {
+			res[n-1].Text += text
+			return
+		}
+		res = append(res, domain.DiffChunk{Op: op, Text: text})
+	}
+
+	// matches start on words and lines with text, blank ones matching only
+	// around them; autojunk would leave out the frequent ones too
+	matcher := difflib.NewMatcherWithJunk(a, b, false, blank)
+	for _, op := range matcher.GetOpCodes() {
+		switch op.Tag {
+		case 'e':
+			add(domain.DiffEqual, a[op.I1:op.I2])
+		case 'd':
+			add(domain.DiffDelete, a[op.I1:op.I2])
+		case 'i':
+			add(domain.DiffInsert, b[op.J1:op.J2])
+		case 'r':
+			add(domain.DiffDelete, a[op.I1:op.I2])
+			add(domain.DiffInsert, b[op.J1:op.J2])
+		}
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/diff"
+)
+
+func TestCompare(t *testing.T) {
+	for name, tc := range map[string]struct {
+		from, to string
+		by       domain.DiffUnit
+		want     []domain.DiffChunk
+	}{
+		"lines": {
+			"one\ntwo\nthree\n", "one\n2\nthree\nfour", domain.DiffByLine,
+			[]domain.DiffChunk{
+				{Op: domain.DiffEqual, Text: "one\n"},
+				{Op: domain.DiffDelete, Text: "two\n"},
+				{Op: domain.DiffInsert, Text: "2\n"},
+				{Op: domain.DiffEqual, Text: "three\n"},
+				{Op: domain.DiffInsert, Text: "four"},
+			},
+		},
+		"words": {
+			"the quick brown fox", "the slow brown  fox jumps", domain.DiffByWord,
+			[]domain.DiffChunk{
+				{Op: domain.DiffEqual, Text: "the "},
+				{Op: domain.DiffDelete, Text: "quick"},
+				{Op: domain.DiffInsert, Text: "slow"},
+				{Op: domain.DiffEqual, Text: " brown"},
+				{Op: domain.DiffDelete, Text: " "},
+				{Op: domain.DiffInsert, Text: "  "},
+				{Op: domain.DiffEqual, Text: "fox"},
+				{Op: domain.DiffInsert, Text: " jumps"},
+			},
+		},
+		"from-nothing": {
+			"", "Hello\n", domain.DiffByLine,
+			[]domain.DiffChunk{{Op: domain.DiffInsert, Text: "Hello\n"}},
+		},
+		"unchanged": {
+			"", "", domain.DiffByWord,
+			[]domain.DiffChunk{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Compare(tc.from, tc.to, tc.by)
+			assert.Equal(t, tc.want, got)
+
+			from, to := "", ""
+			for _, chunk := range got {
+				if chunk.Op != domain.DiffInsert {
+					from += chunk.Text
+				}
+				if chunk.Op != domain.DiffDelete {
+					to += chunk.Text
+				}
+			}
+			assert.Equal(t, tc.from, from)
+			assert.Equal(t, tc.to, to)
+		})
+	}
+}
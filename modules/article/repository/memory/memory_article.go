@@ -0,0 +1,456 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type memoryArticleRepository struct {
+	DB *memdb.DB
+}
+
+// NewMemoryArticleRepository will create an object that represent the article.Repository interface
+func NewMemoryArticleRepository(db *memdb.DB) domain.ArticleRepository {
+	return &memoryArticleRepository{db}
+}
+
+// row returns the article the way the SQL repositories scan it, with only the
+// author id and without categories
+func row(a domain.Article) domain.Article {
+	a.Author = domain.Author{ID: a.Author.ID}
+	a.Categories = nil
+	if a.PublishedAt != nil {
+		publishedAt := *a.PublishedAt
+		a.PublishedAt = &publishedAt
+	}
+	if a.PublishAt != nil {
+		publishAt := *a.PublishAt
+		a.PublishAt = &publishAt
+	}
+	if a.DeletedAt != nil {
+		deletedAt := *a.DeletedAt
+		a.DeletedAt = &deletedAt
+	}
+	return a
+}
+
+// page runs the keyset pagination over the articles matching filter. The
+// caller must hold the read lock.
+func (m *memoryArticleRepository) page(page domain.Page, filter func(a domain.Article) bool) (res []domain.Article, cursors domain.Cursors, err error) {
+	return m.rankedPage(page, func(a domain.Article) (float64, bool) { return 0, filter(a) })
+}
+
+// rankedPage runs the keyset pagination over the articles out of the trash
+// matching filter, which also gives their rank. The caller must hold the read
+// lock.
+func (m *memoryArticleRepository) rankedPage(page domain.Page, filter func(a domain.Article) (float64, bool)) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	ids := make([]int64, 0)
+	ranks := make(map[int64]float64)
+	for id, a := range m.DB.Articles {
+		if a.DeletedAt != nil {
+			continue
+		}
+		if rank, ok := filter(a); ok {
+			ids = append(ids, id)
+			ranks[id] = rank
+		}
+	}
+
+	key := func(id int64) pagination.Key {
+		return pagination.Key{Rank: ranks[id], CreatedAt: m.DB.Articles[id].CreatedAt, ID: id}
+	}
+	ids = memdb.Page(ids, key, keyset)
+	res = make([]domain.Article, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, row(m.DB.Articles[id]))
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return key(res[i].ID)
+	})
+	return res[:n], cursors, nil
+}
+
+// Fetch ranks the articles by how many times the words of the query occur in
+// them, like the SQLite repository
+func (m *memoryArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	categoryID := int64(0)
+	if filter.Category != "" {
+		for _, c := range m.DB.Categories {
+			if c.Tag == filter.Category {
+				categoryID = c.ID
+			}
+		}
+		if categoryID == 0 {
+			return make([]domain.Article, 0), domain.Cursors{}, nil
+		}
+	}
+	words := strings.Fields(strings.ToLower(filter.Query))
+
+	return m.rankedPage(page, func(a domain.Article) (float64, bool) {
+		if filter.AuthorID != 0 && a.Author.ID != filter.AuthorID {
+			return 0, false
+		}
+		if filter.Status != "" && a.Status != filter.Status {
+			return 0, false
+		}
+		if categoryID != 0 {
+			if _, ok := m.DB.ArticleCategories[memdb.ArticleCategory{ArticleID: a.ID, CategoryID: categoryID}]; !ok {
+				return 0, false
+			}
+		}
+		if !filter.CreatedAfter.IsZero() && a.CreatedAt.Before(filter.CreatedAfter) {
+			return 0, false
+		}
+		if !filter.CreatedBefore.IsZero() && !a.CreatedAt.Before(filter.CreatedBefore) {
+			return 0, false
+		}
+		if len(words) == 0 {
+			return 0, true
+		}
+
+		document := strings.ToLower(a.Title + " " + a.Content)
+		rank := 0
+		for _, word := range words {
+			rank += strings.Count(document, word)
+		}
+		return float64(rank), rank > 0
+	})
+}
+
+func (m *memoryArticleRepository) FetchByCategory(ctx context.Context, categoryID int64, status domain.ArticleStatus, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	return m.page(page, func(a domain.Article) bool {
+		_, ok := m.DB.ArticleCategories[memdb.ArticleCategory{ArticleID: a.ID, CategoryID: categoryID}]
+		return ok && (status == "" || a.Status == status)
+	})
+}
+
+func (m *memoryArticleRepository) FetchByAuthor(ctx context.Context, authorID int64, status domain.ArticleStatus, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	return m.page(page, func(a domain.Article) bool {
+		return a.Author.ID == authorID && (status == "" || a.Status == status)
+	})
+}
+
+func (m *memoryArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (n int64, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	for _, a := range m.DB.Articles {
+		if a.Author.ID == authorID {
+			n++
+		}
+	}
+	return
+}
+
+func (m *memoryArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res, ok := m.DB.Articles[id]
+	if !ok || res.DeletedAt != nil {
+		return domain.Article{}, errHandle.ErrNotFound
+	}
+	return row(res), nil
+}
+
+func (m *memoryArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	for _, a := range m.DB.Articles {
+		if a.Title == title && a.DeletedAt == nil && (res.ID == 0 || a.ID < res.ID) {
+			res = a
+		}
+	}
+	if res.ID == 0 {
+		return res, errHandle.ErrNotFound
+	}
+	return row(res), nil
+}
+
+func (m *memoryArticleRepository) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res, ok := m.DB.Articles[m.DB.ArticleSlugs[slug]]
+	if !ok || res.DeletedAt != nil {
+		return domain.Article{}, errHandle.ErrNotFound
+	}
+	return row(res), nil
+}
+
+func (m *memoryArticleRepository) FetchSlugs(ctx context.Context, base string) (map[string]int64, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res := make(map[string]int64)
+	for slug, articleID := range m.DB.ArticleSlugs {
+		if slug == base || strings.HasPrefix(slug, base+"-") {
+			res[slug] = articleID
+		}
+	}
+	return res, nil
+}
+
+func (m *memoryArticleRepository) SetSlug(ctx context.Context, id int64, slug string, at time.Time) error {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	if owner, ok := m.DB.ArticleSlugs[slug]; ok && owner != id {
+		return errHandle.ErrConflict
+	}
+	existing, ok := m.DB.Articles[id]
+	if !ok {
+		return errHandle.ErrNotFound
+	}
+	m.DB.ArticleSlugs[slug] = id
+	existing.Slug = slug
+	m.DB.Articles[id] = existing
+	return nil
+}
+
+func (m *memoryArticleRepository) FetchWithoutSlug(ctx context.Context, num int64) ([]domain.Article, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res := make([]domain.Article, 0)
+	for _, a := range m.DB.Articles {
+		if a.Slug == "" {
+			res = append(res, row(a))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	if int64(len(res)) > num {
+		res = res[:num]
+	}
+	return res, nil
+}
+
+func (m *memoryArticleRepository) FetchDue(ctx context.Context, now time.Time, num int64) ([]domain.Article, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res := make([]domain.Article, 0)
+	for _, a := range m.DB.Articles {
+		if (a.Status == domain.StatusDraft || a.Status == domain.StatusInReview) && a.PublishAt != nil && !a.PublishAt.After(now) && a.DeletedAt == nil {
+			res = append(res, row(a))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].PublishAt.Equal(*res[j].PublishAt) {
+			return res[i].PublishAt.Before(*res[j].PublishAt)
+		}
+		return res[i].ID < res[j].ID
+	})
+	if int64(len(res)) > num {
+		res = res[:num]
+	}
+	return res, nil
+}
+
+// titleTaken reports whether an article out of the trash other than id has
+// the title, regardless of case, like the unique index of the SQL schema. The
+// caller must hold the read lock.
+func (m *memoryArticleRepository) titleTaken(title string, id int64) bool {
+	for _, a := range m.DB.Articles {
+		if a.ID != id && a.DeletedAt == nil && strings.EqualFold(a.Title, title) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	if m.titleTaken(a.Title, 0) {
+		return errHandle.ErrConflict
+	}
+	a.ID = m.DB.NextID("article")
+	stored := row(*a)
+	stored.Slug = ""
+	stored.Version = 1
+	m.DB.Articles[a.ID] = stored
+	return
+}
+
+func (m *memoryArticleRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	existing, ok := m.DB.Articles[id]
+	if !ok || existing.DeletedAt != nil {
+		return errHandle.ErrNotFound
+	}
+	if existing.Version != version {
+		return errHandle.ErrPreconditionFailed
+	}
+	existing.DeletedAt = &deletedAt
+	m.DB.Articles[id] = existing
+	return
+}
+
+func (m *memoryArticleRepository) FetchTrash(ctx context.Context, authorID int64, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	ids := make([]int64, 0)
+	for id, a := range m.DB.Articles {
+		if a.DeletedAt != nil && (authorID == 0 || a.Author.ID == authorID) {
+			ids = append(ids, id)
+		}
+	}
+
+	key := func(id int64) pagination.Key {
+		return pagination.Key{CreatedAt: *m.DB.Articles[id].DeletedAt, ID: id}
+	}
+	ids = memdb.Page(ids, key, keyset)
+	res = make([]domain.Article, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, row(m.DB.Articles[id]))
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return key(res[i].ID)
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *memoryArticleRepository) GetTrashed(ctx context.Context, id int64) (domain.Article, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res, ok := m.DB.Articles[id]
+	if !ok || res.DeletedAt == nil {
+		return domain.Article{}, errHandle.ErrNotFound
+	}
+	return row(res), nil
+}
+
+func (m *memoryArticleRepository) Restore(ctx context.Context, id int64, version int64) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	existing, ok := m.DB.Articles[id]
+	if !ok || existing.DeletedAt == nil {
+		return errHandle.ErrNotFound
+	}
+	if existing.Version != version {
+		return errHandle.ErrPreconditionFailed
+	}
+	if m.titleTaken(existing.Title, id) {
+		return errHandle.ErrConflict
+	}
+	existing.DeletedAt = nil
+	m.DB.Articles[id] = existing
+	return
+}
+
+// Purge removes the category links, revisions and slugs of the articles along
+// with them
+func (m *memoryArticleRepository) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	for id, a := range m.DB.Articles {
+		if a.DeletedAt == nil || !a.DeletedAt.Before(before) {
+			continue
+		}
+		for link := range m.DB.ArticleCategories {
+			if link.ArticleID == id {
+				delete(m.DB.ArticleCategories, link)
+			}
+		}
+		for revID, r := range m.DB.ArticleRevisions {
+			if r.ArticleID == id {
+				delete(m.DB.ArticleRevisions, revID)
+			}
+		}
+		for slug, articleID := range m.DB.ArticleSlugs {
+			if articleID == id {
+				delete(m.DB.ArticleSlugs, slug)
+			}
+		}
+		delete(m.DB.Articles, id)
+		n++
+	}
+	return
+}
+
+func (m *memoryArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	existing, ok := m.DB.Articles[ar.ID]
+	if !ok || existing.DeletedAt != nil {
+		return errHandle.ErrNotFound
+	}
+	if existing.Version != ar.Version {
+		return errHandle.ErrPreconditionFailed
+	}
+	if m.titleTaken(ar.Title, ar.ID) {
+		return errHandle.ErrConflict
+	}
+
+	existing.Title = ar.Title
+	existing.Content = ar.Content
+	existing.Author = domain.Author{ID: ar.Author.ID}
+	existing.UpdatedAt = ar.UpdatedAt
+	existing.Version++
+	m.DB.Articles[ar.ID] = existing
+
+	ar.Version++
+	return
+}
+
+func (m *memoryArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	existing, ok := m.DB.Articles[ar.ID]
+	if !ok || existing.DeletedAt != nil {
+		return errHandle.ErrNotFound
+	}
+	if existing.Version != ar.Version {
+		return errHandle.ErrPreconditionFailed
+	}
+
+	existing.Status = ar.Status
+	copied := row(*ar)
+	existing.PublishedAt = copied.PublishedAt
+	existing.PublishAt = copied.PublishAt
+	existing.UpdatedAt = ar.UpdatedAt
+	existing.Version++
+	m.DB.Articles[ar.ID] = existing
+
+	ar.Version++
+	return
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type memoryRevisionRepository struct {
+	DB *memdb.DB
+}
+
+// NewMemoryArticleRevisionRepository will create an object that represent the ArticleRevision.Repository interface
+func NewMemoryArticleRevisionRepository(db *memdb.DB) domain.ArticleRevisionRepository {
+	return &memoryRevisionRepository{db}
+}
+
+func (m *memoryRevisionRepository) FetchByArticle(ctx context.Context, articleID int64, page domain.Page) (res []domain.ArticleRevision, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	ids := make([]int64, 0)
+	for id, r := range m.DB.ArticleRevisions {
+		if r.ArticleID == articleID {
+			ids = append(ids, id)
+		}
+	}
+
+	ids = memdb.Page(ids, func(id int64) pagination.Key {
+		return pagination.Key{CreatedAt: m.DB.ArticleRevisions[id].CreatedAt, ID: id}
+	}, keyset)
+	res = make([]domain.ArticleRevision, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, m.DB.ArticleRevisions[id])
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *memoryRevisionRepository) GetByNumber(ctx context.Context, articleID int64, number int64) (domain.ArticleRevision, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	for _, r := range m.DB.ArticleRevisions {
+		if r.ArticleID == articleID && r.Number == number {
+			return r, nil
+		}
+	}
+	return domain.ArticleRevision{}, errHandle.ErrNotFound
+}
+
+func (m *memoryRevisionRepository) Store(ctx context.Context, r *domain.ArticleRevision) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	r.Number = 1
+	for _, existing := range m.DB.ArticleRevisions {
+		if existing.ArticleID == r.ArticleID && existing.Number >= r.Number {
+			r.Number = existing.Number + 1
+		}
+	}
+	r.ID = m.DB.NextID("article_revision")
+	m.DB.ArticleRevisions[r.ID] = *r
+	return
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
-	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository"
 	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
@@ -22,10 +25,27 @@ func NewMysqlArticleRepository(Conn *sql.DB) domain.ArticleRepository {
 }
 
 func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Article, err error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	result, _, err = m.fetchRows(ctx, rowPlain, query, args...)
+	return
+}
+
+// rowTail tells what the rows of a query hold after the columns of the article
+type rowTail int
+
+const (
+	rowPlain rowTail = iota
+	// rowRanked rows end with the rank of the article
+	rowRanked
+	// rowTrashed rows end with the time the article was moved to the trash
+	rowTrashed
+)
+
+// fetchRows runs query, whose rows end as tail tells
+func (m *mysqlArticleRepository) fetchRows(ctx context.Context, tail rowTail, query string, args ...interface{}) (result []domain.Article, ranks []float64, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer func() {
@@ -36,56 +56,162 @@ func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args .
 	}()
 
 	result = make([]domain.Article, 0)
+	ranks = make([]float64, 0)
 	for rows.Next() {
 		article := domain.Article{}
 		authorID := int64(0)
-		err = rows.Scan(
+		slug := sql.NullString{}
+		rank := float64(0)
+		dest := []interface{}{
 			&article.ID,
 			&article.Title,
 			&article.Content,
 			&authorID,
+			&article.Status,
+			&article.PublishedAt,
+			&article.PublishAt,
 			&article.UpdatedAt,
 			&article.CreatedAt,
-		)
+			&article.Version,
+			&slug,
+		}
+		switch tail {
+		case rowRanked:
+			dest = append(dest, &rank)
+		case rowTrashed:
+			dest = append(dest, &article.DeletedAt)
+		}
 
+		err = rows.Scan(dest...)
 		if err != nil {
 			logrus.Error(err)
-			return nil, err
+			return nil, nil, err
 		}
+		article.Slug = slug.String
 		article.Author = domain.Author{
 			ID: authorID,
 		}
 		result = append(result, article)
+		ranks = append(ranks, rank)
 	}
 
-	return result, nil
+	return result, ranks, nil
 }
 
-func (m *mysqlArticleRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE created_at > ? ORDER BY created_at LIMIT ? `
+// searchRank is the relevance of an article to the words bound to its
+// placeholder, served by the article_search FULLTEXT index
+const searchRank = `MATCH(a.title, a.content) AGAINST (? IN NATURAL LANGUAGE MODE)`
 
-	decodedCursor, err := repository.DecodeCursor(cursor)
+func (m *mysqlArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	rank, rankArgs := "0", []interface{}{}
+	where, args := []string{`a.deleted_at IS NULL`}, []interface{}{}
+	if filter.Query != "" {
+		rank, rankArgs = searchRank, []interface{}{filter.Query}
+		where = append(where, searchRank)
+		args = append(args, filter.Query)
+	}
+	if filter.AuthorID != 0 {
+		where = append(where, `a.author_id = ?`)
+		args = append(args, filter.AuthorID)
+	}
+	if filter.Status != "" {
+		where = append(where, `a.status = ?`)
+		args = append(args, filter.Status)
+	}
+	if filter.Category != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM article_category ac JOIN category c ON c.id = ac.category_id
+  						WHERE ac.article_id = a.id AND c.tag = ?)`)
+		args = append(args, filter.Category)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		where = append(where, `a.created_at >= ?`)
+		args = append(args, filter.CreatedAfter.UTC())
+	}
+	if !filter.CreatedBefore.IsZero() {
+		where = append(where, `a.created_at < ?`)
+		args = append(args, filter.CreatedBefore.UTC())
+	}
 
-	if err != nil && cursor != "" {
-		return nil, "", errHandle.ErrBadParamInput
+	orderBy := keyset.OrderBy("a.created_at", "a.id")
+	if keyset.Ranked() {
+		where = append(where, `(`+rank+`, a.created_at, a.id) `+keyset.Op()+` (?, ?, ?)`)
+		args = append(args, rankArgs...)
+		orderBy = keyset.OrderBy("score", "a.created_at", "a.id")
+	} else {
+		where = append(where, `(a.created_at, a.id) `+keyset.Op()+` (?, ?)`)
 	}
+	args = append(append(rankArgs, args...), keyset.Args()...)
 
-	res, err = m.fetch(ctx, query, decodedCursor, num)
+	query := `SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug, ` + rank + ` AS score
+  						FROM article a WHERE ` + strings.Join(where, " AND ") + ` ORDER BY ` + orderBy + ` LIMIT ? `
+
+	res, ranks, err := m.fetchRows(ctx, rowRanked, query, args...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{Rank: ranks[i], CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *mysqlArticleRepository) FetchByCategory(ctx context.Context, categoryID int64, status domain.ArticleStatus, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug
+  						FROM article a JOIN article_category ac ON ac.article_id = a.id
+  						WHERE ac.category_id = ? AND a.deleted_at IS NULL AND (? = '' OR a.status = ?) AND (a.created_at, a.id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("a.created_at", "a.id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, append([]interface{}{categoryID, status, status}, keyset.Args()...)...)
 	if err != nil {
-		return nil, "", err
+		return nil, domain.Cursors{}, err
 	}
 
-	if len(res) == int(num) {
-		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *mysqlArticleRepository) FetchByAuthor(ctx context.Context, authorID int64, status domain.ArticleStatus, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE author_id = ? AND deleted_at IS NULL AND (? = '' OR status = ?) AND (created_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, append([]interface{}{authorID, status, status}, keyset.Args()...)...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
 	}
 
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *mysqlArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (n int64, err error) {
+	query := `SELECT COUNT(*) FROM article WHERE author_id = ?`
+
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, authorID).Scan(&n)
 	return
 }
 
 func (m *mysqlArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE ID = ?`
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE ID = ? AND deleted_at IS NULL`
 
 	list, err := m.fetch(ctx, query, id)
 	if err != nil {
@@ -102,8 +228,8 @@ func (m *mysqlArticleRepository) GetByID(ctx context.Context, id int64) (res dom
 }
 
 func (m *mysqlArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE title = ?`
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE title = ? AND deleted_at IS NULL`
 
 	list, err := m.fetch(ctx, query, title)
 	if err != nil {
@@ -118,15 +244,113 @@ func (m *mysqlArticleRepository) GetByTitle(ctx context.Context, title string) (
 	return
 }
 
+func (m *mysqlArticleRepository) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	query := `SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug
+  						FROM article a JOIN article_slug s ON s.article_id = a.id WHERE s.slug = ? AND a.deleted_at IS NULL`
+
+	list, err := m.fetch(ctx, query, slug)
+	if err != nil {
+		return
+	}
+	if len(list) == 0 {
+		return res, errHandle.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *mysqlArticleRepository) FetchSlugs(ctx context.Context, base string) (res map[string]int64, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, `SELECT slug, article_id FROM article_slug WHERE slug = ? OR slug LIKE ?`, base, base+"-%")
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make(map[string]int64)
+	for rows.Next() {
+		var (
+			slug      string
+			articleID int64
+		)
+		if err = rows.Scan(&slug, &articleID); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res[slug] = articleID
+	}
+	return res, rows.Err()
+}
+
+// SetSlug records the slug in a transaction of its own unless ctx carries one
+func (m *mysqlArticleRepository) SetSlug(ctx context.Context, id int64, slug string, at time.Time) error {
+	return transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) error {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		var owner int64
+		err := tx.QueryRowContext(ctx, `SELECT article_id FROM article_slug WHERE slug = ?`, slug).Scan(&owner)
+		switch {
+		case err == sql.ErrNoRows:
+			// another article may get the slug meanwhile; losing the race
+			// inserts nothing instead of failing, which would abort the
+			// transaction the caller tries the next slug in
+			query := `INSERT INTO article_slug (slug, article_id, created_at) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE slug = slug`
+			res, err := tx.ExecContext(ctx, query, slug, id, at)
+			if err != nil {
+				return err
+			}
+			inserted, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if inserted == 0 {
+				return errHandle.ErrConflict
+			}
+		case err != nil:
+			return err
+		case owner != id:
+			return errHandle.ErrConflict
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE article SET slug = ? WHERE id = ?`, slug, id)
+		if transaction.IsUniqueViolation(err) {
+			return errHandle.ErrConflict
+		}
+		return err
+	})
+}
+
+func (m *mysqlArticleRepository) FetchWithoutSlug(ctx context.Context, num int64) ([]domain.Article, error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE slug IS NULL ORDER BY id LIMIT ?`
+
+	return m.fetch(ctx, query, num)
+}
+
+func (m *mysqlArticleRepository) FetchDue(ctx context.Context, now time.Time, num int64) ([]domain.Article, error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE status IN (?, ?) AND publish_at <= ? AND deleted_at IS NULL ORDER BY publish_at, id LIMIT ?`
+
+	return m.fetch(ctx, query, domain.StatusDraft, domain.StatusInReview, now, num)
+}
+
 func (m *mysqlArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
 	fmt.Println(ctx)
-	query := `INSERT  article SET title=? , content=? , author_id=?, updated_at=?, created_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	query := `INSERT  article SET title=? , content=? , author_id=?, status=?, published_at=?, publish_at=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, a.Title, a.Content, a.Author.ID, a.UpdatedAt, a.CreatedAt)
+	res, err := stmt.ExecContext(ctx, a.Title, a.Content, a.Author.ID, a.Status, a.PublishedAt, a.PublishAt, a.UpdatedAt, a.CreatedAt)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
 	if err != nil {
 		return
 	}
@@ -138,41 +362,179 @@ func (m *mysqlArticleRepository) Store(ctx context.Context, a *domain.Article) (
 	return
 }
 
-func (m *mysqlArticleRepository) Delete(ctx context.Context, id int64) (err error) {
-	query := "DELETE FROM article WHERE id = ?"
+func (m *mysqlArticleRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) (err error) {
+	query := "UPDATE article SET deleted_at = ? WHERE id = ? AND version = ? AND deleted_at IS NULL"
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, id)
+	res, err := stmt.ExecContext(ctx, deletedAt, id, version)
 	if err != nil {
 		return
 	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return m.versionMismatch(ctx, id)
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+	return
+}
 
-	rowsAfected, err := res.RowsAffected()
+func (m *mysqlArticleRepository) FetchTrash(ctx context.Context, authorID int64, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug, deleted_at
+  						FROM article WHERE deleted_at IS NOT NULL AND (? = 0 OR author_id = ?) AND (deleted_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("deleted_at", "id") + ` LIMIT ? `
+
+	res, _, err = m.fetchRows(ctx, rowTrashed, query, append([]interface{}{authorID, authorID}, keyset.Args()...)...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: *res[i].DeletedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *mysqlArticleRepository) GetTrashed(ctx context.Context, id int64) (domain.Article, error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug, deleted_at
+  						FROM article WHERE ID = ? AND deleted_at IS NOT NULL`
+
+	list, _, err := m.fetchRows(ctx, rowTrashed, query, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if len(list) == 0 {
+		return domain.Article{}, errHandle.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *mysqlArticleRepository) Restore(ctx context.Context, id int64, version int64) (err error) {
+	query := "UPDATE article SET deleted_at = NULL WHERE id = ? AND version = ? AND deleted_at IS NOT NULL"
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	if rowsAfected != 1 {
-		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAfected)
+	res, err := stmt.ExecContext(ctx, id, version)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
 		return
 	}
+	if affect == 0 {
+		// not in the trash, or at another version
+		if _, err = m.GetTrashed(ctx, id); err != nil {
+			return
+		}
+		return errHandle.ErrPreconditionFailed
+	}
+
+	return checkAffected(res)
+}
+
+// Purge removes the category links, revisions and slugs of the articles along
+// with them, in a transaction of its own unless ctx carries one
+func (m *mysqlArticleRepository) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	err = transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) error {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		trashed := `SELECT id FROM article WHERE deleted_at < ?`
+		if _, err := tx.ExecContext(ctx, `DELETE FROM article_category WHERE article_id IN (`+trashed+`)`, before); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM article_revision WHERE article_id IN (`+trashed+`)`, before); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM article_slug WHERE article_id IN (`+trashed+`)`, before); err != nil {
+			return err
+		}
 
+		res, err := tx.ExecContext(ctx, `DELETE FROM article WHERE deleted_at < ?`, before)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		n = int(affected)
+		return err
+	})
 	return
 }
 
+// checkAffected expects a statement to have changed a single article
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return nil
+}
+
 func (m *mysqlArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
-	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=? WHERE ID = ?`
+	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=?, version=version+1 WHERE ID = ? AND version = ? AND deleted_at IS NULL`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, ar.Version)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return m.versionMismatch(ctx, ar.ID)
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+		return
+	}
+
+	ar.Version++
+	return
+}
+
+func (m *mysqlArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article) (err error) {
+	query := `UPDATE article set status=?, published_at=?, publish_at=?, updated_at=?, version=version+1 WHERE ID = ? AND version = ? AND deleted_at IS NULL`
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID)
+	res, err := stmt.ExecContext(ctx, ar.Status, ar.PublishedAt, ar.PublishAt, ar.UpdatedAt, ar.ID, ar.Version)
 	if err != nil {
 		return
 	}
@@ -180,10 +542,28 @@ func (m *mysqlArticleRepository) Update(ctx context.Context, ar *domain.Article)
 	if err != nil {
 		return
 	}
+	if affect == 0 {
+		return m.versionMismatch(ctx, ar.ID)
+	}
 	if affect != 1 {
 		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
 		return
 	}
 
+	ar.Version++
 	return
 }
+
+// versionMismatch tells apart a missing article from one whose version moved on
+func (m *mysqlArticleRepository) versionMismatch(ctx context.Context, id int64) error {
+	var version int64
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, `SELECT version FROM article WHERE ID = ? AND deleted_at IS NULL`, id).Scan(&version)
+	if err == sql.ErrNoRows {
+		return errHandle.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return errHandle.ErrPreconditionFailed
+}
@@ -2,15 +2,17 @@ package mysql_test
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
-	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository"
 	articleMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/mysql"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 func TestFetch(t *testing.T) {
@@ -30,22 +32,51 @@ func TestFetch(t *testing.T) {
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug", "score"}).
 		AddRow(mockArticles[0].ID, mockArticles[0].Title, mockArticles[0].Content,
-			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt).
+			mockArticles[0].Author.ID, "published", nil, nil, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt, 1, "slug", 0).
 		AddRow(mockArticles[1].ID, mockArticles[1].Title, mockArticles[1].Content,
-			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt)
+			mockArticles[1].Author.ID, "published", nil, nil, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt, 1, "slug", 0)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+	query := "SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug, 0 AS score FROM article a " +
+		"WHERE a.deleted_at IS NULL AND \\(a.created_at, a.id\\) < \\(\\?, \\?\\) ORDER BY a.created_at DESC, a.id DESC LIMIT \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewMysqlArticleRepository(db)
-	cursor := repository.EncodeCursor(mockArticles[1].CreatedAt)
-	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
-	assert.NotEmpty(t, nextCursor)
+	list, cursors, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, domain.Page{Num: 1, Sort: domain.SortDesc})
 	assert.NoError(t, err)
-	assert.Len(t, list, 2)
+	assert.Len(t, list, 1)
+	assert.NotEmpty(t, cursors.Next)
+	assert.Empty(t, cursors.Prev)
+}
+
+func TestFetchSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	createdAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug", "score"}).
+		AddRow(4, "Golang", "Go go go", 1, "published", nil, nil, createdAt, createdAt, 1, "golang", 2.5).
+		AddRow(2, "Gophers", "Go", 1, "published", nil, nil, createdAt, createdAt, 1, "gophers", 0.5)
+
+	match := "MATCH\\(a.title, a.content\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\)"
+	query := "SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug, " + match + " AS score FROM article a " +
+		"WHERE a.deleted_at IS NULL AND " + match + " AND a.author_id = \\? AND a.created_at >= \\? AND " +
+		"\\(" + match + ", a.created_at, a.id\\) < \\(\\?, \\?, \\?\\) ORDER BY score DESC, a.created_at DESC, a.id DESC LIMIT \\?"
+
+	mock.ExpectQuery(query).
+		WithArgs("go", "go", int64(1), createdAt, "go", math.MaxFloat64, sqlmock.AnyArg(), int64(math.MaxInt64), int64(2)).
+		WillReturnRows(rows)
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+	filter := domain.ArticleFilter{Query: "go", AuthorID: 1, CreatedAfter: createdAt}
+	list, cursors, err := a.Fetch(context.TODO(), filter, domain.Page{Num: 1, Sort: domain.SortRelevance})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, int64(4), list[0].ID)
+	assert.NotEmpty(t, cursors.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetByID(t *testing.T) {
@@ -54,10 +85,10 @@ func TestGetByID(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug"}).
+		AddRow(1, "title 1", "Content 1", 1, "published", nil, nil, time.Now(), time.Now(), 1, "title-1")
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE ID = \\?"
+	query := "SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug FROM article WHERE ID = \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewMysqlArticleRepository(db)
@@ -79,15 +110,16 @@ func TestStore(t *testing.T) {
 			ID:   1,
 			Name: "Iman Tumorang",
 		},
+		Status: domain.StatusDraft,
 	}
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?"
+	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, status=\\?, published_at=\\?, publish_at=\\?, updated_at=\\?, created_at=\\?"
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.CreatedAt, ar.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.Status, ar.PublishedAt, ar.PublishAt, ar.UpdatedAt, ar.CreatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
 
 	a := articleMysqlRepo.NewMysqlArticleRepository(db)
 
@@ -96,16 +128,41 @@ func TestStore(t *testing.T) {
 	assert.Equal(t, int64(12), ar.ID)
 }
 
+func TestStoreDuplicateTitle(t *testing.T) {
+	ar := &domain.Article{
+		Title:     "Judul",
+		Content:   "Content",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Author:    domain.Author{ID: 1},
+		Status:    domain.StatusDraft,
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT  article SET title=\\? , content=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Judul' for key 'article_title'"})
+
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.Equal(t, errHandle.ErrConflict, err)
+	assert.Equal(t, int64(0), ar.ID)
+}
+
 func TestGetByTitle(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug"}).
+		AddRow(1, "title 1", "Content 1", 1, "published", nil, nil, time.Now(), time.Now(), 1, "title-1")
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE title = \\?"
+	query := "SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug FROM article WHERE title = \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewMysqlArticleRepository(db)
@@ -122,16 +179,120 @@ func TestDelete(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "DELETE FROM article WHERE id = \\?"
+	now := time.Now()
+	query := "UPDATE article SET deleted_at = \\? WHERE id = \\? AND version = \\? AND deleted_at IS NULL"
 
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(now, 12, 3).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(now, 12, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM article WHERE ID = \\?").WithArgs(12).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(now, 12, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM article WHERE ID = \\?").WithArgs(12).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
 
 	a := articleMysqlRepo.NewMysqlArticleRepository(db)
 
 	num := int64(12)
-	err = a.Delete(context.TODO(), num)
+	err = a.Delete(context.TODO(), num, 3, now)
+	assert.NoError(t, err)
+	err = a.Delete(context.TODO(), num, 3, now)
+	assert.Equal(t, errHandle.ErrNotFound, err, "already in the trash")
+	err = a.Delete(context.TODO(), num, 3, now)
+	assert.Equal(t, errHandle.ErrPreconditionFailed, err, "changed since")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	before := time.Now()
+	trashed := "IN \\(SELECT id FROM article WHERE deleted_at < \\?\\)"
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM article_category WHERE article_id " + trashed).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM article_revision WHERE article_id " + trashed).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec("DELETE FROM article_slug WHERE article_id " + trashed).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM article WHERE deleted_at < \\?").WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+	n, err := a.Purge(context.TODO(), before)
 	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug"}).
+		AddRow(1, "Kopi Susu", "Content 1", 1, "published", nil, nil, time.Now(), time.Now(), 1, "kopi-susu")
+
+	query := "FROM article a JOIN article_slug s ON s.article_id = a.id WHERE s.slug = \\? AND a.deleted_at IS NULL"
+	mock.ExpectQuery(query).WithArgs("kopi").WillReturnRows(rows)
+
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+	anArticle, err := a.GetBySlug(context.TODO(), "kopi")
+	assert.NoError(t, err)
+	assert.Equal(t, "kopi-susu", anArticle.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetSlug(t *testing.T) {
+	at := time.Now()
+	owner := "SELECT article_id FROM article_slug WHERE slug = \\?"
+	update := "UPDATE article SET slug = \\? WHERE id = \\?"
+
+	t.Run("new", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(owner).WithArgs("kopi").WillReturnRows(sqlmock.NewRows([]string{"article_id"}))
+		mock.ExpectExec("INSERT INTO article_slug \\(slug, article_id, created_at\\)").WithArgs("kopi", 12, at).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(update).WithArgs("kopi", 12).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		a := articleMysqlRepo.NewMysqlArticleRepository(db)
+		assert.NoError(t, a.SetSlug(context.TODO(), 12, "kopi", at))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("had-before", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(owner).WithArgs("kopi").WillReturnRows(sqlmock.NewRows([]string{"article_id"}).AddRow(12))
+		mock.ExpectExec(update).WithArgs("kopi", 12).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		a := articleMysqlRepo.NewMysqlArticleRepository(db)
+		assert.NoError(t, a.SetSlug(context.TODO(), 12, "kopi", at))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("taken", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(owner).WithArgs("kopi").WillReturnRows(sqlmock.NewRows([]string{"article_id"}).AddRow(7))
+		mock.ExpectRollback()
+
+		a := articleMysqlRepo.NewMysqlArticleRepository(db)
+		assert.Equal(t, errHandle.ErrConflict, a.SetSlug(context.TODO(), 12, "kopi", at))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -146,6 +307,63 @@ func TestUpdate(t *testing.T) {
 			ID:   1,
 			Name: "Iman Tumorang",
 		},
+		Version: 3,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\?, version=version\\+1 WHERE ID = \\? AND version = \\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, ar.Version).WillReturnResult(sqlmock.NewResult(12, 1))
+
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	err = a.Update(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), ar.Version)
+}
+
+func TestUpdateDuplicateTitle(t *testing.T) {
+	ar := &domain.Article{
+		ID:        12,
+		Title:     "Judul",
+		Content:   "Content",
+		UpdatedAt: time.Now(),
+		Author:    domain.Author{ID: 1},
+		Version:   3,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article set title=\\?, content=\\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, ar.Version).WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Judul' for key 'article_title'"})
+
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	err = a.Update(context.TODO(), ar)
+	assert.Equal(t, errHandle.ErrConflict, err)
+	assert.Equal(t, int64(3), ar.Version)
+}
+
+func TestUpdateNotFound(t *testing.T) {
+	ar := &domain.Article{
+		ID:        12,
+		Title:     "Judul",
+		Content:   "Content",
+		UpdatedAt: time.Now(),
+		Author: domain.Author{
+			ID: 1,
+		},
+		Version: 3,
 	}
 
 	db, mock, err := sqlmock.New()
@@ -153,13 +371,156 @@ func TestUpdate(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\? WHERE ID = \\?"
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\?, version=version\\+1 WHERE ID = \\? AND version = \\?"
 
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, ar.Version).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM article WHERE ID = \\?").WithArgs(ar.ID).WillReturnRows(sqlmock.NewRows([]string{"version"}))
 
 	a := articleMysqlRepo.NewMysqlArticleRepository(db)
 
 	err = a.Update(context.TODO(), ar)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+}
+
+func TestUpdateVersionConflict(t *testing.T) {
+	ar := &domain.Article{
+		ID:        12,
+		Title:     "Judul",
+		Content:   "Content",
+		UpdatedAt: time.Now(),
+		Author: domain.Author{
+			ID: 1,
+		},
+		Version: 3,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\?, version=version\\+1 WHERE ID = \\? AND version = \\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, ar.Version).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM article WHERE ID = \\?").WithArgs(ar.ID).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	err = a.Update(context.TODO(), ar)
+	assert.Equal(t, errHandle.ErrPreconditionFailed, err)
+	assert.Equal(t, int64(3), ar.Version)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{
+		ID:          12,
+		Status:      domain.StatusPublished,
+		PublishedAt: &now,
+		UpdatedAt:   now,
+		Version:     3,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article set status=\\?, published_at=\\?, publish_at=\\?, updated_at=\\?, version=version\\+1 WHERE ID = \\? AND version = \\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(ar.Status, ar.PublishedAt, ar.PublishAt, ar.UpdatedAt, ar.ID, ar.Version).WillReturnResult(sqlmock.NewResult(12, 1))
+
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	err = a.UpdateStatus(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), ar.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchByCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug"}).
+		AddRow(1, "title 1", "Content 1", 1, "published", nil, nil, time.Now(), time.Now(), 1, "title-1")
+
+	query := "SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug FROM article a " +
+		"JOIN article_category ac ON ac.article_id = a.id WHERE ac.category_id = \\? AND a.deleted_at IS NULL AND \\(\\? = '' OR a.status = \\?\\) AND \\(a.created_at, a.id\\) > \\(\\?, \\?\\) " +
+		"ORDER BY a.created_at ASC, a.id ASC LIMIT \\?"
+
+	mock.ExpectQuery(query).WillReturnRows(rows)
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	list, cursors, err := a.FetchByCategory(context.TODO(), int64(3), domain.StatusPublished, domain.Page{Num: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, cursors.Next)
+	assert.Len(t, list, 1)
+}
+
+func TestFetchByAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug"}).
+		AddRow(1, "title 1", "Content 1", 1, "published", nil, nil, time.Now(), time.Now(), 1, "title-1").
+		AddRow(2, "title 2", "Content 2", 1, "published", nil, nil, time.Now(), time.Now(), 1, "title-2")
+
+	query := "SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug FROM article " +
+		"WHERE author_id = \\? AND deleted_at IS NULL AND \\(\\? = '' OR status = \\?\\) AND \\(created_at, id\\) > \\(\\?, \\?\\) ORDER BY created_at ASC, id ASC LIMIT \\?"
+
+	mock.ExpectQuery(query).WillReturnRows(rows)
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	list, cursors, err := a.FetchByAuthor(context.TODO(), int64(1), "", domain.Page{Num: 1})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cursors.Next)
+	assert.Len(t, list, 1)
+}
+
+func TestCountByAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article WHERE author_id = \\?$").WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	n, err := a.CountByAuthor(context.TODO(), int64(1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug"}).
+		AddRow(1, "title 1", "Content 1", 1, "in_review", nil, now.Add(-time.Minute), now, now, 2, "title-1")
+
+	query := "SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug " +
+		"FROM article WHERE status IN \\(\\?, \\?\\) AND publish_at <= \\? AND deleted_at IS NULL ORDER BY publish_at, id LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(domain.StatusDraft, domain.StatusInReview, now, int64(100)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+
+	list, err := a.FetchDue(context.TODO(), now, 100)
 	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, domain.StatusInReview, list[0].Status)
+	assert.NotNil(t, list[0].PublishAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
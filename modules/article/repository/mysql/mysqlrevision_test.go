@@ -0,0 +1,87 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	articleMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/mysql"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestFetchRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	createdAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "article_id", "rev", "title", "content", "user_id", "created_at"}).
+		AddRow(8, 3, 2, "title 2", "content 2", 1, createdAt).
+		AddRow(5, 3, 1, "title 1", "content 1", 0, createdAt)
+
+	query := "SELECT id, article_id, rev, title, content, COALESCE\\(user_id, 0\\), created_at FROM article_revision " +
+		"WHERE article_id = \\? AND \\(created_at, id\\) < \\(\\?, \\?\\) ORDER BY created_at DESC, id DESC LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(int64(3), sqlmock.AnyArg(), sqlmock.AnyArg(), int64(2)).WillReturnRows(rows)
+	r := articleMysqlRepo.NewMysqlArticleRevisionRepository(db)
+	list, cursors, err := r.FetchByArticle(context.TODO(), 3, domain.Page{Num: 1, Sort: domain.SortDesc})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, int64(2), list[0].Number)
+	assert.NotEmpty(t, cursors.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRevisionByNumber(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, article_id, rev, title, content, COALESCE\\(user_id, 0\\), created_at FROM article_revision " +
+		"WHERE article_id = \\? AND rev = \\?"
+
+	rows := sqlmock.NewRows([]string{"id", "article_id", "rev", "title", "content", "user_id", "created_at"}).
+		AddRow(5, 3, 1, "title 1", "content 1", 1, time.Now())
+	mock.ExpectQuery(query).WithArgs(int64(3), int64(1)).WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs(int64(3), int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "rev", "title", "content", "user_id", "created_at"}))
+
+	r := articleMysqlRepo.NewMysqlArticleRevisionRepository(db)
+	rev, err := r.GetByNumber(context.TODO(), 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "title 1", rev.Title)
+
+	_, err = r.GetByNumber(context.TODO(), 3, 9)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rev := &domain.ArticleRevision{ArticleID: 3, Title: "Judul", Content: "Konten", CreatedAt: now}
+
+	query := "INSERT INTO article_revision \\(article_id, rev, title, content, user_id, created_at\\) " +
+		"SELECT \\?, COALESCE\\(MAX\\(rev\\), 0\\) \\+ 1, \\?, \\?, \\?, \\? FROM article_revision WHERE article_id = \\?"
+	mock.ExpectExec(query).WithArgs(int64(3), "Judul", "Konten", nil, now, int64(3)).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectQuery("SELECT rev FROM article_revision WHERE id = \\?").WithArgs(int64(12)).
+		WillReturnRows(sqlmock.NewRows([]string{"rev"}).AddRow(4))
+
+	r := articleMysqlRepo.NewMysqlArticleRevisionRepository(db)
+	err = r.Store(context.TODO(), rev)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), rev.ID)
+	assert.Equal(t, int64(4), rev.Number)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
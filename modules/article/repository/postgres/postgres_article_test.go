@@ -0,0 +1,170 @@
+package postgres_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	articlePostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/postgres"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	createdAt := time.Date(2021, 3, 4, 5, 6, 7, 123456000, time.FixedZone("WIB", 7*3600))
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug", "score"}).
+		AddRow(1, "title 1", "content 1", 1, "published", nil, nil, createdAt, createdAt.Add(-time.Microsecond), 1, "title-1", 0).
+		AddRow(2, "title 2", "content 2", 1, "published", nil, nil, createdAt, createdAt, 1, "title-2", 0).
+		AddRow(3, "title 3", "content 3", 1, "published", nil, nil, createdAt, createdAt, 1, "title-3", 0)
+
+	query := "SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug, 0::float8 AS score FROM article a " +
+		"WHERE a.deleted_at IS NULL AND \\(a.created_at, a.id\\) > \\(\\$1, \\$2\\) ORDER BY a.created_at ASC, a.id ASC LIMIT \\$3"
+
+	mock.ExpectQuery(query).WithArgs(time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), int64(0), int64(3)).WillReturnRows(rows)
+	a := articlePostgresRepo.NewPostgresArticleRepository(db)
+	list, cursors, err := a.Fetch(context.TODO(), domain.ArticleFilter{}, domain.Page{Num: 2})
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.NotEmpty(t, cursors.Next)
+
+	// the cursor keeps the microseconds of the last row, whatever its offset
+	rows = sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug", "score"}).
+		AddRow(3, "title 3", "content 3", 1, "published", nil, nil, createdAt, createdAt, 1, "title-3", 0)
+	mock.ExpectQuery(query).WithArgs(createdAt.UTC(), int64(2), int64(3)).WillReturnRows(rows)
+	list, cursors, err = a.Fetch(context.TODO(), domain.ArticleFilter{}, domain.Page{Cursor: cursors.Next, Num: 2})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Empty(t, cursors.Next)
+	assert.NotEmpty(t, cursors.Prev)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	createdAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "updated_at", "created_at", "version", "slug", "score"}).
+		AddRow(4, "Golang", "Go go go", 1, "published", nil, nil, createdAt, createdAt, 1, "golang", 0.25)
+
+	document := "to_tsvector\\('simple', a.title \\|\\| ' ' \\|\\| a.content\\)"
+	tsquery := "\\(plainto_tsquery\\('simple', \\$1\\) \\|\\| plainto_tsquery\\('simple', \\$2\\)\\)"
+	rank := "ts_rank\\(" + document + ", " + tsquery + "\\)::float8"
+	query := "SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug, " + rank + " AS score FROM article a " +
+		"WHERE a.deleted_at IS NULL AND " + document + " @@ " + tsquery + " AND EXISTS \\(SELECT 1 FROM article_category ac JOIN category c ON c.id = ac.category_id " +
+		"WHERE ac.article_id = a.id AND c.tag = \\$3\\) AND a.created_at < \\$4 AND " +
+		"\\(" + rank + ", a.created_at, a.id\\) < \\(\\$5::float8, \\$6, \\$7\\) ORDER BY score DESC, a.created_at DESC, a.id DESC LIMIT \\$8"
+
+	mock.ExpectQuery(query).
+		WithArgs("go", "gopher", "tech", createdAt, math.MaxFloat64, sqlmock.AnyArg(), int64(math.MaxInt64), int64(3)).
+		WillReturnRows(rows)
+	a := articlePostgresRepo.NewPostgresArticleRepository(db)
+	filter := domain.ArticleFilter{Query: "go gopher", Category: "tech", CreatedBefore: createdAt}
+	list, cursors, err := a.Fetch(context.TODO(), filter, domain.Page{Num: 2, Sort: domain.SortRelevance})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Empty(t, cursors.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{
+		Title:     "Judul",
+		Content:   "Content",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Author: domain.Author{
+			ID:   1,
+			Name: "Iman Tumorang",
+		},
+		Status: domain.StatusDraft,
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO article \\(title, content, author_id, status, published_at, publish_at, updated_at, created_at\\) " +
+		"VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8\\) RETURNING id"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.Status, ar.PublishedAt, ar.PublishAt, ar.UpdatedAt, ar.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+	a := articlePostgresRepo.NewPostgresArticleRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), ar.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{
+		ID:        12,
+		Title:     "Judul",
+		Content:   "Content",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   3,
+		Author:    domain.Author{ID: 1},
+	}
+
+	t.Run("version-conflict", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		query := "UPDATE article set title=\\$1, content=\\$2, author_id=\\$3, updated_at=\\$4, version=version\\+1 WHERE ID = \\$5 AND version = \\$6"
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, ar.Version).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version FROM article WHERE ID = \\$1").WithArgs(ar.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+
+		a := articlePostgresRepo.NewPostgresArticleRepository(db)
+
+		err = a.Update(context.TODO(), ar)
+		assert.Equal(t, errHandle.ErrPreconditionFailed, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateStatus(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Article{
+		ID:          12,
+		Status:      domain.StatusPublished,
+		PublishedAt: &now,
+		UpdatedAt:   now,
+		Version:     3,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article set status=\\$1, published_at=\\$2, publish_at=\\$3, updated_at=\\$4, version=version\\+1 WHERE ID = \\$5 AND version = \\$6"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(ar.Status, ar.PublishedAt, ar.PublishAt, ar.UpdatedAt, ar.ID, ar.Version).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := articlePostgresRepo.NewPostgresArticleRepository(db)
+
+	err = a.UpdateStatus(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), ar.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
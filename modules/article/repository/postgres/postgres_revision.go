@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type postgresRevisionRepository struct {
+	Conn *sql.DB
+}
+
+// NewPostgresArticleRevisionRepository will create an object that represent the ArticleRevision.Repository interface
+func NewPostgresArticleRevisionRepository(Conn *sql.DB) domain.ArticleRevisionRepository {
+	return &postgresRevisionRepository{Conn}
+}
+
+func (m *postgresRevisionRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.ArticleRevision, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.ArticleRevision, 0)
+	for rows.Next() {
+		r := domain.ArticleRevision{}
+		err = rows.Scan(
+			&r.ID,
+			&r.ArticleID,
+			&r.Number,
+			&r.Title,
+			&r.Content,
+			&r.UserID,
+			&r.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func (m *postgresRevisionRepository) FetchByArticle(ctx context.Context, articleID int64, page domain.Page) (res []domain.ArticleRevision, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id, article_id, rev, title, content, COALESCE(user_id, 0), created_at
+  						FROM article_revision WHERE article_id = $1 AND (created_at, id) ` + keyset.Op() + ` ($2, $3) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT $4 `
+
+	res, err = m.fetch(ctx, query, append([]interface{}{articleID}, keyset.Args()...)...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *postgresRevisionRepository) GetByNumber(ctx context.Context, articleID int64, number int64) (res domain.ArticleRevision, err error) {
+	query := `SELECT id, article_id, rev, title, content, COALESCE(user_id, 0), created_at
+  						FROM article_revision WHERE article_id = $1 AND rev = $2`
+
+	list, err := m.fetch(ctx, query, articleID, number)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	if len(list) == 0 {
+		return domain.ArticleRevision{}, errHandle.ErrNotFound
+	}
+	return list[0], nil
+}
+
+// Store numbers the revision in the insert itself. Stored within the
+// transaction updating the article, whose row lock makes the updates of an
+// article wait for each other, two revisions never get the same number.
+func (m *postgresRevisionRepository) Store(ctx context.Context, r *domain.ArticleRevision) error {
+	var userID interface{}
+	if r.UserID != 0 {
+		userID = r.UserID
+	}
+
+	query := `INSERT INTO article_revision (article_id, rev, title, content, user_id, created_at)
+  						SELECT $1::bigint, COALESCE(MAX(rev), 0) + 1, $2::text, $3::text, $4::bigint, $5::timestamptz FROM article_revision WHERE article_id = $1 RETURNING id, rev`
+	return transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, r.ArticleID, r.Title, r.Content, userID, r.CreatedAt).Scan(&r.ID, &r.Number)
+}
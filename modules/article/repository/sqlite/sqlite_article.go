@@ -0,0 +1,583 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type sqliteArticleRepository struct {
+	Conn *sql.DB
+}
+
+// NewSqliteArticleRepository will create an object that represent the article.Repository interface
+func NewSqliteArticleRepository(Conn *sql.DB) domain.ArticleRepository {
+	return &sqliteArticleRepository{Conn}
+}
+
+func (m *sqliteArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Article, err error) {
+	result, _, err = m.fetchRows(ctx, rowPlain, query, args...)
+	return
+}
+
+// rowTail tells what the rows of a query hold after the columns of the article
+type rowTail int
+
+const (
+	rowPlain rowTail = iota
+	// rowRanked rows end with the rank of the article
+	rowRanked
+	// rowTrashed rows end with the time the article was moved to the trash
+	rowTrashed
+)
+
+// fetchRows runs query, whose rows end as tail tells
+func (m *sqliteArticleRepository) fetchRows(ctx context.Context, tail rowTail, query string, args ...interface{}) (result []domain.Article, ranks []float64, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Article, 0)
+	ranks = make([]float64, 0)
+	for rows.Next() {
+		article := domain.Article{}
+		authorID := int64(0)
+		slug := sql.NullString{}
+		rank := float64(0)
+		dest := []interface{}{
+			&article.ID,
+			&article.Title,
+			&article.Content,
+			&authorID,
+			&article.Status,
+			&article.PublishedAt,
+			&article.PublishAt,
+			&article.UpdatedAt,
+			&article.CreatedAt,
+			&article.Version,
+			&slug,
+		}
+		switch tail {
+		case rowRanked:
+			dest = append(dest, &rank)
+		case rowTrashed:
+			dest = append(dest, &article.DeletedAt)
+		}
+
+		err = rows.Scan(dest...)
+		if err != nil {
+			logrus.Error(err)
+			return nil, nil, err
+		}
+		article.Slug = slug.String
+		article.Author = domain.Author{
+			ID: authorID,
+		}
+		result = append(result, article)
+		ranks = append(ranks, rank)
+	}
+
+	return result, ranks, nil
+}
+
+// searchDocument is the text searched in an article. SQLite has no full-text
+// index without the FTS5 extension, so articles are scanned for the words and
+// ranked by how many times they occur.
+const searchDocument = `lower(a.title || ' ' || a.content)`
+
+func (m *sqliteArticleRepository) Fetch(ctx context.Context, filter domain.ArticleFilter, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	args := []interface{}{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "?" + strconv.Itoa(len(args))
+	}
+
+	rank, where := "0.0", []string{`a.deleted_at IS NULL`}
+	if words := strings.Fields(strings.ToLower(filter.Query)); len(words) > 0 {
+		matches := make([]string, len(words))
+		counts := make([]string, len(words))
+		for i, word := range words {
+			w := arg(word)
+			matches[i] = `instr(` + searchDocument + `, ` + w + `) > 0`
+			counts[i] = `(length(` + searchDocument + `) - length(replace(` + searchDocument + `, ` + w + `, ''))) / CAST(length(` + w + `) AS REAL)`
+		}
+		rank = `(` + strings.Join(counts, " + ") + `)`
+		where = append(where, `(`+strings.Join(matches, " OR ")+`)`)
+	}
+	if filter.AuthorID != 0 {
+		where = append(where, `a.author_id = `+arg(filter.AuthorID))
+	}
+	if filter.Status != "" {
+		where = append(where, `a.status = `+arg(filter.Status))
+	}
+	if filter.Category != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM article_category ac JOIN category c ON c.id = ac.category_id
+  						WHERE ac.article_id = a.id AND c.tag = `+arg(filter.Category)+`)`)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		where = append(where, `a.created_at >= `+arg(filter.CreatedAfter.UTC()))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		where = append(where, `a.created_at < `+arg(filter.CreatedBefore.UTC()))
+	}
+
+	position := keyset.Args()
+	orderBy := keyset.OrderBy("a.created_at", "a.id")
+	if keyset.Ranked() {
+		where = append(where, `(`+rank+`, a.created_at, a.id) `+keyset.Op()+` (`+arg(position[0])+`, `+arg(position[1])+`, `+arg(position[2])+`)`)
+		orderBy = keyset.OrderBy("score", "a.created_at", "a.id")
+	} else {
+		where = append(where, `(a.created_at, a.id) `+keyset.Op()+` (`+arg(position[0])+`, `+arg(position[1])+`)`)
+	}
+
+	query := `SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug, ` + rank + ` AS score
+  						FROM article a WHERE ` + strings.Join(where, " AND ") + ` ORDER BY ` + orderBy + ` LIMIT ` + arg(keyset.Limit())
+
+	res, ranks, err := m.fetchRows(ctx, rowRanked, query, args...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{Rank: ranks[i], CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *sqliteArticleRepository) FetchByCategory(ctx context.Context, categoryID int64, status domain.ArticleStatus, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug
+  						FROM article a JOIN article_category ac ON ac.article_id = a.id
+  						WHERE ac.category_id = ? AND a.deleted_at IS NULL AND (? = '' OR a.status = ?) AND (a.created_at, a.id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("a.created_at", "a.id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, append([]interface{}{categoryID, status, status}, keyset.Args()...)...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *sqliteArticleRepository) FetchByAuthor(ctx context.Context, authorID int64, status domain.ArticleStatus, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE author_id = ? AND deleted_at IS NULL AND (? = '' OR status = ?) AND (created_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, append([]interface{}{authorID, status, status}, keyset.Args()...)...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *sqliteArticleRepository) CountByAuthor(ctx context.Context, authorID int64) (n int64, err error) {
+	query := `SELECT COUNT(*) FROM article WHERE author_id = ?`
+
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, authorID).Scan(&n)
+	return
+}
+
+func (m *sqliteArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE ID = ? AND deleted_at IS NULL`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, errHandle.ErrNotFound
+	}
+
+	return
+}
+
+func (m *sqliteArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE title = ? AND deleted_at IS NULL`
+
+	list, err := m.fetch(ctx, query, title)
+	if err != nil {
+		return
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, errHandle.ErrNotFound
+	}
+	return
+}
+
+func (m *sqliteArticleRepository) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	query := `SELECT a.id,a.title,a.content, a.author_id, a.status, a.published_at, a.publish_at, a.updated_at, a.created_at, a.version, a.slug
+  						FROM article a JOIN article_slug s ON s.article_id = a.id WHERE s.slug = ? AND a.deleted_at IS NULL`
+
+	list, err := m.fetch(ctx, query, slug)
+	if err != nil {
+		return
+	}
+	if len(list) == 0 {
+		return res, errHandle.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *sqliteArticleRepository) FetchSlugs(ctx context.Context, base string) (res map[string]int64, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, `SELECT slug, article_id FROM article_slug WHERE slug = ? OR slug LIKE ?`, base, base+"-%")
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make(map[string]int64)
+	for rows.Next() {
+		var (
+			slug      string
+			articleID int64
+		)
+		if err = rows.Scan(&slug, &articleID); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res[slug] = articleID
+	}
+	return res, rows.Err()
+}
+
+// SetSlug records the slug in a transaction of its own unless ctx carries one
+func (m *sqliteArticleRepository) SetSlug(ctx context.Context, id int64, slug string, at time.Time) error {
+	return transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) error {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		var owner int64
+		err := tx.QueryRowContext(ctx, `SELECT article_id FROM article_slug WHERE slug = ?`, slug).Scan(&owner)
+		switch {
+		case err == sql.ErrNoRows:
+			// another article may get the slug meanwhile; losing the race
+			// inserts nothing instead of failing, which would abort the
+			// transaction the caller tries the next slug in
+			query := `INSERT INTO article_slug (slug, article_id, created_at) VALUES (?, ?, ?) ON CONFLICT (slug) DO NOTHING`
+			res, err := tx.ExecContext(ctx, query, slug, id, at.UTC())
+			if err != nil {
+				return err
+			}
+			inserted, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if inserted == 0 {
+				return errHandle.ErrConflict
+			}
+		case err != nil:
+			return err
+		case owner != id:
+			return errHandle.ErrConflict
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE article SET slug = ? WHERE id = ?`, slug, id)
+		if transaction.IsUniqueViolation(err) {
+			return errHandle.ErrConflict
+		}
+		return err
+	})
+}
+
+func (m *sqliteArticleRepository) FetchWithoutSlug(ctx context.Context, num int64) ([]domain.Article, error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE slug IS NULL ORDER BY id LIMIT ?`
+
+	return m.fetch(ctx, query, num)
+}
+
+func (m *sqliteArticleRepository) FetchDue(ctx context.Context, now time.Time, num int64) ([]domain.Article, error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug
+  						FROM article WHERE status IN (?, ?) AND publish_at <= ? AND deleted_at IS NULL ORDER BY publish_at, id LIMIT ?`
+
+	return m.fetch(ctx, query, domain.StatusDraft, domain.StatusInReview, now.UTC(), num)
+}
+
+func (m *sqliteArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	query := `INSERT INTO article (title, content, author_id, status, published_at, publish_at, updated_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Title, a.Content, a.Author.ID, a.Status, nullableUTC(a.PublishedAt), nullableUTC(a.PublishAt), a.UpdatedAt.UTC(), a.CreatedAt.UTC())
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	return
+}
+
+func (m *sqliteArticleRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) (err error) {
+	query := "UPDATE article SET deleted_at = ? WHERE id = ? AND version = ? AND deleted_at IS NULL"
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, deletedAt.UTC(), id, version)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return m.versionMismatch(ctx, id)
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+	return
+}
+
+func (m *sqliteArticleRepository) FetchTrash(ctx context.Context, authorID int64, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug, deleted_at
+  						FROM article WHERE deleted_at IS NOT NULL AND (? = 0 OR author_id = ?) AND (deleted_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("deleted_at", "id") + ` LIMIT ? `
+
+	res, _, err = m.fetchRows(ctx, rowTrashed, query, append([]interface{}{authorID, authorID}, keyset.Args()...)...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: *res[i].DeletedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *sqliteArticleRepository) GetTrashed(ctx context.Context, id int64) (domain.Article, error) {
+	query := `SELECT id,title,content, author_id, status, published_at, publish_at, updated_at, created_at, version, slug, deleted_at
+  						FROM article WHERE ID = ? AND deleted_at IS NOT NULL`
+
+	list, _, err := m.fetchRows(ctx, rowTrashed, query, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if len(list) == 0 {
+		return domain.Article{}, errHandle.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *sqliteArticleRepository) Restore(ctx context.Context, id int64, version int64) (err error) {
+	query := "UPDATE article SET deleted_at = NULL WHERE id = ? AND version = ? AND deleted_at IS NOT NULL"
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id, version)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		// not in the trash, or at another version
+		if _, err = m.GetTrashed(ctx, id); err != nil {
+			return
+		}
+		return errHandle.ErrPreconditionFailed
+	}
+
+	return checkAffected(res)
+}
+
+// Purge removes the category links, revisions and slugs of the articles along
+// with them, in a transaction of its own unless ctx carries one
+func (m *sqliteArticleRepository) Purge(ctx context.Context, before time.Time) (n int, err error) {
+	err = transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) error {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		trashed := `SELECT id FROM article WHERE deleted_at < ?`
+		if _, err := tx.ExecContext(ctx, `DELETE FROM article_category WHERE article_id IN (`+trashed+`)`, before.UTC()); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM article_revision WHERE article_id IN (`+trashed+`)`, before.UTC()); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM article_slug WHERE article_id IN (`+trashed+`)`, before.UTC()); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM article WHERE deleted_at < ?`, before.UTC())
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		n = int(affected)
+		return err
+	})
+	return
+}
+
+// checkAffected expects a statement to have changed a single article
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return nil
+}
+
+func (m *sqliteArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=?, version=version+1 WHERE ID = ? AND version = ? AND deleted_at IS NULL`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt.UTC(), ar.ID, ar.Version)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return m.versionMismatch(ctx, ar.ID)
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+		return
+	}
+
+	ar.Version++
+	return
+}
+
+func (m *sqliteArticleRepository) UpdateStatus(ctx context.Context, ar *domain.Article) (err error) {
+	query := `UPDATE article set status=?, published_at=?, publish_at=?, updated_at=?, version=version+1 WHERE ID = ? AND version = ? AND deleted_at IS NULL`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, ar.Status, nullableUTC(ar.PublishedAt), nullableUTC(ar.PublishAt), ar.UpdatedAt.UTC(), ar.ID, ar.Version)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return m.versionMismatch(ctx, ar.ID)
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+		return
+	}
+
+	ar.Version++
+	return
+}
+
+// nullableUTC stores an optional time in UTC like the other times
+func nullableUTC(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// versionMismatch tells apart a missing article from one whose version moved on
+func (m *sqliteArticleRepository) versionMismatch(ctx context.Context, id int64) error {
+	var version int64
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, `SELECT version FROM article WHERE ID = ? AND deleted_at IS NULL`, id).Scan(&version)
+	if err == sql.ErrNoRows {
+		return errHandle.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return errHandle.ErrPreconditionFailed
+}
@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type sqliteRevisionRepository struct {
+	Conn *sql.DB
+}
+
+// NewSqliteArticleRevisionRepository will create an object that represent the ArticleRevision.Repository interface
+func NewSqliteArticleRevisionRepository(Conn *sql.DB) domain.ArticleRevisionRepository {
+	return &sqliteRevisionRepository{Conn}
+}
+
+func (m *sqliteRevisionRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.ArticleRevision, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.ArticleRevision, 0)
+	for rows.Next() {
+		r := domain.ArticleRevision{}
+		err = rows.Scan(
+			&r.ID,
+			&r.ArticleID,
+			&r.Number,
+			&r.Title,
+			&r.Content,
+			&r.UserID,
+			&r.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func (m *sqliteRevisionRepository) FetchByArticle(ctx context.Context, articleID int64, page domain.Page) (res []domain.ArticleRevision, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id, article_id, rev, title, content, COALESCE(user_id, 0), created_at
+  						FROM article_revision WHERE article_id = ? AND (created_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, append([]interface{}{articleID}, keyset.Args()...)...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *sqliteRevisionRepository) GetByNumber(ctx context.Context, articleID int64, number int64) (res domain.ArticleRevision, err error) {
+	query := `SELECT id, article_id, rev, title, content, COALESCE(user_id, 0), created_at
+  						FROM article_revision WHERE article_id = ? AND rev = ?`
+
+	list, err := m.fetch(ctx, query, articleID, number)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	if len(list) == 0 {
+		return domain.ArticleRevision{}, errHandle.ErrNotFound
+	}
+	return list[0], nil
+}
+
+// Store numbers the revision in the insert itself. Stored within the
+// transaction updating the article, whose row lock makes the updates of an
+// article wait for each other, two revisions never get the same number.
+func (m *sqliteRevisionRepository) Store(ctx context.Context, r *domain.ArticleRevision) (err error) {
+	var userID interface{}
+	if r.UserID != 0 {
+		userID = r.UserID
+	}
+
+	conn := transaction.Conn(ctx, m.Conn)
+	query := `INSERT INTO article_revision (article_id, rev, title, content, user_id, created_at)
+  						SELECT ?, COALESCE(MAX(rev), 0) + 1, ?, ?, ?, ? FROM article_revision WHERE article_id = ?`
+	res, err := conn.ExecContext(ctx, query, r.ArticleID, r.Title, r.Content, userID, r.CreatedAt.UTC(), r.ArticleID)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+
+	if err = conn.QueryRowContext(ctx, `SELECT rev FROM article_revision WHERE id = ?`, lastID).Scan(&r.Number); err != nil {
+		return
+	}
+	r.ID = lastID
+	return
+}
@@ -0,0 +1,120 @@
+// Package scheduler runs the periodic jobs on the articles, publishing the
+// ones whose scheduled publication time has come and purging the trash, from
+// goroutines running beside the HTTP server.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+)
+
+// Clock tells the time and waits for it, so that tests can drive the scheduler
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock is the Clock of the wall time
+var SystemClock Clock = systemClock{}
+
+// Job is a periodic job run at the time now, telling how many articles it
+// went through
+type Job func(ctx context.Context, now time.Time) (int, error)
+
+// PublishDue is the Job publishing the due articles
+func PublishDue(articles domain.ArticleUsecase) Job {
+	return articles.PublishDue
+}
+
+// PurgeTrash is the Job removing for good the articles in the trash for longer
+// than retention
+func PurgeTrash(articles domain.ArticleUsecase, retention time.Duration) Job {
+	return func(ctx context.Context, now time.Time) (int, error) {
+		return articles.PurgeTrash(ctx, now.Add(-retention))
+	}
+}
+
+// Scheduler runs a job every interval, once Started and until Stopped. The
+// job runs under the lock of the scheduler's name, so that a single replica
+// runs it at a time.
+type Scheduler struct {
+	name     string
+	job      Job
+	locker   domain.Locker
+	clock    Clock
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewScheduler will create a Scheduler named name running job every interval
+func NewScheduler(name string, job Job, locker domain.Locker, clock Clock, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		name:     name,
+		job:      job,
+		locker:   locker,
+		clock:    clock,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler in a goroutine of its own
+func (s *Scheduler) Start() {
+	go func() {
+		defer close(s.done)
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-s.clock.After(s.interval):
+				s.run()
+			}
+		}
+	}()
+}
+
+// Stop lets a run in progress finish and stops the scheduler, giving up on
+// waiting when ctx is done. A scheduler is only stopped once.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	close(s.stop)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run runs the job unless another replica is at it
+func (s *Scheduler) run() {
+	ctx := context.Background()
+	unlock, ok, err := s.locker.TryLock(ctx, s.name)
+	if err != nil {
+		logrus.Errorf("%s: taking the lock: %v", s.name, err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	n, err := s.job(ctx, s.clock.Now())
+	if err != nil {
+		logrus.Errorf("%s: %v", s.name, err)
+	}
+	if n > 0 {
+		logrus.Infof("%s: went through %d articles", s.name, n)
+	}
+}
@@ -0,0 +1,162 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/scheduler"
+)
+
+// fakeClock is a Clock whose time only moves on Advance. Every wait started
+// is announced on waiting, so tests know when the scheduler is idle.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []fakeTimer
+	waiting chan struct{}
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, waiting: make(chan struct{}, 10)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	timer := fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	c.mu.Unlock()
+
+	c.waiting <- struct{}{}
+	return timer.c
+}
+
+// Advance moves the time forward, firing the timers that are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	c.timers = pending
+}
+
+// newLocker returns a Locker always handing the lock, counting its releases
+func newLocker(released *int) *mocks.Locker {
+	locker := new(mocks.Locker)
+	locker.On("TryLock", mock.Anything, "article-scheduler").Return(func() { *released++ }, true, nil)
+	return locker
+}
+
+func TestScheduler(t *testing.T) {
+	start := time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC)
+
+	t.Run("publishes-every-interval", func(t *testing.T) {
+		clock := newFakeClock(start)
+		released := 0
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("PublishDue", mock.Anything, start.Add(time.Minute)).Return(2, nil).Once()
+		mockUCase.On("PublishDue", mock.Anything, start.Add(2*time.Minute)).Return(0, errors.New("Unexpected")).Once()
+		s := scheduler.NewScheduler("article-scheduler", scheduler.PublishDue(mockUCase), newLocker(&released), clock, time.Minute)
+
+		s.Start()
+		<-clock.waiting
+		clock.Advance(30 * time.Second)
+		mockUCase.AssertNotCalled(t, "PublishDue", mock.Anything, mock.Anything)
+		clock.Advance(30 * time.Second)
+		<-clock.waiting
+		clock.Advance(time.Minute)
+		<-clock.waiting
+
+		assert.NoError(t, s.Stop(context.TODO()))
+		assert.Equal(t, 2, released)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("lock-held-by-another-replica", func(t *testing.T) {
+		clock := newFakeClock(start)
+		mockUCase := new(mocks.ArticleUsecase)
+		locker := new(mocks.Locker)
+		locker.On("TryLock", mock.Anything, "article-scheduler").Return(nil, false, nil).Once()
+		s := scheduler.NewScheduler("article-scheduler", scheduler.PublishDue(mockUCase), locker, clock, time.Minute)
+
+		s.Start()
+		<-clock.waiting
+		clock.Advance(time.Minute)
+		<-clock.waiting
+
+		assert.NoError(t, s.Stop(context.TODO()))
+		locker.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "PublishDue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stop-waits-for-the-run", func(t *testing.T) {
+		clock := newFakeClock(start)
+		released := 0
+		running, finish := make(chan struct{}), make(chan struct{})
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("PublishDue", mock.Anything, start.Add(time.Minute)).Run(func(args mock.Arguments) {
+			close(running)
+			<-finish
+		}).Return(1, nil).Once()
+		s := scheduler.NewScheduler("article-scheduler", scheduler.PublishDue(mockUCase), newLocker(&released), clock, time.Minute)
+
+		s.Start()
+		<-clock.waiting
+		clock.Advance(time.Minute)
+		<-running
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		assert.Equal(t, context.Canceled, s.Stop(ctx), "gives up waiting once ctx is done")
+		assert.Equal(t, 0, released)
+
+		close(finish)
+		<-clock.waiting
+		assert.Equal(t, 1, released, "the run finished")
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("purges-the-trash", func(t *testing.T) {
+		clock := newFakeClock(start)
+		released := 0
+		locker := new(mocks.Locker)
+		locker.On("TryLock", mock.Anything, "article-purge").Return(func() { released++ }, true, nil).Once()
+		mockUCase := new(mocks.ArticleUsecase)
+		mockUCase.On("PurgeTrash", mock.Anything, start.Add(time.Minute-24*time.Hour)).Return(3, nil).Once()
+		s := scheduler.NewScheduler("article-purge", scheduler.PurgeTrash(mockUCase, 24*time.Hour), locker, clock, time.Minute)
+
+		s.Start()
+		<-clock.waiting
+		clock.Advance(time.Minute)
+		<-clock.waiting
+
+		assert.NoError(t, s.Stop(context.TODO()))
+		assert.Equal(t, 1, released)
+		mockUCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,246 @@
+package bleve
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	blevesearch "github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/analysis/lang/id"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/format/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+)
+
+// indonesian is the analyzer of the Indonesian fields. Bleve has no
+// Indonesian stemmer, so words are only lowercased and stop words dropped.
+const indonesian = "id"
+
+// facetSize is the number of terms of each facet
+const facetSize = 10
+
+// titleBoost weighs a match in the title against one in the content
+const titleBoost = 2
+
+// document is what the index holds of an article. The title and content are
+// analyzed in English, stemming included, and again in Indonesian; only the
+// English fields are stored, for the hits and their highlights.
+type document struct {
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Author    string    `json:"author"`
+	Category  []string  `json:"category"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newMapping() (*mapping.IndexMappingImpl, error) {
+	m := blevesearch.NewIndexMapping()
+	err := m.AddCustomAnalyzer(indonesian, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{lowercase.Name, id.StopName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text := func(name, analyzer string, store bool) *mapping.FieldMapping {
+		field := blevesearch.NewTextFieldMapping()
+		field.Name = name
+		field.Analyzer = analyzer
+		field.Store = store
+		field.IncludeTermVectors = store
+		field.IncludeInAll = false
+		return field
+	}
+	exact := blevesearch.NewTextFieldMapping()
+	exact.Analyzer = keyword.Name
+	exact.IncludeInAll = false
+	date := blevesearch.NewDateTimeFieldMapping()
+	date.IncludeInAll = false
+
+	article := blevesearch.NewDocumentStaticMapping()
+	article.AddFieldMappingsAt("title", text("title", en.AnalyzerName, true), text("title_id", indonesian, false))
+	article.AddFieldMappingsAt("content", text("content", en.AnalyzerName, true), text("content_id", indonesian, false))
+	article.AddFieldMappingsAt("author", exact)
+	article.AddFieldMappingsAt("category", exact)
+	article.AddFieldMappingsAt("updated_at", date)
+	article.AddFieldMappingsAt("created_at", date)
+
+	m.DefaultMapping = article
+	m.DefaultAnalyzer = en.AnalyzerName
+	return m, nil
+}
+
+// OpenIndex opens the article index at path, creating it when missing, and
+// reports whether it was created empty. An empty path gives an index kept in
+// memory.
+func OpenIndex(path string) (index blevesearch.Index, created bool, err error) {
+	m, err := newMapping()
+	if err != nil {
+		return nil, false, err
+	}
+	if path == "" {
+		index, err = blevesearch.NewMemOnly(m)
+		return index, true, err
+	}
+
+	if _, err = os.Stat(path); os.IsNotExist(err) {
+		index, err = blevesearch.New(path, m)
+		return index, true, err
+	}
+	index, err = blevesearch.Open(path)
+	return index, false, err
+}
+
+type bleveArticleSearcher struct {
+	Bleve blevesearch.Index
+}
+
+// NewBleveArticleSearcher will create an object that represent the domain.ArticleSearcher interface
+func NewBleveArticleSearcher(index blevesearch.Index) domain.ArticleSearcher {
+	return &bleveArticleSearcher{index}
+}
+
+func (s *bleveArticleSearcher) Index(ctx context.Context, articles []domain.Article) error {
+	batch := s.Bleve.NewBatch()
+	for _, ar := range articles {
+		doc := document{
+			Title:     ar.Title,
+			Content:   ar.Content,
+			Author:    strconv.FormatInt(ar.Author.ID, 10),
+			Category:  make([]string, 0, len(ar.Categories)),
+			UpdatedAt: ar.UpdatedAt,
+			CreatedAt: ar.CreatedAt,
+		}
+		for _, c := range ar.Categories {
+			doc.Category = append(doc.Category, c.Tag)
+		}
+		if err := batch.Index(strconv.FormatInt(ar.ID, 10), doc); err != nil {
+			return err
+		}
+	}
+	return s.Bleve.Batch(batch)
+}
+
+func (s *bleveArticleSearcher) Delete(ctx context.Context, id int64) error {
+	return s.Bleve.Delete(strconv.FormatInt(id, 10))
+}
+
+func (s *bleveArticleSearcher) Search(ctx context.Context, q domain.ArticleSearchQuery) (res domain.ArticleSearchResult, err error) {
+	conjuncts := make([]query.Query, 0)
+	if q.Query != "" {
+		fields := []string{"title", "title_id", "content", "content_id"}
+		matches := make([]query.Query, len(fields))
+		for i, field := range fields {
+			match := blevesearch.NewMatchQuery(q.Query)
+			match.SetField(field)
+			if strings.HasPrefix(field, "title") {
+				match.SetBoost(titleBoost)
+			}
+			matches[i] = match
+		}
+		conjuncts = append(conjuncts, blevesearch.NewDisjunctionQuery(matches...))
+	}
+	if q.AuthorID != 0 {
+		term := blevesearch.NewTermQuery(strconv.FormatInt(q.AuthorID, 10))
+		term.SetField("author")
+		conjuncts = append(conjuncts, term)
+	}
+	if q.Category != "" {
+		term := blevesearch.NewTermQuery(q.Category)
+		term.SetField("category")
+		conjuncts = append(conjuncts, term)
+	}
+
+	var search query.Query = blevesearch.NewMatchAllQuery()
+	if len(conjuncts) > 0 {
+		search = blevesearch.NewConjunctionQuery(conjuncts...)
+	}
+
+	req := blevesearch.NewSearchRequestOptions(search, q.Num, q.From, false)
+	req.Fields = []string{"title", "content", "author", "category", "updated_at", "created_at"}
+	if q.Query != "" {
+		req.Highlight = blevesearch.NewHighlightWithStyle(html.Name)
+		req.Highlight.AddField("title")
+		req.Highlight.AddField("content")
+	}
+	req.AddFacet(domain.FacetCategory, blevesearch.NewFacetRequest("category", facetSize))
+	req.AddFacet(domain.FacetAuthor, blevesearch.NewFacetRequest("author", facetSize))
+
+	found, err := s.Bleve.SearchInContext(ctx, req)
+	if err != nil {
+		return domain.ArticleSearchResult{}, err
+	}
+
+	res = domain.ArticleSearchResult{
+		Total:  found.Total,
+		Hits:   make([]domain.ArticleHit, 0, len(found.Hits)),
+		Facets: make(map[string][]domain.FacetTerm),
+	}
+	for _, hit := range found.Hits {
+		ar, err := article(hit.ID, hit.Fields)
+		if err != nil {
+			return domain.ArticleSearchResult{}, err
+		}
+		highlights := hit.Fragments
+		if highlights == nil {
+			highlights = map[string][]string{}
+		}
+		res.Hits = append(res.Hits, domain.ArticleHit{Article: ar, Score: hit.Score, Highlights: highlights})
+	}
+	for name, facet := range found.Facets {
+		terms := make([]domain.FacetTerm, 0, len(facet.Terms))
+		for _, term := range facet.Terms {
+			terms = append(terms, domain.FacetTerm{Term: term.Term, Count: term.Count})
+		}
+		res.Facets[name] = terms
+	}
+	return
+}
+
+// article reads the article of a hit from its stored fields. A field holding
+// several values comes back as a slice, one holding a single value as is.
+func article(docID string, fields map[string]interface{}) (ar domain.Article, err error) {
+	if ar.ID, err = strconv.ParseInt(docID, 10, 64); err != nil {
+		return
+	}
+	ar.Title, _ = fields["title"].(string)
+	ar.Content, _ = fields["content"].(string)
+	if author, ok := fields["author"].(string); ok {
+		if ar.Author.ID, err = strconv.ParseInt(author, 10, 64); err != nil {
+			return
+		}
+	}
+
+	ar.Categories = make([]domain.Category, 0)
+	switch category := fields["category"].(type) {
+	case string:
+		ar.Categories = append(ar.Categories, domain.Category{Tag: category})
+	case []interface{}:
+		for _, tag := range category {
+			if tag, ok := tag.(string); ok {
+				ar.Categories = append(ar.Categories, domain.Category{Tag: tag})
+			}
+		}
+	}
+
+	for field, t := range map[string]*time.Time{"updated_at": &ar.UpdatedAt, "created_at": &ar.CreatedAt} {
+		if value, ok := fields[field].(string); ok {
+			if *t, err = time.Parse(time.RFC3339, value); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
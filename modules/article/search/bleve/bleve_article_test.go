@@ -0,0 +1,127 @@
+package bleve_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/search/bleve"
+)
+
+func newSearcher(t *testing.T) domain.ArticleSearcher {
+	index, created, err := bleve.OpenIndex("")
+	require.NoError(t, err)
+	assert.True(t, created)
+	t.Cleanup(func() { index.Close() })
+
+	createdAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	searcher := bleve.NewBleveArticleSearcher(index)
+	err = searcher.Index(context.TODO(), []domain.Article{
+		{
+			ID: 1, Title: "Running a marathon", Content: "Runners train for months before the race.",
+			Author: domain.Author{ID: 1}, Categories: []domain.Category{{Tag: "sport"}, {Tag: "health"}},
+			CreatedAt: createdAt, UpdatedAt: createdAt,
+		},
+		{
+			ID: 2, Title: "Resep nasi goreng", Content: "Nasi goreng yang enak dan mudah dibuat di rumah.",
+			Author: domain.Author{ID: 2}, Categories: []domain.Category{{Tag: "food"}},
+			CreatedAt: createdAt, UpdatedAt: createdAt,
+		},
+		{
+			ID: 3, Title: "Healthy breakfast", Content: "A bowl of oats before a run.",
+			Author: domain.Author{ID: 1}, Categories: []domain.Category{{Tag: "health"}},
+			CreatedAt: createdAt, UpdatedAt: createdAt,
+		},
+	})
+	require.NoError(t, err)
+	return searcher
+}
+
+func ids(hits []domain.ArticleHit) []int64 {
+	res := make([]int64, len(hits))
+	for i, hit := range hits {
+		res[i] = hit.Article.ID
+	}
+	return res
+}
+
+func TestSearch(t *testing.T) {
+	searcher := newSearcher(t)
+
+	t.Run("english-stemming", func(t *testing.T) {
+		res, err := searcher.Search(context.TODO(), domain.ArticleSearchQuery{Query: "runs", Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), res.Total)
+		assert.ElementsMatch(t, []int64{1, 3}, ids(res.Hits))
+
+		hit := res.Hits[0]
+		if hit.Article.ID != 1 {
+			hit = res.Hits[1]
+		}
+		assert.Equal(t, "Running a marathon", hit.Article.Title)
+		assert.Equal(t, int64(1), hit.Article.Author.ID)
+		assert.Equal(t, []domain.Category{{Tag: "sport"}, {Tag: "health"}}, hit.Article.Categories)
+		assert.Equal(t, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), hit.Article.CreatedAt.UTC())
+		require.NotEmpty(t, hit.Highlights["title"])
+		assert.Contains(t, hit.Highlights["title"][0], "<mark>Running</mark>")
+	})
+
+	t.Run("indonesian", func(t *testing.T) {
+		res, err := searcher.Search(context.TODO(), domain.ArticleSearchQuery{Query: "yang nasi", Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2}, ids(res.Hits))
+		assert.Equal(t, []domain.Category{{Tag: "food"}}, res.Hits[0].Article.Categories)
+	})
+
+	t.Run("facets", func(t *testing.T) {
+		res, err := searcher.Search(context.TODO(), domain.ArticleSearchQuery{Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), res.Total)
+		assert.Equal(t, []domain.FacetTerm{{Term: "1", Count: 2}, {Term: "2", Count: 1}}, res.Facets[domain.FacetAuthor])
+		assert.Equal(t, []domain.FacetTerm{{Term: "health", Count: 2}, {Term: "food", Count: 1}, {Term: "sport", Count: 1}},
+			res.Facets[domain.FacetCategory])
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		res, err := searcher.Search(context.TODO(), domain.ArticleSearchQuery{Category: "health", AuthorID: 1, Query: "oats", Num: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3}, ids(res.Hits))
+
+		res, err = searcher.Search(context.TODO(), domain.ArticleSearchQuery{Category: "food", Query: "oats", Num: 10})
+		require.NoError(t, err)
+		assert.Empty(t, res.Hits)
+	})
+
+	t.Run("paged", func(t *testing.T) {
+		res, err := searcher.Search(context.TODO(), domain.ArticleSearchQuery{Category: "health", Num: 1, From: 1})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), res.Total)
+		assert.Len(t, res.Hits, 1)
+	})
+}
+
+func TestIndexAndDelete(t *testing.T) {
+	searcher := newSearcher(t)
+
+	err := searcher.Index(context.TODO(), []domain.Article{
+		{ID: 2, Title: "Resep soto ayam", Content: "Soto ayam kuning.", Author: domain.Author{ID: 2}, Categories: []domain.Category{}},
+	})
+	require.NoError(t, err)
+	res, err := searcher.Search(context.TODO(), domain.ArticleSearchQuery{Query: "nasi", Num: 10})
+	require.NoError(t, err)
+	assert.Empty(t, res.Hits, "indexing again replaces the article")
+
+	res, err = searcher.Search(context.TODO(), domain.ArticleSearchQuery{Query: "soto", Num: 10})
+	require.NoError(t, err)
+	require.Equal(t, []int64{2}, ids(res.Hits))
+	assert.Empty(t, res.Hits[0].Article.Categories)
+
+	require.NoError(t, searcher.Delete(context.TODO(), 2))
+	res, err = searcher.Search(context.TODO(), domain.ArticleSearchQuery{Query: "soto", Num: 10})
+	require.NoError(t, err)
+	assert.Empty(t, res.Hits)
+}
@@ -0,0 +1,92 @@
+// Package slug turns the titles of articles into readable names fit for a
+// URL. Letters are transliterated to ASCII, so that "Nasi Goreng Spésial!"
+// becomes "nasi-goreng-spesial"; two articles with the same slug tell apart
+// with a suffix, "nasi-goreng-spesial-2".
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxLen is the length past which a slug is cut, at a hyphen when it can,
+// leaving room for a suffix in the 100 characters of the column
+const maxLen = 80
+
+// fallback is the slug of a title with nothing to transliterate
+const fallback = "article"
+
+// letters are transliterated by hand, their compatibility decomposition
+// holding no ASCII letter
+var letters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+	'ł': "l", 'þ': "th", 'ı': "i", 'ħ': "h", 'ŋ': "ng",
+}
+
+// elided marks join the words around them, as in "Jum'at" or "don't"
+var elided = map[rune]bool{'\'': true, '’': true, '‘': true, '`': true}
+
+// Make returns the slug of title: its letters and digits in lower case ASCII,
+// the runs of anything else between them turned to a hyphen.
+func Make(title string) string {
+	var b strings.Builder
+	hyphen := false
+	write := func(s string) {
+		if hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		hyphen = false
+		b.WriteString(s)
+	}
+
+	for _, r := range norm.NFKD.String(title) {
+		r = unicode.ToLower(r)
+		switch {
+		case unicode.Is(unicode.Mn, r) || elided[r]:
+		case 'a' <= r && r <= 'z' || '0' <= r && r <= '9':
+			write(string(r))
+		case letters[r] != "":
+			write(letters[r])
+		default:
+			hyphen = true
+		}
+	}
+
+	s := b.String()
+	if len(s) > maxLen {
+		cut := s[:maxLen]
+		if s[maxLen] != '-' {
+			if i := strings.LastIndexByte(cut, '-'); i > 0 {
+				cut = cut[:i]
+			}
+		}
+		s = strings.TrimSuffix(cut, "-")
+	}
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// Nth returns the n-th slug made of base: base itself, then base-2, base-3...
+func Nth(base string, n int) string {
+	if n < 2 {
+		return base
+	}
+	return base + "-" + strconv.Itoa(n)
+}
+
+// Of reports whether s is one of the slugs made of base
+func Of(s, base string) bool {
+	if s == base {
+		return true
+	}
+	if !strings.HasPrefix(s, base+"-") {
+		return false
+	}
+	n, err := strconv.Atoi(s[len(base)+1:])
+	return err == nil && n >= 2 && Nth(base, n) == s
+}
@@ -0,0 +1,46 @@
+package slug_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/slug"
+)
+
+func TestMake(t *testing.T) {
+	for title, want := range map[string]string{
+		"Makan Ayam":                   "makan-ayam",
+		"  Hello, World!  ":            "hello-world",
+		"Nasi Goreng Spésial":          "nasi-goreng-spesial",
+		"Salat Jum'at di Masjid":       "salat-jumat-di-masjid",
+		"Straße & Æsir":                "strasse-aesir",
+		"Ｆｕｌｌ ｗｉｄｔｈ ２０２１":              "full-width-2021",
+		"Łódź—Kraków":                  "lodz-krakow",
+		"Новости":                      "article",
+		"???":                          "article",
+		"Top 10: Kuliner Khas Bandung": "top-10-kuliner-khas-bandung",
+	} {
+		assert.Equal(t, want, slug.Make(title), title)
+	}
+}
+
+func TestMakeCutsLongTitles(t *testing.T) {
+	got := slug.Make(strings.Repeat("panjang ", 20))
+	assert.True(t, len(got) <= 80, got)
+	assert.False(t, strings.HasSuffix(got, "-"), got)
+	assert.Equal(t, strings.TrimSuffix(strings.Repeat("panjang-", 10), "-"), got)
+}
+
+func TestNthAndOf(t *testing.T) {
+	assert.Equal(t, "kopi", slug.Nth("kopi", 1))
+	assert.Equal(t, "kopi-3", slug.Nth("kopi", 3))
+
+	assert.True(t, slug.Of("kopi", "kopi"))
+	assert.True(t, slug.Of("kopi-12", "kopi"))
+	assert.False(t, slug.Of("kopi-1", "kopi"))
+	assert.False(t, slug.Of("kopi-02", "kopi"))
+	assert.False(t, slug.Of("kopi-susu", "kopi"))
+	assert.False(t, slug.Of("kopi", "kopi-2"))
+}
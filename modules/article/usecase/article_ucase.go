@@ -2,104 +2,252 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/diff"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/slug"
 	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 type articleUsecase struct {
 	articleRepo    domain.ArticleRepository
 	authorRepo     domain.AuthorRepository
+	categoryRepo   domain.CategoryRepository
+	policy         domain.Policy
+	transactor     domain.Transactor
+	sealer         domain.CursorSealer
+	searcher       domain.ArticleSearcher
+	revisionRepo   domain.ArticleRevisionRepository
 	contextTimeout time.Duration
 }
 
 // NewArticleUsecase will create new an articleUsecase object representation of domain.ArticleUsecase interface
-func NewArticleUsecase(a domain.ArticleRepository, ar domain.AuthorRepository, timeout time.Duration) domain.ArticleUsecase {
+func NewArticleUsecase(a domain.ArticleRepository, ar domain.AuthorRepository, cr domain.CategoryRepository, p domain.Policy, t domain.Transactor, s domain.CursorSealer, se domain.ArticleSearcher, rr domain.ArticleRevisionRepository, timeout time.Duration) domain.ArticleUsecase {
 	return &articleUsecase{
 		articleRepo:    a,
 		authorRepo:     ar,
+		categoryRepo:   cr,
+		policy:         p,
+		transactor:     t,
+		sealer:         s,
+		searcher:       se,
+		revisionRepo:   rr,
 		contextTimeout: timeout,
 	}
 }
 
-/*
-* In this function below, I'm using errgroup with the pipeline pattern
-* Look how this works in this package explanation
-* in godoc: https://godoc.org/golang.org/x/sync/errgroup#ex-Group--Pipeline
- */
-func (a *articleUsecase) fillAuthorDetails(c context.Context, data []domain.Article) ([]domain.Article, error) {
-	g, ctx := errgroup.WithContext(c)
-
+// fillAuthorDetails loads the authors of every article in a single query.
+// Articles whose author no longer exists keep only the author id.
+func (a *articleUsecase) fillAuthorDetails(ctx context.Context, data []domain.Article) ([]domain.Article, error) {
 	// Get the author's id
-	mapAuthors := map[int64]domain.Author{}
-
+	authorIDs := make([]int64, 0, len(data))
+	seen := map[int64]bool{}
 	for _, article := range data {
-		mapAuthors[article.Author.ID] = domain.Author{}
-	}
-	// Using goroutine to fetch the author's detail
-	chanAuthor := make(chan domain.Author)
-	for authorID := range mapAuthors {
-		authorID := authorID
-		g.Go(func() error {
-			res, err := a.authorRepo.GetByID(ctx, authorID)
-			if err != nil {
-				return err
-			}
-			chanAuthor <- res
-			return nil
-		})
+		if !seen[article.Author.ID] {
+			seen[article.Author.ID] = true
+			authorIDs = append(authorIDs, article.Author.ID)
+		}
 	}
 
-	go func() {
-		err := g.Wait()
-		if err != nil {
-			logrus.Error(err)
-			return
-		}
-		close(chanAuthor)
-	}()
+	mapAuthors, err := a.authorRepo.GetByIDs(ctx, authorIDs)
+	if err != nil {
+		return nil, err
+	}
 
-	for author := range chanAuthor {
-		if author != (domain.Author{}) {
-			mapAuthors[author.ID] = author
+	// merge the author's data
+	missing := make([]int64, 0)
+	for index, item := range data {
+		author, ok := mapAuthors[item.Author.ID]
+		if !ok {
+			missing = append(missing, item.ID)
+			continue
 		}
+		data[index].Author = author
+	}
+	if len(missing) > 0 {
+		logrus.Warnf("author details not found for articles %v", missing)
+	}
+	return data, nil
+}
+
+// fillCategoryDetails loads the categories of every article in a single query
+func (a *articleUsecase) fillCategoryDetails(ctx context.Context, data []domain.Article) ([]domain.Article, error) {
+	articleIDs := make([]int64, 0, len(data))
+	for _, article := range data {
+		articleIDs = append(articleIDs, article.ID)
 	}
 
-	if err := g.Wait(); err != nil {
+	mapCategories, err := a.categoryRepo.GetByArticleIDs(ctx, articleIDs)
+	if err != nil {
 		return nil, err
 	}
 
-	// merge the author's data
+	// merge the category's data
 	for index, item := range data {
-		if a, ok := mapAuthors[item.Author.ID]; ok {
-			data[index].Author = a
+		data[index].Categories = mapCategories[item.ID]
+		if data[index].Categories == nil {
+			data[index].Categories = []domain.Category{}
 		}
 	}
 	return data, nil
 }
 
-func (a *articleUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	if num == 0 {
-		num = 10
+func (a *articleUsecase) fillDetails(ctx context.Context, data []domain.Article) ([]domain.Article, error) {
+	data, err := a.fillAuthorDetails(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.fillCategoryDetails(ctx, data)
+}
+
+// canList allows anyone to list the published articles. The others are
+// listed by publishers, or by whoever may modify the articles of the author
+// the listing is narrowed to.
+func (a *articleUsecase) canList(ctx context.Context, filter domain.ArticleFilter) error {
+	if filter.Status == domain.StatusPublished {
+		return nil
+	}
+	if filter.AuthorID != 0 && a.policy.CanModifyArticle(ctx, domain.Article{Author: domain.Author{ID: filter.AuthorID}}) == nil {
+		return nil
+	}
+	return a.policy.CanPublishArticle(ctx)
+}
+
+// canRead hides the articles not published from the callers who may neither
+// modify nor publish them
+func (a *articleUsecase) canRead(ctx context.Context, ar domain.Article) error {
+	if ar.Status == domain.StatusPublished {
+		return nil
+	}
+	if a.policy.CanModifyArticle(ctx, ar) == nil || a.policy.CanPublishArticle(ctx) == nil {
+		return nil
+	}
+	return errHandle.ErrNotFound
+}
+
+// Fetch lists the articles matching filter, the published ones unless another
+// status is asked for. A search is sorted by relevance unless another sort is
+// asked for, and only a search can be.
+func (a *articleUsecase) Fetch(c context.Context, filter domain.ArticleFilter, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	if page.Num == 0 {
+		page.Num = 10
+	}
+	if page.Sort == "" && filter.Query != "" {
+		page.Sort = domain.SortRelevance
+	}
+	if page.Sort == domain.SortRelevance && filter.Query == "" {
+		return nil, domain.Cursors{}, errHandle.ErrBadParamInput
+	}
+	if filter.Status == "" {
+		filter.Status = domain.StatusPublished
+	}
+	if !filter.Status.Valid() {
+		return nil, domain.Cursors{}, errHandle.ErrBadParamInput
 	}
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
-	res, nextCursor, err = a.articleRepo.Fetch(ctx, cursor, num)
+	if err = a.canList(ctx, filter); err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	opened, err := a.sealer.Open(page, "articles", filter)
 	if err != nil {
-		return nil, "", err
+		return nil, domain.Cursors{}, err
 	}
 
-	res, err = a.fillAuthorDetails(ctx, res)
+	res, cursors, err = a.articleRepo.Fetch(ctx, filter, opened)
 	if err != nil {
-		nextCursor = ""
+		return nil, domain.Cursors{}, err
 	}
-	return
+
+	res, err = a.fillDetails(ctx, res)
+	if err != nil {
+		return res, domain.Cursors{}, err
+	}
+	return res, a.sealer.Seal(page, "articles", filter, cursors), nil
+}
+
+func (a *articleUsecase) FetchByCategory(c context.Context, tag string, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	if page.Num == 0 {
+		page.Num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	category, err := a.categoryRepo.GetByTag(ctx, tag)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	opened, err := a.sealer.Open(page, "category-articles", category.ID)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, cursors, err = a.articleRepo.FetchByCategory(ctx, category.ID, domain.StatusPublished, opened)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, err = a.fillDetails(ctx, res)
+	if err != nil {
+		return res, domain.Cursors{}, err
+	}
+	return res, a.sealer.Seal(page, "category-articles", category.ID, cursors), nil
+}
+
+func (a *articleUsecase) FetchByAuthor(c context.Context, authorID int64, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	if page.Num == 0 {
+		page.Num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.authorRepo.GetByID(ctx, authorID); err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	opened, err := a.sealer.Open(page, "author-articles", authorID)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, cursors, err = a.articleRepo.FetchByAuthor(ctx, authorID, domain.StatusPublished, opened)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, err = a.fillDetails(ctx, res)
+	if err != nil {
+		return res, domain.Cursors{}, err
+	}
+	return res, a.sealer.Seal(page, "author-articles", authorID, cursors), nil
+}
+
+// fillOne loads the author and categories of a single article
+func (a *articleUsecase) fillOne(ctx context.Context, res domain.Article) (domain.Article, error) {
+	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	res.Author = resAuthor
+
+	list, err := a.fillCategoryDetails(ctx, []domain.Article{res})
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	return list[0], nil
 }
 
 func (a *articleUsecase) GetByID(c context.Context, id int64) (res domain.Article, err error) {
@@ -110,21 +258,174 @@ func (a *articleUsecase) GetByID(c context.Context, id int64) (res domain.Articl
 	if err != nil {
 		return
 	}
+	if err = a.canRead(ctx, res); err != nil {
+		return domain.Article{}, err
+	}
 
-	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
+	return a.fillOne(ctx, res)
+}
+
+// GetBySlug finds the article by its current slug or by any it had before,
+// the caller telling them apart with the slug of the article returned
+func (a *articleUsecase) GetBySlug(c context.Context, s string) (res domain.Article, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.articleRepo.GetBySlug(ctx, s)
 	if err != nil {
+		return
+	}
+	if err = a.canRead(ctx, res); err != nil {
 		return domain.Article{}, err
 	}
-	res.Author = resAuthor
-	return
+
+	return a.fillOne(ctx, res)
 }
 
+// slugAttempts bounds the slugs tried for an article whose free slug keeps
+// being taken by articles stored meanwhile
+const slugAttempts = 10
+
+// assignSlug gives ar the slug made of its title. It keeps the one it has
+// while the title still makes it, else takes the first of base, base-2,
+// base-3... no other article ever had, the slugs it had before still leading
+// to it. A slug taken meanwhile by another article, whatever its author, makes
+// it try the next one.
+func (a *articleUsecase) assignSlug(ctx context.Context, ar *domain.Article) error {
+	base := slug.Make(ar.Title)
+	if ar.Slug != "" && slug.Of(ar.Slug, base) {
+		return nil
+	}
+
+	taken, err := a.articleRepo.FetchSlugs(ctx, base)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; attempt < slugAttempts; attempt++ {
+		s := base
+		for n := 2; ; n++ {
+			if owner, ok := taken[s]; !ok || owner == ar.ID {
+				break
+			}
+			s = slug.Nth(base, n)
+		}
+
+		err = a.articleRepo.SetSlug(ctx, ar.ID, s, time.Now())
+		if err == errHandle.ErrConflict {
+			taken[s] = 0
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		ar.Slug = s
+		return nil
+	}
+	return fmt.Errorf("no free slug for %q after %d attempts", base, slugAttempts)
+}
+
+// slugBatch is the number of articles given a slug at once
+const slugBatch = 100
+
+// FillSlugs gives a slug to the articles stored before they had any, trashed
+// ones included, and reports how many it went through
+func (a *articleUsecase) FillSlugs(c context.Context) (n int, err error) {
+	for {
+		count, err := a.fillSlugsBatch(c)
+		n += count
+		if err != nil || count < slugBatch {
+			return n, err
+		}
+	}
+}
+
+func (a *articleUsecase) fillSlugsBatch(c context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	list, err := a.articleRepo.FetchWithoutSlug(ctx, slugBatch)
+	if err != nil {
+		return 0, err
+	}
+	for i := range list {
+		if err = a.assignSlug(ctx, &list[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(list), nil
+}
+
+// Update requires the caller to be allowed on the article both as it is
+// stored and as it will be. The article keeps its author unless given another
+// one, which takes being allowed to post as them, as on Store.
+// The new title and content are kept as a revision along with the update, and a
+// new title that makes another slug gives the article that slug. Categories
+// given by id in ar.Categories replace the ones of the article, an unknown one
+// failing the update with ErrBadParamInput; a nil ar.Categories keeps them.
+// On success ar is the article as stored, with its author and categories.
 func (a *articleUsecase) Update(c context.Context, ar *domain.Article) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
+	existedArticle, err := a.articleRepo.GetByID(ctx, ar.ID)
+	if err != nil {
+		return
+	}
+	if err = a.policy.CanModifyArticle(ctx, existedArticle); err != nil {
+		return
+	}
+	if err = a.reassignAuthor(ctx, ar, existedArticle.Author); err != nil {
+		return
+	}
+	if err = a.policy.CanModifyArticle(ctx, *ar); err != nil {
+		return
+	}
+
+	ar.Status, ar.PublishedAt, ar.PublishAt = existedArticle.Status, existedArticle.PublishedAt, existedArticle.PublishAt
+	ar.Slug = existedArticle.Slug
 	ar.UpdatedAt = time.Now()
-	return a.articleRepo.Update(ctx, ar)
+	version := ar.Version
+	err = a.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// a transaction run again after a deadlock starts over from the
+		// version and slug the article had before the rolled back attempt
+		ar.Version, ar.Slug = version, existedArticle.Slug
+		if ar.Author.ID != existedArticle.Author.ID {
+			if err := a.lockAuthor(ctx, ar.Author.ID); err != nil {
+				return err
+			}
+		}
+		if err := a.articleRepo.Update(ctx, ar); err != nil {
+			return err
+		}
+		if err := a.assignSlug(ctx, ar); err != nil {
+			return err
+		}
+		if err := a.storeRevision(ctx, *ar); err != nil {
+			return err
+		}
+		if ar.Categories == nil {
+			return nil
+		}
+		linked, err := a.categoryRepo.GetByArticleIDs(ctx, []int64{ar.ID})
+		if err != nil {
+			return err
+		}
+		return a.linkCategories(ctx, ar, linked[ar.ID])
+	})
+	if err != nil {
+		return
+	}
+
+	// the article is given back as GetByID finds it
+	stored, err := a.articleRepo.GetByID(ctx, ar.ID)
+	if err != nil {
+		return
+	}
+	if *ar, err = a.fillOne(ctx, stored); err != nil {
+		return
+	}
+	a.syncIndex(ctx, *ar)
+	return
 }
 
 func (a *articleUsecase) GetByTitle(c context.Context, title string) (res domain.Article, err error) {
@@ -134,37 +435,623 @@ func (a *articleUsecase) GetByTitle(c context.Context, title string) (res domain
 	if err != nil {
 		return
 	}
-
-	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
-	if err != nil {
+	if err = a.canRead(ctx, res); err != nil {
 		return domain.Article{}, err
 	}
 
-	res.Author = resAuthor
-	return
+	return a.fillOne(ctx, res)
+}
+
+// resolveAuthor sets the author of a new article to the author profile of the
+// caller. An explicit author id is only accepted from callers allowed to post
+// on behalf of that author.
+func (a *articleUsecase) resolveAuthor(ctx context.Context, m *domain.Article) error {
+	if m.Author.ID == 0 {
+		caller, _ := domain.UserFromContext(ctx)
+		m.Author.ID = caller.AuthorID
+	}
+	return a.policy.CanPostAsAuthor(ctx, m.Author.ID)
+}
+
+// reassignAuthor keeps the author an article had unless the update gives
+// another one, which the caller has to be allowed to post as, like on Store
+func (a *articleUsecase) reassignAuthor(ctx context.Context, ar *domain.Article, was domain.Author) error {
+	if ar.Author.ID == 0 || ar.Author.ID == was.ID {
+		ar.Author = was
+		return nil
+	}
+	return a.policy.CanPostAsAuthor(ctx, ar.Author.ID)
+}
+
+// lockAuthor holds the author an article is given until the end of the
+// transaction of ctx, so that the author can't be deleted meanwhile. An
+// unknown author is an ErrBadParamInput.
+func (a *articleUsecase) lockAuthor(ctx context.Context, authorID int64) error {
+	err := a.authorRepo.Lock(ctx, authorID)
+	if err == errHandle.ErrNotFound {
+		return errHandle.ErrBadParamInput
+	}
+	return err
 }
 
+// Store saves the article with its first revision and links it to the
+// categories given by id in m.Categories within a single transaction. An
+// unknown author or category fails the whole store with ErrBadParamInput, and
+// a title another article has with ErrConflict, as told by the repository.
 func (a *articleUsecase) Store(c context.Context, m *domain.Article) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
-	existedArticle, _ := a.GetByTitle(ctx, m.Title)
-	if existedArticle != (domain.Article{}) {
-		return errHandle.ErrConflict
+	if err = a.policy.CanCreateArticle(ctx); err != nil {
+		return
+	}
+	if err = a.resolveAuthor(ctx, m); err != nil {
+		return
 	}
 
-	err = a.articleRepo.Store(ctx, m)
+	m.Status = domain.StatusDraft
+	m.PublishedAt = nil
+	m.PublishAt = nil
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	err = a.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// the slug of a rolled back attempt was never stored
+		m.Slug = ""
+		if err := a.lockAuthor(ctx, m.Author.ID); err != nil {
+			return err
+		}
+		if err := a.articleRepo.Store(ctx, m); err != nil {
+			return err
+		}
+		if err := a.assignSlug(ctx, m); err != nil {
+			return err
+		}
+		if err := a.storeRevision(ctx, *m); err != nil {
+			return err
+		}
+		return a.linkCategories(ctx, m, nil)
+	})
+	if err != nil {
+		m.ID, m.Slug = 0, ""
+	}
 	return
 }
 
-func (a *articleUsecase) Delete(c context.Context, id int64) (err error) {
+// linkCategories links the stored article to its categories, unlinking it from
+// the ones of linked it no longer has, and replaces them by their full details
+func (a *articleUsecase) linkCategories(ctx context.Context, m *domain.Article, linked []domain.Category) error {
+	kept := make(map[int64]domain.Category, len(linked))
+	for _, item := range linked {
+		kept[item.ID] = item
+	}
+
+	categories := make([]domain.Category, 0, len(m.Categories))
+	seen := map[int64]bool{}
+	for _, item := range m.Categories {
+		if seen[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+
+		category, ok := kept[item.ID]
+		if !ok {
+			var err error
+			category, err = a.categoryRepo.GetByID(ctx, item.ID)
+			if err == errHandle.ErrNotFound {
+				return errHandle.ErrBadParamInput
+			}
+			if err != nil {
+				return err
+			}
+			if err = a.categoryRepo.AddArticle(ctx, m.ID, category.ID); err != nil {
+				return err
+			}
+		}
+		categories = append(categories, category)
+	}
+	for _, item := range linked {
+		if seen[item.ID] {
+			continue
+		}
+		if err := a.categoryRepo.RemoveArticle(ctx, m.ID, item.ID); err != nil {
+			return err
+		}
+	}
+
+	m.Categories = categories
+	return nil
+}
+
+// Delete moves the given version of the article to the trash, out of the
+// listings and search index until it is restored or purged
+func (a *articleUsecase) Delete(c context.Context, id int64, version int64) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	existedArticle, err := a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
 	}
-	if existedArticle == (domain.Article{}) {
-		return errHandle.ErrNotFound
+	if err = a.policy.CanModifyArticle(ctx, existedArticle); err != nil {
+		return
+	}
+	if err = a.articleRepo.Delete(ctx, id, version, time.Now()); err != nil {
+		return
+	}
+
+	if errIndex := a.searcher.Delete(ctx, id); errIndex != nil {
+		logrus.Errorf("removing article %d from the index: %v", id, errIndex)
+	}
+	return
+}
+
+// reindex indexes the article as stored
+func (a *articleUsecase) reindex(ctx context.Context, id int64) {
+	ar, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		logrus.Errorf("indexing article %d: %v", id, err)
+		return
+	}
+	list, err := a.fillCategoryDetails(ctx, []domain.Article{ar})
+	if err != nil {
+		logrus.Errorf("indexing article %d: %v", id, err)
+		return
 	}
-	return a.articleRepo.Delete(ctx, id)
+	a.syncIndex(ctx, list[0])
+}
+
+// syncIndex keeps the article in the search index while it is published and
+// out of it otherwise. The index is rebuilt from the repository by Reindex,
+// so failing to keep it in sync is only logged.
+func (a *articleUsecase) syncIndex(ctx context.Context, ar domain.Article) {
+	var err error
+	if ar.Status == domain.StatusPublished {
+		err = a.searcher.Index(ctx, []domain.Article{ar})
+	} else {
+		err = a.searcher.Delete(ctx, ar.ID)
+	}
+	if err != nil {
+		logrus.Errorf("indexing article %d: %v", ar.ID, err)
+	}
+}
+
+// transition moves the given version of the article to status to from any of
+// the statuses from, the caller being allowed by can. Publishing stamps the publication time and
+// going back to the drafts clears it; a scheduled publication only survives
+// the submission for review.
+func (a *articleUsecase) transition(c context.Context, id int64, version int64, to domain.ArticleStatus, from []domain.ArticleStatus, can func(context.Context, domain.Article) error) (res domain.Article, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	ar, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if err = can(ctx, ar); err != nil {
+		return
+	}
+
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || ar.Status == status
+	}
+	if !allowed {
+		return domain.Article{}, errHandle.ErrInvalidTransition
+	}
+
+	ar.Status, ar.Version = to, version
+	ar.UpdatedAt = time.Now()
+	switch to {
+	case domain.StatusPublished:
+		publishedAt := ar.UpdatedAt
+		ar.PublishedAt = &publishedAt
+	case domain.StatusDraft:
+		ar.PublishedAt = nil
+	}
+	if to != domain.StatusInReview {
+		ar.PublishAt = nil
+	}
+	if err = a.articleRepo.UpdateStatus(ctx, &ar); err != nil {
+		return
+	}
+
+	if res, err = a.fillOne(ctx, ar); err != nil {
+		return
+	}
+	a.syncIndex(ctx, res)
+	return
+}
+
+// Submit hands a draft over for review, by whoever may modify it
+func (a *articleUsecase) Submit(c context.Context, id int64, version int64) (domain.Article, error) {
+	return a.transition(c, id, version, domain.StatusInReview, []domain.ArticleStatus{domain.StatusDraft}, a.policy.CanModifyArticle)
+}
+
+// Publish makes a draft or an article in review public, by publishers only
+func (a *articleUsecase) Publish(c context.Context, id int64, version int64) (domain.Article, error) {
+	return a.transition(c, id, version, domain.StatusPublished, []domain.ArticleStatus{domain.StatusDraft, domain.StatusInReview}, a.canPublish)
+}
+
+// Unpublish sends a published article back to the drafts, by publishers only
+func (a *articleUsecase) Unpublish(c context.Context, id int64, version int64) (domain.Article, error) {
+	return a.transition(c, id, version, domain.StatusDraft, []domain.ArticleStatus{domain.StatusPublished}, a.canPublish)
+}
+
+// Archive retires an article. Whoever may modify an article archives it
+// before it is published, publishers only once it is.
+func (a *articleUsecase) Archive(c context.Context, id int64, version int64) (domain.Article, error) {
+	return a.transition(c, id, version, domain.StatusArchived,
+		[]domain.ArticleStatus{domain.StatusDraft, domain.StatusInReview, domain.StatusPublished},
+		func(ctx context.Context, ar domain.Article) error {
+			if ar.Status == domain.StatusPublished {
+				return a.canPublish(ctx, ar)
+			}
+			return a.policy.CanModifyArticle(ctx, ar)
+		})
+}
+
+// Schedule sets the time the scheduler publishes the given version of a draft
+// or an article in review at, by publishers only. A nil time cancels the scheduled publication.
+func (a *articleUsecase) Schedule(c context.Context, id int64, version int64, publishAt *time.Time) (res domain.Article, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	ar, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if err = a.policy.CanPublishArticle(ctx); err != nil {
+		return
+	}
+	if ar.Status != domain.StatusDraft && ar.Status != domain.StatusInReview {
+		return domain.Article{}, errHandle.ErrInvalidTransition
+	}
+
+	ar.UpdatedAt = time.Now()
+	if publishAt != nil && !publishAt.After(ar.UpdatedAt) {
+		return domain.Article{}, errHandle.ErrBadParamInput
+	}
+	ar.PublishAt, ar.Version = publishAt, version
+	if err = a.articleRepo.UpdateStatus(ctx, &ar); err != nil {
+		return
+	}
+
+	return a.fillOne(ctx, ar)
+}
+
+// dueBatch is the number of due articles published at once
+const dueBatch = 100
+
+// PublishDue publishes the articles whose scheduled publication is at or
+// before now, on behalf of the scheduler, and reports how many it published.
+// An article changed since it was listed is left for the next run.
+func (a *articleUsecase) PublishDue(c context.Context, now time.Time) (n int, err error) {
+	for {
+		published, listed, err := a.publishDueBatch(c, now)
+		n += published
+		if err != nil || listed < dueBatch || published == 0 {
+			return n, err
+		}
+	}
+}
+
+func (a *articleUsecase) publishDueBatch(c context.Context, now time.Time) (published int, listed int, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	due, err := a.articleRepo.FetchDue(ctx, now, dueBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, ar := range due {
+		ar.Status = domain.StatusPublished
+		ar.PublishedAt = &now
+		ar.PublishAt = nil
+		ar.UpdatedAt = now
+		err = a.articleRepo.UpdateStatus(ctx, &ar)
+		if err == errHandle.ErrPreconditionFailed || err == errHandle.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return published, len(due), err
+		}
+
+		published++
+		a.reindex(ctx, ar.ID)
+	}
+	return published, len(due), nil
+}
+
+func (a *articleUsecase) canPublish(ctx context.Context, ar domain.Article) error {
+	return a.policy.CanPublishArticle(ctx)
+}
+
+// Search looks the articles up in the index, then loads the authors and
+// categories of the hits and the names of the facet terms from the
+// repositories
+func (a *articleUsecase) Search(c context.Context, q domain.ArticleSearchQuery) (res domain.ArticleSearchResult, err error) {
+	if q.Num == 0 {
+		q.Num = 10
+	}
+	if q.Num < 0 || q.Num > 100 || q.From < 0 {
+		return domain.ArticleSearchResult{}, errHandle.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.searcher.Search(ctx, q)
+	if err != nil {
+		return domain.ArticleSearchResult{}, err
+	}
+
+	if len(res.Hits) > 0 {
+		articles := make([]domain.Article, len(res.Hits))
+		for i, hit := range res.Hits {
+			articles[i] = hit.Article
+		}
+		if articles, err = a.fillDetails(ctx, articles); err != nil {
+			return domain.ArticleSearchResult{}, err
+		}
+		for i := range res.Hits {
+			res.Hits[i].Article = articles[i]
+		}
+	}
+
+	if err = a.labelFacets(ctx, res.Facets); err != nil {
+		return domain.ArticleSearchResult{}, err
+	}
+	return
+}
+
+// labelFacets names the authors and categories of the facets
+func (a *articleUsecase) labelFacets(ctx context.Context, facets map[string][]domain.FacetTerm) error {
+	if terms := facets[domain.FacetAuthor]; len(terms) > 0 {
+		ids := make([]int64, 0, len(terms))
+		for _, term := range terms {
+			if id, err := strconv.ParseInt(term.Term, 10, 64); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		authors, err := a.authorRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i, term := range terms {
+			id, _ := strconv.ParseInt(term.Term, 10, 64)
+			terms[i].Label = authors[id].Name
+		}
+	}
+
+	if terms := facets[domain.FacetCategory]; len(terms) > 0 {
+		categories, err := a.categoryRepo.Fetch(ctx)
+		if err != nil {
+			return err
+		}
+		names := make(map[string]string, len(categories))
+		for _, category := range categories {
+			names[category.Tag] = category.Name
+		}
+		for i, term := range terms {
+			terms[i].Label = names[term.Term]
+		}
+	}
+	return nil
+}
+
+// Reindex indexes every published article, a page at a time, and returns how
+// many there were
+func (a *articleUsecase) Reindex(c context.Context) (n int, err error) {
+	page := domain.Page{Num: 100}
+	for {
+		var (
+			count int
+			next  string
+		)
+		if count, next, err = a.reindexPage(c, page); err != nil {
+			return
+		}
+		n += count
+		if next == "" {
+			return
+		}
+		page.Cursor = next
+	}
+}
+
+func (a *articleUsecase) reindexPage(c context.Context, page domain.Page) (n int, next string, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, cursors, err := a.articleRepo.Fetch(ctx, domain.ArticleFilter{Status: domain.StatusPublished}, page)
+	if err != nil {
+		return
+	}
+	if res, err = a.fillCategoryDetails(ctx, res); err != nil {
+		return
+	}
+	if err = a.searcher.Index(ctx, res); err != nil {
+		return
+	}
+	return len(res), cursors.Next, nil
+}
+
+// storeRevision keeps the title and content of the article as written by the
+// caller
+func (a *articleUsecase) storeRevision(ctx context.Context, ar domain.Article) error {
+	caller, _ := domain.UserFromContext(ctx)
+	return a.revisionRepo.Store(ctx, &domain.ArticleRevision{
+		ArticleID: ar.ID,
+		Title:     ar.Title,
+		Content:   ar.Content,
+		UserID:    caller.ID,
+		CreatedAt: ar.UpdatedAt,
+	})
+}
+
+// revisionsOf returns the article whose revisions the caller may read, which
+// takes being allowed to modify it
+func (a *articleUsecase) revisionsOf(ctx context.Context, id int64) (domain.Article, error) {
+	ar, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if err = a.policy.CanModifyArticle(ctx, ar); err != nil {
+		return domain.Article{}, err
+	}
+	return ar, nil
+}
+
+// FetchRevisions lists the revisions of the article, the oldest first unless
+// another sort is asked for
+func (a *articleUsecase) FetchRevisions(c context.Context, id int64, page domain.Page) (res []domain.ArticleRevision, cursors domain.Cursors, err error) {
+	if page.Num == 0 {
+		page.Num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.revisionsOf(ctx, id); err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	opened, err := a.sealer.Open(page, "article-revisions", id)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, cursors, err = a.revisionRepo.FetchByArticle(ctx, id, opened)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+	return res, a.sealer.Seal(page, "article-revisions", id, cursors), nil
+}
+
+// DiffRevisions compares revision rev of the article with revision against,
+// 0 standing for an empty article
+func (a *articleUsecase) DiffRevisions(c context.Context, id int64, rev int64, against int64, by domain.DiffUnit) (res domain.ArticleRevisionDiff, err error) {
+	if by == "" {
+		by = domain.DiffByLine
+	}
+	if !by.Valid() || against < 0 {
+		return domain.ArticleRevisionDiff{}, errHandle.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.revisionsOf(ctx, id); err != nil {
+		return
+	}
+	to, err := a.revisionRepo.GetByNumber(ctx, id, rev)
+	if err != nil {
+		return
+	}
+	from := domain.ArticleRevision{}
+	if against != 0 {
+		if from, err = a.revisionRepo.GetByNumber(ctx, id, against); err != nil {
+			return
+		}
+	}
+
+	return domain.ArticleRevisionDiff{
+		ArticleID: id,
+		From:      against,
+		To:        rev,
+		By:        by,
+		Title:     diff.Compare(from.Title, to.Title, by),
+		Content:   diff.Compare(from.Content, to.Content, by),
+	}, nil
+}
+
+// RestoreRevision puts back the title and content of revision rev, as an
+// update of the given version of the article storing a new revision
+func (a *articleUsecase) RestoreRevision(c context.Context, id int64, rev int64, version int64) (res domain.Article, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	ar, err := a.revisionsOf(ctx, id)
+	if err != nil {
+		return
+	}
+	revision, err := a.revisionRepo.GetByNumber(ctx, id, rev)
+	if err != nil {
+		return
+	}
+
+	ar.Title, ar.Content, ar.Version = revision.Title, revision.Content, version
+	if err = a.Update(ctx, &ar); err != nil {
+		return
+	}
+	return a.fillOne(ctx, ar)
+}
+
+// FetchTrash lists the articles in the trash of the given author, of any
+// author when 0, to the callers who may list the articles not published. The
+// latest trashed come first unless another sort is asked for.
+func (a *articleUsecase) FetchTrash(c context.Context, authorID int64, page domain.Page) (res []domain.Article, cursors domain.Cursors, err error) {
+	if page.Num == 0 {
+		page.Num = 10
+	}
+	if page.Sort == "" {
+		page.Sort = domain.SortDesc
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err = a.canList(ctx, domain.ArticleFilter{AuthorID: authorID}); err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	opened, err := a.sealer.Open(page, "trash-articles", authorID)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, cursors, err = a.articleRepo.FetchTrash(ctx, authorID, opened)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, err = a.fillDetails(ctx, res)
+	if err != nil {
+		return res, domain.Cursors{}, err
+	}
+	return res, a.sealer.Seal(page, "trash-articles", authorID, cursors), nil
+}
+
+// Restore takes the given version of the article out of the trash, back in
+// the listings and search index as it was deleted
+func (a *articleUsecase) Restore(c context.Context, id int64, version int64) (res domain.Article, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	ar, err := a.articleRepo.GetTrashed(ctx, id)
+	if err != nil {
+		return
+	}
+	if err = a.policy.CanModifyArticle(ctx, ar); err != nil {
+		return
+	}
+	if err = a.articleRepo.Restore(ctx, id, version); err != nil {
+		return
+	}
+
+	ar.DeletedAt = nil
+	res, err = a.fillOne(ctx, ar)
+	if err != nil {
+		return
+	}
+	a.syncIndex(ctx, res)
+	return
+}
+
+// PurgeTrash removes for good the articles moved to the trash before the
+// given time, with their revisions
+func (a *articleUsecase) PurgeTrash(c context.Context, before time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.articleRepo.Purge(ctx, before)
 }
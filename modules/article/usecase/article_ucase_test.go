@@ -6,14 +6,44 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/internal/testutil"
 	ucase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/usecase"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
+// newRevisionRepo returns an ArticleRevisionRepository storing every revision
+func newRevisionRepo() *mocks.ArticleRevisionRepository {
+	revisionRepo := new(mocks.ArticleRevisionRepository)
+	revisionRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.ArticleRevision")).Return(nil)
+	return revisionRepo
+}
+
+// lockingAuthorRepo returns an AuthorRepository where every author exists and
+// can be locked
+func lockingAuthorRepo() *mocks.AuthorRepository {
+	authorRepo := new(mocks.AuthorRepository)
+	authorRepo.On("Lock", mock.Anything, mock.Anything).Return(nil)
+	return authorRepo
+}
+
+// newSearcher returns an ArticleSearcher accepting every change to the index
+func newSearcher() *mocks.ArticleSearcher {
+	searcher := new(mocks.ArticleSearcher)
+	searcher.On("Index", mock.Anything, mock.Anything).Return(nil)
+	searcher.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	return searcher
+}
+
 func TestFetch(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
@@ -23,23 +53,23 @@ func TestFetch(t *testing.T) {
 
 	mockListArtilce := make([]domain.Article, 0)
 	mockListArtilce = append(mockListArtilce, mockArticle)
+	published := domain.ArticleFilter{Status: domain.StatusPublished}
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, published, mock.AnythingOfType("domain.Page")).Return(mockListArtilce, domain.Cursors{Next: "next-cursor"}, nil).Once()
 		mockAuthor := domain.Author{
 			ID:   1,
 			Name: "Iman Tumorang",
 		}
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{0}).Return(map[int64]domain.Author{0: mockAuthor}, nil)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64][]domain.Category{}, nil)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
-		cursorExpected := "next-cursor"
-		assert.Equal(t, cursorExpected, nextCursor)
-		assert.NotEmpty(t, nextCursor)
+		list, cursors, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, domain.Page{Cursor: cursor, Num: num})
+		assert.Equal(t, domain.Cursors{Next: "next-cursor"}, cursors)
 		assert.NoError(t, err)
 		assert.Len(t, list, len(mockListArtilce))
 
@@ -47,23 +77,63 @@ func TestFetch(t *testing.T) {
 		mockAuthorrepo.AssertExpectations(t)
 	})
 
+	t.Run("missing-author", func(t *testing.T) {
+		mockArticleRepo.On("Fetch", mock.Anything, published, mock.AnythingOfType("domain.Page")).Return(mockListArtilce, domain.Cursors{Next: "next-cursor"}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64]domain.Author{}, nil).Once()
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64][]domain.Category{}, nil)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		list, cursors, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, domain.Page{Cursor: "12", Num: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", cursors.Next)
+		assert.Len(t, list, len(mockListArtilce))
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+
 	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, published, mock.AnythingOfType("domain.Page")).Return(nil, domain.Cursors{}, errors.New("Unexpexted Error")).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, cursors, err := u.Fetch(context.TODO(), domain.ArticleFilter{}, domain.Page{Cursor: cursor, Num: num})
 
-		assert.Empty(t, nextCursor)
+		assert.Empty(t, cursors)
 		assert.Error(t, err)
 		assert.Len(t, list, 0)
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorrepo.AssertExpectations(t)
 	})
 
+	t.Run("search-by-relevance", func(t *testing.T) {
+		filter := domain.ArticleFilter{Query: "hello", AuthorID: 1}
+		mockArticleRepo.On("Fetch", mock.Anything, domain.ArticleFilter{Query: "hello", AuthorID: 1, Status: domain.StatusPublished}, domain.Page{Num: 10, Sort: domain.SortRelevance}).Return(mockListArtilce, domain.Cursors{}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64]domain.Author{}, nil)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64][]domain.Category{}, nil)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		list, _, err := u.Fetch(context.TODO(), filter, domain.Page{})
+
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("relevance-without-search", func(t *testing.T) {
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), new(mocks.AuthorRepository), new(mocks.CategoryRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, _, err := u.Fetch(context.TODO(), domain.ArticleFilter{AuthorID: 1}, domain.Page{Sort: domain.SortRelevance})
+
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
 }
 
 func TestGetByID(t *testing.T) {
@@ -71,6 +141,7 @@ func TestGetByID(t *testing.T) {
 	mockArticle := domain.Article{
 		Title:   "Hello",
 		Content: "Content",
+		Status:  domain.StatusPublished,
 	}
 	mockAuthor := domain.Author{
 		ID:   1,
@@ -80,8 +151,10 @@ func TestGetByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
 		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockCategoryRepo := new(mocks.CategoryRepository)
 		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64][]domain.Category{}, nil)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
 
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
@@ -95,7 +168,8 @@ func TestGetByID(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Article{}, errors.New("Unexpected")).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
 
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
@@ -114,39 +188,183 @@ func TestStore(t *testing.T) {
 		Title:   "Hello",
 		Content: "Content",
 	}
+	ctx := domain.NewContextWithUser(context.TODO(), domain.User{ID: 1, AuthorID: 4})
 
 	t.Run("success", func(t *testing.T) {
 		tempMockArticle := mockArticle
 		tempMockArticle.ID = 0
-		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
 		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "hello").Return(map[string]int64{}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(0), "hello", mock.AnythingOfType("time.Time")).Return(nil).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockAuthorrepo.On("Lock", mock.Anything, int64(4)).Return(nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(4)).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
 
-		err := u.Store(context.TODO(), &tempMockArticle)
+		err := u.Store(ctx, &tempMockArticle)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockArticle.Title, tempMockArticle.Title)
+		assert.Equal(t, int64(4), tempMockArticle.Author.ID)
+		assert.Equal(t, domain.StatusDraft, tempMockArticle.Status, "a new article is a draft, left out of the index")
+		assert.Equal(t, "hello", tempMockArticle.Slug)
 		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
 	})
-	t.Run("existing-title", func(t *testing.T) {
-		existingArticle := mockArticle
-		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(existingArticle, nil).Once()
-		mockAuthor := domain.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		}
+	t.Run("on-behalf-of-another-author", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		tempMockArticle.Author = domain.Author{ID: 9}
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "hello").Return(map[string]int64{}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(0), "hello", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
+		mockAuthorrepo.On("Lock", mock.Anything, int64(9)).Return(nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(9)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Store(ctx, &tempMockArticle)
 
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), tempMockArticle.Author.ID)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
+	})
+	t.Run("on-behalf-of-unknown-author", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		tempMockArticle.Author = domain.Author{ID: 9}
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("Lock", mock.Anything, int64(9)).Return(errHandle.ErrNotFound).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(9)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Store(ctx, &tempMockArticle)
+
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		assert.Zero(t, tempMockArticle.ID)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+	t.Run("on-behalf-forbidden", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		tempMockArticle.Author = domain.Author{ID: 9}
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(9)).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Store(ctx, &tempMockArticle)
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+	t.Run("with-categories", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		tempMockArticle.Categories = []domain.Category{{ID: 2}, {ID: 3}, {ID: 2}}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 12
+		}).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "hello").Return(map[string]int64{}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(12), "hello", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(2)).Return(domain.Category{ID: 2, Tag: "food"}, nil).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(3)).Return(domain.Category{ID: 3, Tag: "tech"}, nil).Once()
+		mockCategoryRepo.On("AddArticle", mock.Anything, int64(12), int64(2)).Return(nil).Once()
+		mockCategoryRepo.On("AddArticle", mock.Anything, int64(12), int64(3)).Return(nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(4)).Return(nil).Once()
+		mockTransactor := testutil.NewTransactor()
+		u := ucase.NewArticleUsecase(mockArticleRepo, lockingAuthorRepo(), mockCategoryRepo, mockPolicy, mockTransactor, testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Store(ctx, &tempMockArticle)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), tempMockArticle.ID)
+		assert.Equal(t, []domain.Category{{ID: 2, Tag: "food"}, {ID: 3, Tag: "tech"}}, tempMockArticle.Categories)
+		mockArticleRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+		mockTransactor.AssertNumberOfCalls(t, "WithinTx", 1)
+	})
+	t.Run("unknown-category", func(t *testing.T) {
+		tempMockArticle := mockArticle
+		tempMockArticle.Categories = []domain.Category{{ID: 2}, {ID: 99}}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 12
+		}).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "hello").Return(map[string]int64{}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(12), "hello", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(2)).Return(domain.Category{ID: 2}, nil).Once()
+		mockCategoryRepo.On("AddArticle", mock.Anything, int64(12), int64(2)).Return(nil).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(99)).Return(domain.Category{}, errHandle.ErrNotFound).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(4)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, lockingAuthorRepo(), mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Store(ctx, &tempMockArticle)
+
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		assert.Zero(t, tempMockArticle.ID)
+		assert.Empty(t, tempMockArticle.Slug)
+		mockArticleRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+	t.Run("existing-title", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(errHandle.ErrConflict).Once()
+		mockAuthorrepo := lockingAuthorRepo()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(4)).Return(nil).Once()
+		mockRevisionRepo := newRevisionRepo()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), mockRevisionRepo, time.Second*2)
+
+		tempMockArticle := mockArticle
+		err := u.Store(ctx, &tempMockArticle)
+
+		assert.Equal(t, errHandle.ErrConflict, err, "told by the repository")
+		mockArticleRepo.AssertExpectations(t)
+		mockArticleRepo.AssertNotCalled(t, "GetByTitle", mock.Anything, mock.Anything)
+		mockRevisionRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
 
 		err := u.Store(context.TODO(), &mockArticle)
 
-		assert.Error(t, err)
+		assert.Equal(t, errHandle.ErrForbidden, err)
 		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
 	})
 
 }
@@ -154,6 +372,7 @@ func TestStore(t *testing.T) {
 func TestDelete(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
+		ID:      10,
 		Title:   "Hello",
 		Content: "Content",
 	}
@@ -161,41 +380,94 @@ func TestDelete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
 
-		mockArticleRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64"), int64(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mockArticle).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		mockSearcher.On("Delete", mock.Anything, mockArticle.ID).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Delete(context.TODO(), mockArticle.ID, 1)
 
 		assert.NoError(t, err)
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorrepo.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
 	})
 	t.Run("article-is-not-exist", func(t *testing.T) {
-		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Article{}, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Article{}, errHandle.ErrNotFound).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Delete(context.TODO(), mockArticle.ID, 1)
 
-		assert.Error(t, err)
+		assert.Equal(t, errHandle.ErrNotFound, err)
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorrepo.AssertExpectations(t)
 	})
+	t.Run("trashed-meanwhile", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, mockArticle.ID, int64(1), mock.AnythingOfType("time.Time")).Return(errHandle.ErrNotFound).Once()
+
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mockArticle).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+		err := u.Delete(context.TODO(), mockArticle.ID, 1)
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+	t.Run("stale-version", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, mockArticle.ID, int64(1), mock.AnythingOfType("time.Time")).Return(errHandle.ErrPreconditionFailed).Once()
+
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mockArticle).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+		err := u.Delete(context.TODO(), mockArticle.ID, 1)
+
+		assert.Equal(t, errHandle.ErrPreconditionFailed, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
 	t.Run("error-happens-in-db", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Article{}, errors.New("Unexpected Error")).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Delete(context.TODO(), mockArticle.ID, 1)
 
 		assert.Error(t, err)
 		mockArticleRepo.AssertExpectations(t)
 		mockAuthorrepo.AssertExpectations(t)
 	})
+	t.Run("not-the-owner", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mockArticle).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Delete(context.TODO(), mockArticle.ID, 1)
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
+	})
 
 }
 
@@ -203,18 +475,1112 @@ func TestUpdate(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
 		Title:   "Hello",
+		Slug:    "hello",
 		Content: "Content",
 		ID:      23,
+		Status:  domain.StatusPublished,
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Update", mock.Anything, &mockArticle).Once().Return(nil)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil).Twice()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Once().Return(nil)
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(0)).Return(domain.Author{Name: "Iman Tumorang"}, nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{mockArticle.ID}).
+			Return(map[int64][]domain.Category{mockArticle.ID: {{ID: 3, Tag: "sport"}}}, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil).Twice()
+		stored := mockArticle
+		stored.Author = domain.Author{Name: "Iman Tumorang"}
+		stored.Categories = []domain.Category{{ID: 3, Tag: "sport"}}
+		mockSearcher := new(mocks.ArticleSearcher)
+		mockSearcher.On("Index", mock.Anything, []domain.Article{stored}).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+		updated := mockArticle
+		err := u.Update(context.TODO(), &updated)
+		assert.NoError(t, err)
+		assert.Equal(t, stored, updated, "the update is answered with the article as stored")
+		mockArticleRepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
+	})
+
+	t.Run("reassign-to-another-author", func(t *testing.T) {
+		stored := mockArticle
+		stored.Author = domain.Author{ID: 1}
+		updated := mockArticle
+		updated.Author = domain.Author{ID: 2}
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(stored, nil).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, stored).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(2)).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Update(context.TODO(), &updated)
+		assert.Equal(t, errHandle.ErrForbidden, err, "editing any article is not posting as any author")
+		mockArticleRepo.AssertNotCalled(t, "Update", mock.Anything, &updated)
+		mockAuthorrepo.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("keeps-author-when-left-out", func(t *testing.T) {
+		stored := mockArticle
+		stored.Author = domain.Author{ID: 1, Name: "Iman Tumorang"}
+		updated := mockArticle
+		updated.Author = domain.Author{}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(stored, nil)
+		mockArticleRepo.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.Author.ID == 1
+		})).Return(nil).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(stored.Author, nil).Maybe()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{mockArticle.ID}).Return(map[int64][]domain.Category{}, nil).Maybe()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, stored).Return(nil).Twice()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Update(context.TODO(), &updated)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), updated.Author.ID)
+		mockArticleRepo.AssertExpectations(t)
+		mockPolicy.AssertNotCalled(t, "CanPostAsAuthor", mock.Anything, mock.Anything)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("admin-reassigns", func(t *testing.T) {
+		stored := mockArticle
+		stored.Author = domain.Author{ID: 1}
+		updated := mockArticle
+		updated.Author = domain.Author{ID: 2}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(stored, nil)
+		mockArticleRepo.On("Update", mock.Anything, &updated).Return(nil).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("Lock", mock.Anything, int64(2)).Return(nil).Once()
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Maybe()
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(2)).Return(domain.Author{ID: 2}, nil).Maybe()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{mockArticle.ID}).Return(map[int64][]domain.Category{}, nil).Maybe()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil).Twice()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(2)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Update(context.TODO(), &updated)
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("reassign-to-unknown-author", func(t *testing.T) {
+		stored := mockArticle
+		stored.Author = domain.Author{ID: 1}
+		updated := mockArticle
+		updated.Author = domain.Author{ID: 99}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(stored, nil).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("Lock", mock.Anything, int64(99)).Return(errHandle.ErrNotFound).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil).Twice()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(99)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Update(context.TODO(), &updated)
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		mockArticleRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+
+	t.Run("retried-after-deadlock", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		dbMock.ExpectBegin()
+		dbMock.ExpectRollback()
+		dbMock.ExpectBegin()
+		dbMock.ExpectCommit()
+
+		stored := mockArticle
+		stored.Version = 3
+		mockArticleRepo := new(mocks.ArticleRepository)
+		saved := stored
+		saved.Title, saved.Slug, saved.Version = "Hello Again", "hello-again", 4
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(saved, nil).Once()
+		var versions []int64
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			ar := args.Get(1).(*domain.Article)
+			versions = append(versions, ar.Version)
+			ar.Version++
+		}).Return(nil).Twice()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "hello-again").Return(map[string]int64{}, nil).Twice()
+		mockArticleRepo.On("SetSlug", mock.Anything, stored.ID, "hello-again", mock.AnythingOfType("time.Time")).Return(nil).Twice()
+		mockRevisionRepo := new(mocks.ArticleRevisionRepository)
+		mockRevisionRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.ArticleRevision")).Return(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}).Once()
+		mockRevisionRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.ArticleRevision")).Return(nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{stored.ID}).Return(map[int64][]domain.Category{}, nil).Maybe()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil)
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("GetByID", mock.Anything, int64(0)).Return(domain.Author{}, nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorRepo, mockCategoryRepo, mockPolicy, transaction.NewSQLTransactor(db), testutil.NewSealer(), newSearcher(), mockRevisionRepo, time.Second*2)
+
+		updated := stored
+		updated.Title = "Hello Again"
+		err = u.Update(context.TODO(), &updated)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 3}, versions, "the retry updates the version the client had")
+		assert.Equal(t, int64(4), updated.Version)
+		assert.Equal(t, "hello-again", updated.Slug)
+		mockArticleRepo.AssertExpectations(t)
+		mockRevisionRepo.AssertExpectations(t)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+	t.Run("moves-to-the-given-categories", func(t *testing.T) {
+		food := domain.Category{ID: 1, Name: "Food", Tag: "food"}
+		drink := domain.Category{ID: 2, Name: "Drink", Tag: "drink"}
+		sport := domain.Category{ID: 3, Name: "Sport", Tag: "sport"}
+		updated := mockArticle
+		updated.Categories = []domain.Category{{ID: 2}, {ID: 3}, {ID: 3}}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil)
+		mockArticleRepo.On("Update", mock.Anything, &updated).Return(nil).Once()
+
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{mockArticle.ID}).
+			Return(map[int64][]domain.Category{mockArticle.ID: {food, drink}}, nil).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(3)).Return(sport, nil).Once()
+		mockCategoryRepo.On("AddArticle", mock.Anything, mockArticle.ID, int64(3)).Return(nil).Once()
+		mockCategoryRepo.On("RemoveArticle", mock.Anything, mockArticle.ID, int64(1)).Return(nil).Once()
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{mockArticle.ID}).
+			Return(map[int64][]domain.Category{mockArticle.ID: {drink, sport}}, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil).Twice()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("GetByID", mock.Anything, int64(0)).Return(domain.Author{}, nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorRepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Update(context.TODO(), &updated)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Category{drink, sport}, updated.Categories)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-category", func(t *testing.T) {
+		updated := mockArticle
+		updated.Categories = []domain.Category{{ID: 9}}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, mockArticle.ID).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, &updated).Return(nil).Once()
+
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{mockArticle.ID}).
+			Return(map[int64][]domain.Category{}, nil).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Category{}, errHandle.ErrNotFound).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil).Twice()
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		err := u.Update(context.TODO(), &updated)
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		mockCategoryRepo.AssertNotCalled(t, "AddArticle", mock.Anything, mock.Anything, mock.Anything)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestFetchByCategory(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockListArticle := []domain.Article{
+		{ID: 1, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}},
+	}
+	mockCategory := domain.Category{ID: 3, Name: "Makanan", Tag: "food"}
+
+	t.Run("success", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByTag", mock.Anything, "food").Return(mockCategory, nil).Once()
+		mockArticleRepo.On("FetchByCategory", mock.Anything, int64(3), domain.StatusPublished, domain.Page{Num: 10}).Return(mockListArticle, domain.Cursors{Next: "next-cursor"}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]domain.Author{1: {ID: 1, Name: "Iman Tumorang"}}, nil)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).
+			Return(map[int64][]domain.Category{1: {mockCategory}}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+		list, cursors, err := u.FetchByCategory(context.TODO(), "food", domain.Page{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", cursors.Next)
+		assert.Len(t, list, 1)
+		assert.Equal(t, []domain.Category{mockCategory}, list[0].Categories)
+		assert.Equal(t, "Iman Tumorang", list[0].Author.Name)
+		mockArticleRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-tag", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByTag", mock.Anything, "sport").Return(domain.Category{}, errHandle.ErrNotFound).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+		list, cursors, err := u.FetchByCategory(context.TODO(), "sport", domain.Page{})
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		assert.Empty(t, cursors)
+		assert.Len(t, list, 0)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestFetchByAuthor(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockAuthor := domain.Author{ID: 1, Name: "Iman Tumorang"}
+	mockListArticle := []domain.Article{
+		{ID: 1, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(mockAuthor, nil)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]domain.Author{1: mockAuthor}, nil)
+		mockArticleRepo.On("FetchByAuthor", mock.Anything, int64(1), domain.StatusPublished, domain.Page{Num: 10}).Return(mockListArticle, domain.Cursors{Next: "next-cursor"}, nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).Return(map[int64][]domain.Category{}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+		list, cursors, err := u.FetchByAuthor(context.TODO(), 1, domain.Page{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", cursors.Next)
+		assert.Len(t, list, 1)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-author", func(t *testing.T) {
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Author{}, errHandle.ErrNotFound)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+		list, _, err := u.FetchByAuthor(context.TODO(), 9, domain.Page{})
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		assert.Len(t, list, 0)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+}
+
+func TestFetchByAuthorSealedCursor(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockAuthorrepo := new(mocks.AuthorRepository)
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Author{ID: 1}, nil)
+	mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]domain.Author{1: {ID: 1}}, nil)
+	mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).Return(map[int64][]domain.Category{}, nil)
+	mockListArticle := []domain.Article{{ID: 1, Title: "Hello", Author: domain.Author{ID: 1}}}
+	mockArticleRepo.On("FetchByAuthor", mock.Anything, int64(1), domain.StatusPublished, domain.Page{Num: 10}).Return(mockListArticle, domain.Cursors{Next: "next-cursor"}, nil).Once()
+	mockArticleRepo.On("FetchByAuthor", mock.Anything, int64(1), domain.StatusPublished, domain.Page{Cursor: "next-cursor", Num: 10}).Return(mockListArticle, domain.Cursors{}, nil).Once()
+
+	u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), pagination.NewSealer([]byte("secret"), time.Minute), newSearcher(), newRevisionRepo(), time.Second*2)
+	_, cursors, err := u.FetchByAuthor(context.TODO(), 1, domain.Page{})
+	require.NoError(t, err)
+	require.NotEmpty(t, cursors.Next)
+	assert.NotEqual(t, "next-cursor", cursors.Next)
+
+	t.Run("same-listing", func(t *testing.T) {
+		_, _, err := u.FetchByAuthor(context.TODO(), 1, domain.Page{Cursor: cursors.Next})
+		assert.NoError(t, err)
+	})
+
+	t.Run("other-author", func(t *testing.T) {
+		_, _, err := u.FetchByAuthor(context.TODO(), 2, domain.Page{Cursor: cursors.Next})
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+
+	t.Run("other-sort", func(t *testing.T) {
+		_, _, err := u.FetchByAuthor(context.TODO(), 1, domain.Page{Cursor: cursors.Next, Sort: domain.SortDesc})
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+
+	mockArticleRepo.AssertExpectations(t)
+}
+
+func TestSearch(t *testing.T) {
+	query := domain.ArticleSearchQuery{Query: "lari", Category: "sport"}
+
+	t.Run("success", func(t *testing.T) {
+		mockSearcher := new(mocks.ArticleSearcher)
+		mockSearcher.On("Search", mock.Anything, domain.ArticleSearchQuery{Query: "lari", Category: "sport", Num: 10}).
+			Return(domain.ArticleSearchResult{
+				Total: 1,
+				Hits: []domain.ArticleHit{{
+					Article:    domain.Article{ID: 1, Title: "Lari pagi", Author: domain.Author{ID: 4}},
+					Highlights: map[string][]string{"title": {"<mark>Lari</mark> pagi"}},
+				}},
+				Facets: map[string][]domain.FacetTerm{
+					domain.FacetAuthor:   {{Term: "4", Count: 1}},
+					domain.FacetCategory: {{Term: "sport", Count: 1}},
+				},
+			}, nil).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{4}).Return(map[int64]domain.Author{4: {ID: 4, Name: "Iman"}}, nil).Twice()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).
+			Return(map[int64][]domain.Category{1: {{ID: 3, Name: "Olahraga", Tag: "sport"}}}, nil).Once()
+		mockCategoryRepo.On("Fetch", mock.Anything).Return([]domain.Category{{ID: 3, Name: "Olahraga", Tag: "sport"}}, nil).Once()
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+		res, err := u.Search(context.TODO(), query)
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Hits, 1)
+		assert.Equal(t, "Iman", res.Hits[0].Article.Author.Name)
+		assert.Equal(t, []domain.Category{{ID: 3, Name: "Olahraga", Tag: "sport"}}, res.Hits[0].Article.Categories)
+		assert.Equal(t, []domain.FacetTerm{{Term: "4", Label: "Iman", Count: 1}}, res.Facets[domain.FacetAuthor])
+		assert.Equal(t, []domain.FacetTerm{{Term: "sport", Label: "Olahraga", Count: 1}}, res.Facets[domain.FacetCategory])
+		mockSearcher.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+	t.Run("too-many", func(t *testing.T) {
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), new(mocks.AuthorRepository), new(mocks.CategoryRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), new(mocks.ArticleSearcher), newRevisionRepo(), time.Second*2)
+
+		_, err := u.Search(context.TODO(), domain.ArticleSearchQuery{Num: 101})
+
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+}
+
+func TestReindex(t *testing.T) {
+	published := domain.ArticleFilter{Status: domain.StatusPublished}
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("Fetch", mock.Anything, published, domain.Page{Num: 100}).
+		Return([]domain.Article{{ID: 1}, {ID: 2}}, domain.Cursors{Next: "next"}, nil).Once()
+	mockArticleRepo.On("Fetch", mock.Anything, published, domain.Page{Num: 100, Cursor: "next"}).
+		Return([]domain.Article{{ID: 3}}, domain.Cursors{Prev: "prev"}, nil).Once()
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1, 2}).
+		Return(map[int64][]domain.Category{2: {{ID: 3, Tag: "sport"}}}, nil).Once()
+	mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{3}).Return(map[int64][]domain.Category{}, nil).Once()
+	mockSearcher := new(mocks.ArticleSearcher)
+	mockSearcher.On("Index", mock.Anything, []domain.Article{
+		{ID: 1, Categories: []domain.Category{}},
+		{ID: 2, Categories: []domain.Category{{ID: 3, Tag: "sport"}}},
+	}).Return(nil).Once()
+	mockSearcher.On("Index", mock.Anything, []domain.Article{{ID: 3, Categories: []domain.Category{}}}).Return(nil).Once()
+	u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+	n, err := u.Reindex(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	mockArticleRepo.AssertExpectations(t)
+	mockCategoryRepo.AssertExpectations(t)
+	mockSearcher.AssertExpectations(t)
+}
+
+func TestGetByIDDraft(t *testing.T) {
+	draft := domain.Article{ID: 7, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft}
+
+	t.Run("hidden", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, draft.ID).Return(draft, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, draft).Return(errHandle.ErrForbidden).Once()
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, err := u.GetByID(context.TODO(), draft.ID)
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("by-its-author", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, draft.ID).Return(draft, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{draft.ID}).Return(map[int64][]domain.Category{}, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, draft).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		a, err := u.GetByID(context.TODO(), draft.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusDraft, a.Status)
+		mockPolicy.AssertExpectations(t)
+	})
+}
+
+func TestFetchDrafts(t *testing.T) {
+	t.Run("of-own-author", func(t *testing.T) {
+		filter := domain.ArticleFilter{AuthorID: 1, Status: domain.StatusDraft}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, filter, domain.Page{Num: 10}).Return([]domain.Article{}, domain.Cursors{}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64]domain.Author{}, nil)
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, mock.AnythingOfType("[]int64")).Return(map[int64][]domain.Category{}, nil)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, domain.Article{Author: domain.Author{ID: 1}}).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, _, err := u.Fetch(context.TODO(), filter, domain.Page{})
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("of-everyone-forbidden", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, _, err := u.Fetch(context.TODO(), domain.ArticleFilter{Status: domain.StatusInReview}, domain.Page{})
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("unknown-status", func(t *testing.T) {
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), new(mocks.AuthorRepository), new(mocks.CategoryRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, _, err := u.Fetch(context.TODO(), domain.ArticleFilter{Status: "deleted"}, domain.Page{})
+
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+}
+
+func TestTransitions(t *testing.T) {
+	stored := domain.Article{ID: 7, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}, Version: 2}
+
+	// newUsecase returns a usecase whose repository holds the article in the
+	// given status and accepts one status update
+	newUsecase := func(status domain.ArticleStatus, mockPolicy *mocks.Policy, mockSearcher *mocks.ArticleSearcher) (domain.ArticleUsecase, *mocks.ArticleRepository) {
+		ar := stored
+		ar.Status = status
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(ar, nil).Once()
+		mockArticleRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).Version++
+		}).Return(nil).Maybe()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1, Name: "Iman Tumorang"}, nil).Maybe()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{stored.ID}).Return(map[int64][]domain.Category{}, nil).Maybe()
+		return ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2), mockArticleRepo
+	}
+
+	t.Run("submit", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		mockSearcher.On("Delete", mock.Anything, stored.ID).Return(nil).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusDraft, mockPolicy, mockSearcher)
+
+		a, err := u.Submit(context.TODO(), stored.ID, stored.Version)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusInReview, a.Status)
+		assert.Nil(t, a.PublishedAt)
+		assert.Equal(t, int64(3), a.Version)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
+	})
+
+	t.Run("stale-version", func(t *testing.T) {
+		ar := stored
+		ar.Status = domain.StatusDraft
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(ar, nil).Once()
+		mockArticleRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.Version == stored.Version-1
+		})).Return(errHandle.ErrPreconditionFailed).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+		_, err := u.Submit(context.TODO(), stored.ID, stored.Version-1)
+
+		assert.Equal(t, errHandle.ErrPreconditionFailed, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		mockSearcher.On("Index", mock.Anything, mock.MatchedBy(func(articles []domain.Article) bool {
+			return len(articles) == 1 && articles[0].Status == domain.StatusPublished
+		})).Return(nil).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusInReview, mockPolicy, mockSearcher)
+
+		a, err := u.Publish(context.TODO(), stored.ID, stored.Version)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusPublished, a.Status)
+		require.NotNil(t, a.PublishedAt)
+		assert.Equal(t, a.UpdatedAt, *a.PublishedAt)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
+	})
+
+	t.Run("publish-forbidden", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusInReview, mockPolicy, new(mocks.ArticleSearcher))
+
+		_, err := u.Publish(context.TODO(), stored.ID, stored.Version)
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockArticleRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unpublish", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		mockSearcher.On("Delete", mock.Anything, stored.ID).Return(nil).Once()
+		u, _ := newUsecase(domain.StatusPublished, mockPolicy, mockSearcher)
+
+		a, err := u.Unpublish(context.TODO(), stored.ID, stored.Version)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusDraft, a.Status)
+		assert.Nil(t, a.PublishedAt)
+		mockSearcher.AssertExpectations(t)
+	})
+
+	t.Run("archive-published-by-its-author", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusPublished, mockPolicy, new(mocks.ArticleSearcher))
+
+		_, err := u.Archive(context.TODO(), stored.ID, stored.Version)
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockArticleRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("invalid-transition", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(nil).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusArchived, mockPolicy, new(mocks.ArticleSearcher))
+
+		_, err := u.Publish(context.TODO(), stored.ID, stored.Version)
+
+		assert.Equal(t, errHandle.ErrInvalidTransition, err)
+		mockArticleRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		publishAt := time.Now().Add(time.Hour)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(nil).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusInReview, mockPolicy, new(mocks.ArticleSearcher))
+
+		a, err := u.Schedule(context.TODO(), stored.ID, stored.Version, &publishAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusInReview, a.Status)
+		require.NotNil(t, a.PublishAt)
+		assert.Equal(t, publishAt, *a.PublishAt)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("schedule-in-the-past", func(t *testing.T) {
+		publishAt := time.Now().Add(-time.Hour)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(nil).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusDraft, mockPolicy, new(mocks.ArticleSearcher))
+
+		_, err := u.Schedule(context.TODO(), stored.ID, stored.Version, &publishAt)
+
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		mockArticleRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("schedule-published", func(t *testing.T) {
+		publishAt := time.Now().Add(time.Hour)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(nil).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusPublished, mockPolicy, new(mocks.ArticleSearcher))
+
+		_, err := u.Schedule(context.TODO(), stored.ID, stored.Version, &publishAt)
+
+		assert.Equal(t, errHandle.ErrInvalidTransition, err)
+		mockArticleRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("schedule-forbidden", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u, mockArticleRepo := newUsecase(domain.StatusDraft, mockPolicy, new(mocks.ArticleSearcher))
+
+		_, err := u.Schedule(context.TODO(), stored.ID, stored.Version, nil)
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockArticleRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPublishDue(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC)
+	publishAt := now.Add(-time.Minute)
+	due := []domain.Article{
+		{ID: 1, Author: domain.Author{ID: 1}, Status: domain.StatusInReview, PublishAt: &publishAt, Version: 2},
+		{ID: 2, Author: domain.Author{ID: 1}, Status: domain.StatusDraft, PublishAt: &publishAt, Version: 1},
+	}
+	published := func(id int64) interface{} {
+		return mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == id && ar.Status == domain.StatusPublished && ar.PublishAt == nil &&
+				ar.PublishedAt != nil && ar.PublishedAt.Equal(now)
+		})
+	}
+
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("FetchDue", mock.Anything, now, int64(100)).Return(due, nil).Once()
+	mockArticleRepo.On("UpdateStatus", mock.Anything, published(1)).Return(nil).Once()
+	mockArticleRepo.On("UpdateStatus", mock.Anything, published(2)).Return(errHandle.ErrPreconditionFailed).Once()
+	mockArticleRepo.On("GetByID", mock.Anything, int64(1)).
+		Return(domain.Article{ID: 1, Author: domain.Author{ID: 1}, Status: domain.StatusPublished}, nil).Once()
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).Return(map[int64][]domain.Category{}, nil).Once()
+	mockSearcher := new(mocks.ArticleSearcher)
+	mockSearcher.On("Index", mock.Anything, mock.MatchedBy(func(articles []domain.Article) bool {
+		return len(articles) == 1 && articles[0].ID == 1
+	})).Return(nil).Once()
+	u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+	n, err := u.PublishDue(context.TODO(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n, "the article changed since it was listed is left out")
+	mockArticleRepo.AssertExpectations(t)
+	mockSearcher.AssertExpectations(t)
+}
+
+func TestRevisions(t *testing.T) {
+	stored := domain.Article{ID: 7, Title: "Hello", Slug: "hello", Content: "one\ntwo\n", Author: domain.Author{ID: 1}, Status: domain.StatusPublished, Version: 2}
+	first := domain.ArticleRevision{ID: 10, ArticleID: 7, Number: 1, Title: "Hi", Content: "one\n", UserID: 3}
+	second := domain.ArticleRevision{ID: 11, ArticleID: 7, Number: 2, Title: "Hello", Content: "one\ntwo\n", UserID: 4}
+
+	// saved is the article as found by id, stored until an update saves it
+	var saved domain.Article
+
+	// newUsecase returns a usecase over the stored article and its two
+	// revisions, the caller being allowed on the article unless denied
+	newUsecase := func(denied error) (domain.ArticleUsecase, *mocks.ArticleRepository, *mocks.ArticleRevisionRepository) {
+		saved = stored
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(func(context.Context, int64) domain.Article { return saved }, nil)
+		mockArticleRepo.On("FetchSlugs", mock.Anything, mock.AnythingOfType("string")).Return(map[string]int64{}, nil).Maybe()
+		mockArticleRepo.On("SetSlug", mock.Anything, stored.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil).Maybe()
+		mockRevisionRepo := new(mocks.ArticleRevisionRepository)
+		mockRevisionRepo.On("GetByNumber", mock.Anything, stored.ID, int64(1)).Return(first, nil).Maybe()
+		mockRevisionRepo.On("GetByNumber", mock.Anything, stored.ID, int64(2)).Return(second, nil).Maybe()
+		mockRevisionRepo.On("GetByNumber", mock.Anything, stored.ID, mock.AnythingOfType("int64")).Return(domain.ArticleRevision{}, errHandle.ErrNotFound).Maybe()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(denied)
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Maybe()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{stored.ID}).Return(map[int64][]domain.Category{}, nil).Maybe()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), mockRevisionRepo, time.Second*2)
+		return u, mockArticleRepo, mockRevisionRepo
+	}
+
+	t.Run("update-stores-a-revision", func(t *testing.T) {
+		u, mockArticleRepo, mockRevisionRepo := newUsecase(nil)
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockRevisionRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.ArticleRevision) bool {
+			return r.ArticleID == stored.ID && r.Title == "Bye" && r.Content == "three\n" && r.UserID == 5
+		})).Return(nil).Once()
+		ctx := domain.NewContextWithUser(context.TODO(), domain.User{ID: 5})
+
+		ar := stored
+		ar.Title, ar.Content = "Bye", "three\n"
+		err := u.Update(ctx, &ar)
+
+		assert.NoError(t, err)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		u, _, mockRevisionRepo := newUsecase(nil)
+		mockRevisionRepo.On("FetchByArticle", mock.Anything, stored.ID, domain.Page{Num: 10}).
+			Return([]domain.ArticleRevision{first, second}, domain.Cursors{}, nil).Once()
+
+		list, _, err := u.FetchRevisions(context.TODO(), stored.ID, domain.Page{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ArticleRevision{first, second}, list)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("list-forbidden", func(t *testing.T) {
+		u, _, mockRevisionRepo := newUsecase(errHandle.ErrForbidden)
+
+		_, _, err := u.FetchRevisions(context.TODO(), stored.ID, domain.Page{})
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockRevisionRepo.AssertNotCalled(t, "FetchByArticle", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		u, _, _ := newUsecase(nil)
+
+		d, err := u.DiffRevisions(context.TODO(), stored.ID, 2, 1, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ArticleRevisionDiff{
+			ArticleID: stored.ID, From: 1, To: 2, By: domain.DiffByLine,
+			Title:   []domain.DiffChunk{{Op: domain.DiffDelete, Text: "Hi"}, {Op: domain.DiffInsert, Text: "Hello"}},
+			Content: []domain.DiffChunk{{Op: domain.DiffEqual, Text: "one\n"}, {Op: domain.DiffInsert, Text: "two\n"}},
+		}, d)
+	})
+
+	t.Run("diff-against-nothing", func(t *testing.T) {
+		u, _, _ := newUsecase(nil)
+
+		d, err := u.DiffRevisions(context.TODO(), stored.ID, 1, 0, domain.DiffByWord)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.DiffChunk{{Op: domain.DiffInsert, Text: "Hi"}}, d.Title)
+	})
+
+	t.Run("diff-unknown-revision", func(t *testing.T) {
+		u, _, _ := newUsecase(nil)
+
+		_, err := u.DiffRevisions(context.TODO(), stored.ID, 2, 9, domain.DiffByLine)
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
+
+	t.Run("diff-unknown-unit", func(t *testing.T) {
+		u, _, _ := newUsecase(nil)
+
+		_, err := u.DiffRevisions(context.TODO(), stored.ID, 2, 1, "char")
+
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		u, mockArticleRepo, mockRevisionRepo := newUsecase(nil)
+		mockArticleRepo.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.Title == first.Title && ar.Content == first.Content && ar.Version == stored.Version
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).Version++
+			saved = *args.Get(1).(*domain.Article)
+		}).Return(nil).Once()
+		mockRevisionRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.ArticleRevision) bool {
+			return r.Title == first.Title && r.Content == first.Content
+		})).Return(nil).Once()
+
+		a, err := u.RestoreRevision(context.TODO(), stored.ID, 1, stored.Version)
+
+		assert.NoError(t, err)
+		assert.Equal(t, first.Title, a.Title)
+		assert.Equal(t, stored.Version+1, a.Version)
+		assert.Equal(t, domain.StatusPublished, a.Status)
+		mockArticleRepo.AssertExpectations(t)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("restore-stale-version", func(t *testing.T) {
+		u, mockArticleRepo, mockRevisionRepo := newUsecase(nil)
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(errHandle.ErrPreconditionFailed).Once()
+
+		_, err := u.RestoreRevision(context.TODO(), stored.ID, 1, stored.Version-1)
+
+		assert.Equal(t, errHandle.ErrPreconditionFailed, err)
+		mockRevisionRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	deletedAt := time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC)
+	trashed := domain.Article{ID: 7, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished, DeletedAt: &deletedAt, Version: 2}
+
+	t.Run("fetch-of-everyone", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("FetchTrash", mock.Anything, int64(0), domain.Page{Num: 10, Sort: domain.SortDesc}).
+			Return([]domain.Article{trashed}, domain.Cursors{}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]domain.Author{1: {ID: 1, Name: "Iman"}}, nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{7}).Return(map[int64][]domain.Category{}, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		list, _, err := u.FetchTrash(context.TODO(), 0, domain.Page{})
+
+		assert.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "Iman", list[0].Author.Name)
+		mockArticleRepo.AssertExpectations(t)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("fetch-of-another-author-forbidden", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, domain.Article{Author: domain.Author{ID: 2}}).Return(errHandle.ErrForbidden).Once()
+		mockPolicy.On("CanPublishArticle", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(new(mocks.ArticleRepository), new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, _, err := u.FetchTrash(context.TODO(), 2, domain.Page{})
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockPolicy.AssertExpectations(t)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetTrashed", mock.Anything, trashed.ID).Return(trashed, nil).Once()
+		mockArticleRepo.On("Restore", mock.Anything, trashed.ID, trashed.Version).Return(nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{7}).Return(map[int64][]domain.Category{}, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, trashed).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		mockSearcher.On("Index", mock.Anything, mock.MatchedBy(func(articles []domain.Article) bool {
+			return len(articles) == 1 && articles[0].ID == trashed.ID
+		})).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+		res, err := u.Restore(context.TODO(), trashed.ID, trashed.Version)
+
+		assert.NoError(t, err)
+		assert.Nil(t, res.DeletedAt)
+		assert.Equal(t, int64(2), res.Version)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
+	})
+
+	t.Run("restore-forbidden", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetTrashed", mock.Anything, trashed.ID).Return(trashed, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, trashed).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, err := u.Restore(context.TODO(), trashed.ID, trashed.Version)
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockArticleRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("restore-stale-version", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetTrashed", mock.Anything, trashed.ID).Return(trashed, nil).Once()
+		mockArticleRepo.On("Restore", mock.Anything, trashed.ID, int64(1)).Return(errHandle.ErrPreconditionFailed).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, trashed).Return(nil).Once()
+		mockSearcher := new(mocks.ArticleSearcher)
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), mockSearcher, newRevisionRepo(), time.Second*2)
+
+		_, err := u.Restore(context.TODO(), trashed.ID, 1)
+
+		assert.Equal(t, errHandle.ErrPreconditionFailed, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertNotCalled(t, "Index", mock.Anything, mock.Anything)
+	})
+
+	t.Run("restore-not-in-the-trash", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetTrashed", mock.Anything, int64(8)).Return(domain.Article{}, errHandle.ErrNotFound).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		_, err := u.Restore(context.TODO(), 8, 1)
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Purge", mock.Anything, deletedAt).Return(3, nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		n, err := u.PurgeTrash(context.TODO(), deletedAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestSlugs(t *testing.T) {
+	ctx := domain.NewContextWithUser(context.TODO(), domain.User{ID: 1, AuthorID: 4})
+	stored := domain.Article{ID: 7, Title: "Teh", Slug: "teh", Content: "Content", Author: domain.Author{ID: 4}, Status: domain.StatusPublished, Version: 2}
+
+	t.Run("store-suffixes-on-collision", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 12
+		}).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "kopi").Return(map[string]int64{"kopi": 3, "kopi-2": 5, "kopi-susu": 6}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(12), "kopi-3", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(4)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, lockingAuthorRepo(), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		ar := domain.Article{Title: "Kopi?", Content: "Content", Slug: "chosen-by-the-caller"}
+		err := u.Store(ctx, &ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "kopi-3", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-takes-the-next-slug-taken-meanwhile", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 12
+		}).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "kopi").Return(map[string]int64{"kopi": 3}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(12), "kopi-2", mock.AnythingOfType("time.Time")).Return(errHandle.ErrConflict).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(12), "kopi-3", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(4)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, lockingAuthorRepo(), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		ar := domain.Article{Title: "Kopi", Content: "Content"}
+		err := u.Store(ctx, &ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "kopi-3", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-gives-up-without-a-conflict", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 12
+		}).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "kopi").Return(map[string]int64{}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(12), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(errHandle.ErrConflict).Times(10)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanCreateArticle", mock.Anything).Return(nil).Once()
+		mockPolicy.On("CanPostAsAuthor", mock.Anything, int64(4)).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, lockingAuthorRepo(), new(mocks.CategoryRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		ar := domain.Article{Title: "Kopi", Content: "Content"}
+		err := u.Store(ctx, &ar)
+
+		assert.Error(t, err)
+		assert.NotEqual(t, errHandle.ErrConflict, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	// update gives stored another title, the slugs made of it being taken as
+	// given and set expected to be its new slug, unless taken is nil
+	update := func(title string, taken map[string]int64, set string) (domain.Article, *mocks.ArticleRepository, error) {
+		saved := stored
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, stored.ID).Return(func(context.Context, int64) domain.Article { return saved }, nil)
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Run(func(args mock.Arguments) {
+			saved = *args.Get(1).(*domain.Article)
+		}).Return(nil).Once()
+		if taken != nil {
+			mockArticleRepo.On("FetchSlugs", mock.Anything, mock.AnythingOfType("string")).Return(taken, nil).Once()
+			mockArticleRepo.On("SetSlug", mock.Anything, stored.ID, set, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+				saved.Slug = set
+			}).Return(nil).Once()
+		}
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{stored.ID}).Return(map[int64][]domain.Category{}, nil).Maybe()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil)
+		mockAuthorRepo := lockingAuthorRepo()
+		mockAuthorRepo.On("GetByID", mock.Anything, stored.Author.ID).Return(stored.Author, nil)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorRepo, mockCategoryRepo, mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		ar := stored
+		ar.Title, ar.Slug = title, ""
+		err := u.Update(ctx, &ar)
+		return ar, mockArticleRepo, err
+	}
+
+	t.Run("update-same-slug", func(t *testing.T) {
+		ar, mockArticleRepo, err := update("Teh!", nil, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "teh", ar.Slug)
+		mockArticleRepo.AssertNotCalled(t, "SetSlug", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("update-new-title", func(t *testing.T) {
+		ar, mockArticleRepo, err := update("Teh Manis", map[string]int64{}, "teh-manis")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "teh-manis", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("update-back-to-an-old-title", func(t *testing.T) {
+		ar, mockArticleRepo, err := update("Kopi", map[string]int64{"kopi": stored.ID}, "kopi")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "kopi", ar.Slug, "a slug it had before is its own")
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("get-by-slug", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetBySlug", mock.Anything, "kopi").Return(stored, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(4)).Return(domain.Author{ID: 4, Name: "Iman"}, nil).Once()
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{stored.ID}).Return(map[int64][]domain.Category{}, nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		res, err := u.GetBySlug(context.TODO(), "kopi")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "teh", res.Slug)
+		assert.Equal(t, "Iman", res.Author.Name)
+	})
+
+	t.Run("fill", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("FetchWithoutSlug", mock.Anything, int64(100)).
+			Return([]domain.Article{{ID: 1, Title: "Makan Ayam"}, {ID: 2, Title: "Makan Ayam"}}, nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "makan-ayam").Return(map[string]int64{}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(1), "makan-ayam", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockArticleRepo.On("FetchSlugs", mock.Anything, "makan-ayam").Return(map[string]int64{"makan-ayam": 1}, nil).Once()
+		mockArticleRepo.On("SetSlug", mock.Anything, int64(2), "makan-ayam-2", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(mocks.AuthorRepository), new(mocks.CategoryRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), newSearcher(), newRevisionRepo(), time.Second*2)
+
+		n, err := u.FillSlugs(context.TODO())
 
-		err := u.Update(context.TODO(), &mockArticle)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
 		mockArticleRepo.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// loginRequest represent the request body to log in, Login being the username or the email
+type loginRequest struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// refreshRequest represent the request body carrying a refresh token
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// AuthHandler  represent the httphandler for authentication
+type AuthHandler struct {
+	AuthUcase domain.AuthUsecase
+}
+
+// NewAuthHandler will initialize the auth/ resources endpoint
+func NewAuthHandler(e *echo.Echo, us domain.AuthUsecase) {
+	handler := &AuthHandler{
+		AuthUcase: us,
+	}
+	e.POST("/auth/login", handler.Login)
+	e.POST("/auth/refresh", handler.Refresh)
+	e.POST("/auth/logout", handler.Logout)
+}
+
+// Login will issue a token pair for the given credentials
+func (a *AuthHandler) Login(c echo.Context) (err error) {
+	var req loginRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	tokens, err := a.AuthUcase.Login(ctx, req.Login, req.Password)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// Refresh will exchange the given refresh token for a new token pair
+func (a *AuthHandler) Refresh(c echo.Context) (err error) {
+	var req refreshRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	tokens, err := a.AuthUcase.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// Logout will revoke the given refresh token
+func (a *AuthHandler) Logout(c echo.Context) (err error) {
+	var req refreshRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = a.AuthUcase.Logout(ctx, req.RefreshToken)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case errHandle.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case errHandle.ErrNotFound:
+		return http.StatusNotFound
+	case errHandle.ErrConflict:
+		return http.StatusConflict
+	case errHandle.ErrBadParamInput:
+		return http.StatusBadRequest
+	case errHandle.ErrUnauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,134 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	authHttp "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/delivery/http"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestLogin(t *testing.T) {
+	body := `{"login":"iman","password":"supersecret"}`
+
+	t.Run("success", func(t *testing.T) {
+		tokens := domain.TokenPair{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900}
+		mockUCase := new(mocks.AuthUsecase)
+		mockUCase.On("Login", mock.Anything, "iman", "supersecret").Return(tokens, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/auth/login", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := authHttp.AuthHandler{
+			AuthUcase: mockUCase,
+		}
+		err = handler.Login(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var res domain.TokenPair
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, tokens, res)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("wrong-credentials", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+		mockUCase.On("Login", mock.Anything, "iman", "supersecret").Return(domain.TokenPair{}, errHandle.ErrUnauthorized).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/auth/login", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := authHttp.AuthHandler{
+			AuthUcase: mockUCase,
+		}
+		err = handler.Login(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	tokens := domain.TokenPair{AccessToken: "access", RefreshToken: "rotated", TokenType: "Bearer", ExpiresIn: 900}
+	mockUCase := new(mocks.AuthUsecase)
+	mockUCase.On("Refresh", mock.Anything, "refresh").Return(tokens, nil).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/auth/refresh", strings.NewReader(`{"refresh_token":"refresh"}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := authHttp.AuthHandler{
+		AuthUcase: mockUCase,
+	}
+	err = handler.Refresh(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestLogout(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+		mockUCase.On("Logout", mock.Anything, "refresh").Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/auth/logout", strings.NewReader(`{"refresh_token":"refresh"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := authHttp.AuthHandler{
+			AuthUcase: mockUCase,
+		}
+		err = handler.Logout(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-token", func(t *testing.T) {
+		mockUCase := new(mocks.AuthUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/auth/logout", strings.NewReader(`{}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := authHttp.AuthHandler{
+			AuthUcase: mockUCase,
+		}
+		err = handler.Logout(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type memoryRefreshTokenRepository struct {
+	DB *memdb.DB
+}
+
+// NewMemoryRefreshTokenRepository will create an object that represent the RefreshToken.Repository interface
+func NewMemoryRefreshTokenRepository(db *memdb.DB) domain.RefreshTokenRepository {
+	return &memoryRefreshTokenRepository{db}
+}
+
+func (m *memoryRefreshTokenRepository) GetByHash(ctx context.Context, hash string) (res domain.RefreshToken, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	for _, t := range m.DB.RefreshTokens {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+	return domain.RefreshToken{}, errHandle.ErrNotFound
+}
+
+func (m *memoryRefreshTokenRepository) Store(ctx context.Context, t *domain.RefreshToken) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	for _, existing := range m.DB.RefreshTokens {
+		if existing.TokenHash == t.TokenHash {
+			return errHandle.ErrConflict
+		}
+	}
+
+	t.ID = m.DB.NextID("refresh_token")
+	stored := *t
+	stored.RevokedAt = nil
+	m.DB.RefreshTokens[t.ID] = stored
+	return
+}
+
+// Revoke marks the token as used. It reports ErrNotFound when the token was
+// already revoked, so that only one of two concurrent rotations succeeds.
+func (m *memoryRefreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	t, ok := m.DB.RefreshTokens[id]
+	if !ok || t.RevokedAt != nil {
+		return errHandle.ErrNotFound
+	}
+	t.RevokedAt = &at
+	m.DB.RefreshTokens[id] = t
+	return
+}
+
+func (m *memoryRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	for id, t := range m.DB.RefreshTokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			revokedAt := at
+			t.RevokedAt = &revokedAt
+			m.DB.RefreshTokens[id] = t
+		}
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type mysqlRefreshTokenRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlRefreshTokenRepository will create an object that represent the RefreshToken.Repository interface
+func NewMysqlRefreshTokenRepository(Conn *sql.DB) domain.RefreshTokenRepository {
+	return &mysqlRefreshTokenRepository{Conn}
+}
+
+func (m *mysqlRefreshTokenRepository) GetByHash(ctx context.Context, hash string) (res domain.RefreshToken, err error) {
+	query := `SELECT id, user_id, token_hash, expires_at, revoked_at, created_at
+  						FROM refresh_token WHERE token_hash = ?`
+
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, hash).Scan(
+		&res.ID,
+		&res.UserID,
+		&res.TokenHash,
+		&res.ExpiresAt,
+		&res.RevokedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.RefreshToken{}, errHandle.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mysqlRefreshTokenRepository) Store(ctx context.Context, t *domain.RefreshToken) (err error) {
+	query := `INSERT refresh_token SET user_id=? , token_hash=? , expires_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, t.UserID, t.TokenHash, t.ExpiresAt, t.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	t.ID = lastID
+	return
+}
+
+// Revoke marks the token as used. It reports ErrNotFound when the token was
+// already revoked, so that only one of two concurrent rotations succeeds.
+func (m *mysqlRefreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) (err error) {
+	query := `UPDATE refresh_token SET revoked_at=? WHERE id = ? AND revoked_at IS NULL`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, at, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return
+}
+
+func (m *mysqlRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) (err error) {
+	query := `UPDATE refresh_token SET revoked_at=? WHERE user_id = ? AND revoked_at IS NULL`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, at, userID)
+	return
+}
@@ -0,0 +1,109 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	authMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/repository/mysql"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestGetByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, user_id, token_hash, expires_at, revoked_at, created_at FROM refresh_token WHERE token_hash = \\?"
+	columns := []string{"id", "user_id", "token_hash", "expires_at", "revoked_at", "created_at"}
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).AddRow(1, 2, "hash", time.Now().Add(time.Hour), nil, time.Now())
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+		r := authMysqlRepo.NewMysqlRefreshTokenRepository(db)
+
+		token, err := r.GetByHash(context.TODO(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), token.UserID)
+		assert.Nil(t, token.RevokedAt)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		revokedAt := time.Now()
+		rows := sqlmock.NewRows(columns).AddRow(1, 2, "hash", time.Now().Add(time.Hour), revokedAt, time.Now())
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+		r := authMysqlRepo.NewMysqlRefreshTokenRepository(db)
+
+		token, err := r.GetByHash(context.TODO(), "hash")
+		assert.NoError(t, err)
+		if assert.NotNil(t, token.RevokedAt) {
+			assert.True(t, revokedAt.Equal(*token.RevokedAt))
+		}
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs("unknown").WillReturnRows(sqlmock.NewRows(columns))
+		r := authMysqlRepo.NewMysqlRefreshTokenRepository(db)
+
+		_, err := r.GetByHash(context.TODO(), "unknown")
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	token := &domain.RefreshToken{
+		UserID:    2,
+		TokenHash: "hash",
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT refresh_token SET user_id=\\? , token_hash=\\? , expires_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	r := authMysqlRepo.NewMysqlRefreshTokenRepository(db)
+
+	err = r.Store(context.TODO(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), token.ID)
+}
+
+func TestRevoke(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE refresh_token SET revoked_at=\\? WHERE id = \\? AND revoked_at IS NULL"
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(now, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		r := authMysqlRepo.NewMysqlRefreshTokenRepository(db)
+
+		err := r.Revoke(context.TODO(), 1, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("already-revoked", func(t *testing.T) {
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(now, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+		r := authMysqlRepo.NewMysqlRefreshTokenRepository(db)
+
+		err := r.Revoke(context.TODO(), 1, now)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type postgresRefreshTokenRepository struct {
+	Conn *sql.DB
+}
+
+// NewPostgresRefreshTokenRepository will create an object that represent the RefreshToken.Repository interface
+func NewPostgresRefreshTokenRepository(Conn *sql.DB) domain.RefreshTokenRepository {
+	return &postgresRefreshTokenRepository{Conn}
+}
+
+func (m *postgresRefreshTokenRepository) GetByHash(ctx context.Context, hash string) (res domain.RefreshToken, err error) {
+	query := `SELECT id, user_id, token_hash, expires_at, revoked_at, created_at
+  						FROM refresh_token WHERE token_hash = $1`
+
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, hash).Scan(
+		&res.ID,
+		&res.UserID,
+		&res.TokenHash,
+		&res.ExpiresAt,
+		&res.RevokedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.RefreshToken{}, errHandle.ErrNotFound
+	}
+
+	return
+}
+
+func (m *postgresRefreshTokenRepository) Store(ctx context.Context, t *domain.RefreshToken) (err error) {
+	query := `INSERT INTO refresh_token (user_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	err = stmt.QueryRowContext(ctx, t.UserID, t.TokenHash, t.ExpiresAt, t.CreatedAt).Scan(&t.ID)
+	return
+}
+
+// Revoke marks the token as used. It reports ErrNotFound when the token was
+// already revoked, so that only one of two concurrent rotations succeeds.
+func (m *postgresRefreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) (err error) {
+	query := `UPDATE refresh_token SET revoked_at=$1 WHERE id = $2 AND revoked_at IS NULL`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, at, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return
+}
+
+func (m *postgresRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) (err error) {
+	query := `UPDATE refresh_token SET revoked_at=$1 WHERE user_id = $2 AND revoked_at IS NULL`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, at, userID)
+	return
+}
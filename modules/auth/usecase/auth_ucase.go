@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// TokenConfig holds the settings used to sign and verify access tokens.
+// SignKey and VerifyKey are the same []byte secret for HS256, and the
+// *rsa.PrivateKey / *rsa.PublicKey pair for RS256.
+type TokenConfig struct {
+	SigningMethod jwt.SigningMethod
+	SignKey       interface{}
+	VerifyKey     interface{}
+	AccessTTL     time.Duration
+	RefreshTTL    time.Duration
+}
+
+type authUsecase struct {
+	userRepo       domain.UserRepository
+	tokenRepo      domain.RefreshTokenRepository
+	config         TokenConfig
+	contextTimeout time.Duration
+}
+
+// NewAuthUsecase will create new an authUsecase object representation of domain.AuthUsecase interface
+func NewAuthUsecase(u domain.UserRepository, t domain.RefreshTokenRepository, config TokenConfig, timeout time.Duration) domain.AuthUsecase {
+	return &authUsecase{
+		userRepo:       u,
+		tokenRepo:      t,
+		config:         config,
+		contextTimeout: timeout,
+	}
+}
+
+// Login verifies the credentials, login being either the username or the
+// email of the user, and issues a new token pair.
+func (a *authUsecase) Login(c context.Context, login, password string) (res domain.TokenPair, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	var user domain.User
+	if strings.Contains(login, "@") {
+		user, err = a.userRepo.GetByEmail(ctx, login)
+	} else {
+		user, err = a.userRepo.GetByUsername(ctx, login)
+	}
+	if err == errHandle.ErrNotFound {
+		return domain.TokenPair{}, errHandle.ErrUnauthorized
+	}
+	if err != nil {
+		return
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return domain.TokenPair{}, errHandle.ErrUnauthorized
+	}
+
+	return a.issue(ctx, user)
+}
+
+// Refresh rotates the given refresh token: it is revoked and a new pair is
+// issued. Presenting an already rotated token is treated as theft and
+// revokes every refresh token of the user.
+func (a *authUsecase) Refresh(c context.Context, refreshToken string) (res domain.TokenPair, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	stored, err := a.tokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err == errHandle.ErrNotFound {
+		return domain.TokenPair{}, errHandle.ErrUnauthorized
+	}
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	if stored.RevokedAt != nil {
+		if err = a.tokenRepo.RevokeByUser(ctx, stored.UserID, now); err != nil {
+			return
+		}
+		return domain.TokenPair{}, errHandle.ErrUnauthorized
+	}
+	if now.After(stored.ExpiresAt) {
+		return domain.TokenPair{}, errHandle.ErrUnauthorized
+	}
+
+	err = a.tokenRepo.Revoke(ctx, stored.ID, now)
+	if err == errHandle.ErrNotFound {
+		return domain.TokenPair{}, errHandle.ErrUnauthorized
+	}
+	if err != nil {
+		return
+	}
+
+	user, err := a.userRepo.GetByID(ctx, stored.UserID)
+	if err == errHandle.ErrNotFound {
+		return domain.TokenPair{}, errHandle.ErrUnauthorized
+	}
+	if err != nil {
+		return
+	}
+
+	return a.issue(ctx, user)
+}
+
+// Logout revokes the given refresh token. Logging out twice is not an error.
+func (a *authUsecase) Logout(c context.Context, refreshToken string) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	stored, err := a.tokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err == errHandle.ErrNotFound {
+		return errHandle.ErrUnauthorized
+	}
+	if err != nil {
+		return
+	}
+	if stored.RevokedAt != nil {
+		return nil
+	}
+
+	err = a.tokenRepo.Revoke(ctx, stored.ID, time.Now())
+	if err == errHandle.ErrNotFound {
+		return nil
+	}
+	return
+}
+
+// Authenticate verifies the access token and returns the user it was issued to
+func (a *authUsecase) Authenticate(c context.Context, accessToken string) (res domain.User, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	claims := jwt.StandardClaims{}
+	_, err = jwt.ParseWithClaims(accessToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != a.config.SigningMethod.Alg() {
+			return nil, errHandle.ErrUnauthorized
+		}
+		return a.config.VerifyKey, nil
+	})
+	if err != nil {
+		return domain.User{}, errHandle.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return domain.User{}, errHandle.ErrUnauthorized
+	}
+
+	res, err = a.userRepo.GetByID(ctx, id)
+	if err == errHandle.ErrNotFound {
+		return domain.User{}, errHandle.ErrUnauthorized
+	}
+	return
+}
+
+func (a *authUsecase) issue(ctx context.Context, user domain.User) (res domain.TokenPair, err error) {
+	now := time.Now()
+	claims := jwt.StandardClaims{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(a.config.AccessTTL).Unix(),
+	}
+	accessToken, err := jwt.NewWithClaims(a.config.SigningMethod, claims).SignedString(a.config.SignKey)
+	if err != nil {
+		return
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return
+	}
+	stored := domain.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(a.config.RefreshTTL),
+		CreatedAt: now,
+	}
+	if err = a.tokenRepo.Store(ctx, &stored); err != nil {
+		return
+	}
+
+	res = domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(a.config.AccessTTL / time.Second),
+	}
+	return
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,246 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	ucase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/auth/usecase"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+var tokenConfig = ucase.TokenConfig{
+	SigningMethod: jwt.SigningMethodHS256,
+	SignKey:       []byte("secret"),
+	VerifyKey:     []byte("secret"),
+	AccessTTL:     time.Minute * 15,
+	RefreshTTL:    time.Hour * 24,
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func mockUser(t *testing.T) domain.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte("supersecret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	return domain.User{
+		ID:       1,
+		Username: "iman",
+		Email:    "iman@example.com",
+		Password: string(hash),
+	}
+}
+
+func TestLogin(t *testing.T) {
+	user := mockUser(t)
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockUserRepo.On("GetByUsername", mock.Anything, "iman").Return(user, nil).Once()
+		mockUserRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Once()
+		mockTokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(rt *domain.RefreshToken) bool {
+			return rt.UserID == user.ID && rt.ExpiresAt.After(time.Now())
+		})).Return(nil).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		tokens, err := u.Login(context.TODO(), "iman", "supersecret")
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.Equal(t, "Bearer", tokens.TokenType)
+		assert.Equal(t, int64(900), tokens.ExpiresIn)
+
+		authenticated, err := u.Authenticate(context.TODO(), tokens.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, authenticated.ID)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("by-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, "iman@example.com").Return(user, nil).Once()
+		mockTokenRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		_, err := u.Login(context.TODO(), "iman@example.com", "supersecret")
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("wrong-password", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockUserRepo.On("GetByUsername", mock.Anything, "iman").Return(user, nil).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		_, err := u.Login(context.TODO(), "iman", "wrongpassword")
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+		mockTokenRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown-user", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockUserRepo.On("GetByUsername", mock.Anything, "nobody").Return(domain.User{}, errHandle.ErrNotFound).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		_, err := u.Login(context.TODO(), "nobody", "supersecret")
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	user := mockUser(t)
+	stored := domain.RefreshToken{
+		ID:        5,
+		UserID:    user.ID,
+		TokenHash: hashToken("refresh-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockTokenRepo.On("GetByHash", mock.Anything, stored.TokenHash).Return(stored, nil).Once()
+		mockTokenRepo.On("Revoke", mock.Anything, stored.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockTokenRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+		mockUserRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		tokens, err := u.Refresh(context.TODO(), "refresh-token")
+		assert.NoError(t, err)
+		assert.NotEqual(t, "refresh-token", tokens.RefreshToken)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("reused-token", func(t *testing.T) {
+		revokedAt := time.Now().Add(-time.Minute)
+		revoked := stored
+		revoked.RevokedAt = &revokedAt
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockTokenRepo.On("GetByHash", mock.Anything, stored.TokenHash).Return(revoked, nil).Once()
+		mockTokenRepo.On("RevokeByUser", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		_, err := u.Refresh(context.TODO(), "refresh-token")
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := stored
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockTokenRepo.On("GetByHash", mock.Anything, stored.TokenHash).Return(expired, nil).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		_, err := u.Refresh(context.TODO(), "refresh-token")
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("concurrent-rotation", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockTokenRepo.On("GetByHash", mock.Anything, stored.TokenHash).Return(stored, nil).Once()
+		mockTokenRepo.On("Revoke", mock.Anything, stored.ID, mock.AnythingOfType("time.Time")).Return(errHandle.ErrNotFound).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, mockTokenRepo, tokenConfig, time.Second*2)
+
+		_, err := u.Refresh(context.TODO(), "refresh-token")
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+		mockTokenRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	stored := domain.RefreshToken{
+		ID:        5,
+		UserID:    1,
+		TokenHash: hashToken("refresh-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockTokenRepo.On("GetByHash", mock.Anything, stored.TokenHash).Return(stored, nil).Once()
+		mockTokenRepo.On("Revoke", mock.Anything, stored.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		u := ucase.NewAuthUsecase(new(mocks.UserRepository), mockTokenRepo, tokenConfig, time.Second*2)
+
+		err := u.Logout(context.TODO(), "refresh-token")
+		assert.NoError(t, err)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-token", func(t *testing.T) {
+		mockTokenRepo := new(mocks.RefreshTokenRepository)
+		mockTokenRepo.On("GetByHash", mock.Anything, hashToken("unknown")).Return(domain.RefreshToken{}, errHandle.ErrNotFound).Once()
+		u := ucase.NewAuthUsecase(new(mocks.UserRepository), mockTokenRepo, tokenConfig, time.Second*2)
+
+		err := u.Logout(context.TODO(), "unknown")
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	user := mockUser(t)
+
+	sign := func(method jwt.SigningMethod, key interface{}, expiresAt time.Time) string {
+		token, err := jwt.NewWithClaims(method, jwt.StandardClaims{
+			Subject:   "1",
+			ExpiresAt: expiresAt.Unix(),
+		}).SignedString(key)
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Once()
+		u := ucase.NewAuthUsecase(mockUserRepo, new(mocks.RefreshTokenRepository), tokenConfig, time.Second*2)
+
+		res, err := u.Authenticate(context.TODO(), sign(jwt.SigningMethodHS256, []byte("secret"), time.Now().Add(time.Minute)))
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, res.ID)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		u := ucase.NewAuthUsecase(new(mocks.UserRepository), new(mocks.RefreshTokenRepository), tokenConfig, time.Second*2)
+
+		_, err := u.Authenticate(context.TODO(), sign(jwt.SigningMethodHS256, []byte("secret"), time.Now().Add(-time.Minute)))
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+	})
+
+	t.Run("wrong-key", func(t *testing.T) {
+		u := ucase.NewAuthUsecase(new(mocks.UserRepository), new(mocks.RefreshTokenRepository), tokenConfig, time.Second*2)
+
+		_, err := u.Authenticate(context.TODO(), sign(jwt.SigningMethodHS256, []byte("another"), time.Now().Add(time.Minute)))
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+	})
+
+	t.Run("wrong-algorithm", func(t *testing.T) {
+		u := ucase.NewAuthUsecase(new(mocks.UserRepository), new(mocks.RefreshTokenRepository), tokenConfig, time.Second*2)
+
+		_, err := u.Authenticate(context.TODO(), sign(jwt.SigningMethodHS512, []byte("secret"), time.Now().Add(time.Minute)))
+		assert.Equal(t, errHandle.ErrUnauthorized, err)
+	})
+}
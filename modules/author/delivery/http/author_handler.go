@@ -0,0 +1,162 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// AuthorHandler  represent the httphandler for author
+type AuthorHandler struct {
+	AUsecase domain.AuthorUsecase
+}
+
+// NewAuthorHandler will initialize the authors/ resources endpoint
+func NewAuthorHandler(e *echo.Echo, us domain.AuthorUsecase) {
+	handler := &AuthorHandler{
+		AUsecase: us,
+	}
+	e.GET("/authors", handler.FetchAuthor)
+	e.POST("/authors", handler.Store)
+	e.GET("/authors/:id", handler.GetByID)
+	e.PUT("/authors/:id", handler.Update)
+	e.DELETE("/authors/:id", handler.Delete)
+}
+
+// FetchAuthor will fetch the author based on given params
+func (a *AuthorHandler) FetchAuthor(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	page := domain.Page{
+		Cursor: c.QueryParam("cursor"),
+		Num:    int64(num),
+		Sort:   domain.SortDirection(c.QueryParam("sort")),
+	}
+	ctx := c.Request().Context()
+
+	listAuthor, cursors, err := a.AUsecase.Fetch(ctx, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(`X-Cursor`, cursors.Next)
+	c.Response().Header().Set(`X-Prev-Cursor`, cursors.Prev)
+	return c.JSON(http.StatusOK, listAuthor)
+}
+
+// GetByID will get author by given id
+func (a *AuthorHandler) GetByID(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+
+	author, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, author)
+}
+
+// Store will store the author by given request body
+func (a *AuthorHandler) Store(c echo.Context) (err error) {
+	var author domain.Author
+	err = c.Bind(&author)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&author); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = a.AUsecase.Store(ctx, &author)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, author)
+}
+
+// Update will update the author by given param and request body
+func (a *AuthorHandler) Update(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	var author domain.Author
+	err = c.Bind(&author)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	author.ID = int64(idP)
+
+	if err = validation.Struct(&author); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = a.AUsecase.Update(ctx, &author)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, author)
+}
+
+// Delete will delete author by given param
+func (a *AuthorHandler) Delete(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+
+	err = a.AUsecase.Delete(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case errHandle.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case errHandle.ErrNotFound:
+		return http.StatusNotFound
+	case errHandle.ErrConflict:
+		return http.StatusConflict
+	case errHandle.ErrBadParamInput:
+		return http.StatusBadRequest
+	case errHandle.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case errHandle.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
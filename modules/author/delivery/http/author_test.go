@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/faker"
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	authorHttp "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/delivery/http"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestFetch(t *testing.T) {
+	var mockAuthor domain.Author
+	err := faker.FakeData(&mockAuthor)
+	assert.NoError(t, err)
+	mockUCase := new(mocks.AuthorUsecase)
+	mockListAuthor := []domain.Author{mockAuthor}
+	mockUCase.On("Fetch", mock.Anything, domain.Page{Cursor: "2", Num: 1}).Return(mockListAuthor, domain.Cursors{Next: "10"}, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/authors?num=1&cursor=2", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := authorHttp.AuthorHandler{
+		AUsecase: mockUCase,
+	}
+	err = handler.FetchAuthor(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, "10", rec.Header().Get("X-Cursor"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		j, err := json.Marshal(domain.Author{Name: "Iman Tumorang"})
+		assert.NoError(t, err)
+		mockUCase := new(mocks.AuthorUsecase)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Author")).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/authors", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := authorHttp.AuthorHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Store(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-name", func(t *testing.T) {
+		mockUCase := new(mocks.AuthorUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/authors", strings.NewReader(`{}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := authorHttp.AuthorHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Store(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.AuthorUsecase)
+	mockUCase.On("Delete", mock.Anything, int64(1)).Return(errHandle.ErrConflict)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.DELETE, "/authors/1", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("authors/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	handler := authorHttp.AuthorHandler{
+		AUsecase: mockUCase,
+	}
+	err = handler.Delete(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDeleteDenied(t *testing.T) {
+	for denial, code := range map[error]int{errHandle.ErrUnauthorized: http.StatusUnauthorized, errHandle.ErrForbidden: http.StatusForbidden} {
+		mockUCase := new(mocks.AuthorUsecase)
+		mockUCase.On("Delete", mock.Anything, int64(1)).Return(denial).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/authors/1", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("authors/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		handler := authorHttp.AuthorHandler{
+			AUsecase: mockUCase,
+		}
+		err = handler.Delete(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, code, rec.Code, denial.Error())
+		mockUCase.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type memoryAuthorRepo struct {
+	DB *memdb.DB
+}
+
+// NewMemoryAuthorRepository will create an implementation of author.Repository
+func NewMemoryAuthorRepository(db *memdb.DB) domain.AuthorRepository {
+	return &memoryAuthorRepo{
+		DB: db,
+	}
+}
+
+func (m *memoryAuthorRepo) Fetch(ctx context.Context, page domain.Page) (res []domain.Author, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	ids := make([]int64, 0, len(m.DB.Authors))
+	for id := range m.DB.Authors {
+		ids = append(ids, id)
+	}
+
+	ids = memdb.Page(ids, func(id int64) pagination.Key {
+		return pagination.Key{CreatedAt: m.DB.Authors[id].CreatedAt, ID: id}
+	}, keyset)
+	res = make([]domain.Author, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, m.DB.Authors[id])
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *memoryAuthorRepo) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res, ok := m.DB.Authors[id]
+	if !ok {
+		return domain.Author{}, errHandle.ErrNotFound
+	}
+	return res, nil
+}
+
+// Lock only checks the author exists, the transactions of the memory database
+// run one at a time
+func (m *memoryAuthorRepo) Lock(ctx context.Context, id int64) error {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	if _, ok := m.DB.Authors[id]; !ok {
+		return errHandle.ErrNotFound
+	}
+	return nil
+}
+
+func (m *memoryAuthorRepo) GetByIDs(ctx context.Context, ids []int64) (res map[int64]domain.Author, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res = map[int64]domain.Author{}
+	for _, id := range ids {
+		if author, ok := m.DB.Authors[id]; ok {
+			res[id] = author
+		}
+	}
+	return
+}
+
+func (m *memoryAuthorRepo) Store(ctx context.Context, a *domain.Author) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	a.ID = m.DB.NextID("author")
+	m.DB.Authors[a.ID] = *a
+	return
+}
+
+func (m *memoryAuthorRepo) Update(ctx context.Context, a *domain.Author) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	existing, ok := m.DB.Authors[a.ID]
+	if !ok {
+		return errHandle.ErrNotFound
+	}
+	existing.Name = a.Name
+	existing.UpdatedAt = a.UpdatedAt
+	m.DB.Authors[a.ID] = existing
+	return
+}
+
+func (m *memoryAuthorRepo) Delete(ctx context.Context, id int64) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	if _, ok := m.DB.Authors[id]; !ok {
+		return errHandle.ErrNotFound
+	}
+	delete(m.DB.Authors, id)
+	return
+}
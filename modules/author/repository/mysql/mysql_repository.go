@@ -3,8 +3,15 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 type mysqlAuthorRepo struct {
@@ -19,7 +26,7 @@ func NewMysqlAuthorRepository(db *sql.DB) domain.AuthorRepository {
 }
 
 func (m *mysqlAuthorRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Author, err error) {
-	stmt, err := m.DB.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
 	if err != nil {
 		return domain.Author{}, err
 	}
@@ -32,10 +39,165 @@ func (m *mysqlAuthorRepo) getOne(ctx context.Context, query string, args ...inte
 		&res.CreatedAt,
 		&res.UpdatedAt,
 	)
+	if err == sql.ErrNoRows {
+		return domain.Author{}, errHandle.ErrNotFound
+	}
 	return
 }
 
+func (m *mysqlAuthorRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Author, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Author, 0)
+	for rows.Next() {
+		author := domain.Author{}
+		err = rows.Scan(
+			&author.ID,
+			&author.Name,
+			&author.CreatedAt,
+			&author.UpdatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, author)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlAuthorRepo) Fetch(ctx context.Context, page domain.Page) (res []domain.Author, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id, name, created_at, updated_at
+  						FROM author WHERE (created_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, keyset.Args()...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
 func (m *mysqlAuthorRepo) GetByID(ctx context.Context, id int64) (domain.Author, error) {
 	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=?`
 	return m.getOne(ctx, query, id)
 }
+
+func (m *mysqlAuthorRepo) Lock(ctx context.Context, id int64) error {
+	var locked int64
+	err := transaction.Conn(ctx, m.DB).QueryRowContext(ctx, `SELECT id FROM author WHERE id = ? FOR UPDATE`, id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return errHandle.ErrNotFound
+	}
+	return err
+}
+
+func (m *mysqlAuthorRepo) GetByIDs(ctx context.Context, ids []int64) (res map[int64]domain.Author, err error) {
+	res = map[int64]domain.Author{}
+	if len(ids) == 0 {
+		return
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id IN (?` + strings.Repeat(",?", len(ids)-1) + `)`
+
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, author := range list {
+		res[author.ID] = author
+	}
+	return
+}
+
+func (m *mysqlAuthorRepo) Store(ctx context.Context, a *domain.Author) (err error) {
+	query := `INSERT author SET name=? , created_at=? , updated_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Name, a.CreatedAt, a.UpdatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	return
+}
+
+func (m *mysqlAuthorRepo) Update(ctx context.Context, a *domain.Author) (err error) {
+	query := `UPDATE author set name=?, updated_at=? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Name, a.UpdatedAt, a.ID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (m *mysqlAuthorRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM author WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return nil
+}
@@ -8,9 +8,31 @@ import (
 	"github.com/stretchr/testify/assert"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
 	repository "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/mysql"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+		AddRow(1, "Iman Tumorang", time.Now(), time.Now()).
+		AddRow(2, "Rachadian Novansyah", time.Now(), time.Now())
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE \\(created_at, id\\) > \\(\\?, \\?\\) ORDER BY created_at ASC, id ASC LIMIT \\?"
+
+	mock.ExpectQuery(query).WillReturnRows(rows)
+	a := repository.NewMysqlAuthorRepository(db)
+	list, cursors, err := a.Fetch(context.TODO(), domain.Page{Num: 2})
+	assert.Empty(t, cursors.Next)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
 func TestGetByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -32,3 +54,123 @@ func TestGetByID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, anArticle)
 }
+
+func TestGetByIDNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "updated_at", "created_at"})
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE id=\\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(7)).WillReturnRows(rows)
+
+	a := repository.NewMysqlAuthorRepository(db)
+
+	_, err = a.GetByID(context.TODO(), int64(7))
+	assert.Equal(t, errHandle.ErrNotFound, err)
+}
+
+func TestLock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id FROM author WHERE id = \\? FOR UPDATE"
+	mock.ExpectQuery(query).WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	a := repository.NewMysqlAuthorRepository(db)
+
+	assert.NoError(t, a.Lock(context.TODO(), int64(1)))
+	assert.Equal(t, errHandle.ErrNotFound, a.Lock(context.TODO(), int64(7)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	au := &domain.Author{
+		Name:      "Iman Tumorang",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT author SET name=\\? , created_at=\\? , updated_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(au.Name, au.CreatedAt, au.UpdatedAt).WillReturnResult(sqlmock.NewResult(2, 1))
+
+	a := repository.NewMysqlAuthorRepository(db)
+
+	err = a.Store(context.TODO(), au)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), au.ID)
+}
+
+func TestUpdate(t *testing.T) {
+	au := &domain.Author{
+		ID:        2,
+		Name:      "Iman Tumorang",
+		UpdatedAt: time.Now(),
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE author set name=\\?, updated_at=\\? WHERE id = \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(au.Name, au.UpdatedAt, au.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := repository.NewMysqlAuthorRepository(db)
+
+	err = a.Update(context.TODO(), au)
+	assert.Equal(t, errHandle.ErrNotFound, err)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "DELETE FROM author WHERE id = \\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(2, 1))
+
+	a := repository.NewMysqlAuthorRepository(db)
+
+	err = a.Delete(context.TODO(), int64(2))
+	assert.NoError(t, err)
+}
+
+func TestGetByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+		AddRow(1, "Iman Tumorang", time.Now(), time.Now()).
+		AddRow(3, "Rachadian Novansyah", time.Now(), time.Now())
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE id IN \\(\\?,\\?,\\?\\)"
+
+	mock.ExpectQuery(query).WithArgs(1, 2, 3).WillReturnRows(rows)
+	a := repository.NewMysqlAuthorRepository(db)
+
+	res, err := a.GetByIDs(context.TODO(), []int64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "Iman Tumorang", res[1].Name)
+	_, ok := res[2]
+	assert.False(t, ok)
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type postgresAuthorRepo struct {
+	DB *sql.DB
+}
+
+// NewPostgresAuthorRepository will create an implementation of author.Repository
+func NewPostgresAuthorRepository(db *sql.DB) domain.AuthorRepository {
+	return &postgresAuthorRepo{
+		DB: db,
+	}
+}
+
+func (m *postgresAuthorRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Author, err error) {
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	row := stmt.QueryRowContext(ctx, args...)
+	res = domain.Author{}
+
+	err = row.Scan(
+		&res.ID,
+		&res.Name,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.Author{}, errHandle.ErrNotFound
+	}
+	return
+}
+
+func (m *postgresAuthorRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Author, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Author, 0)
+	for rows.Next() {
+		author := domain.Author{}
+		err = rows.Scan(
+			&author.ID,
+			&author.Name,
+			&author.CreatedAt,
+			&author.UpdatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, author)
+	}
+
+	return result, nil
+}
+
+func (m *postgresAuthorRepo) Fetch(ctx context.Context, page domain.Page) (res []domain.Author, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id, name, created_at, updated_at
+  						FROM author WHERE (created_at, id) ` + keyset.Op() + ` ($1, $2) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT $3 `
+
+	res, err = m.fetch(ctx, query, keyset.Args()...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *postgresAuthorRepo) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=$1`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *postgresAuthorRepo) Lock(ctx context.Context, id int64) error {
+	var locked int64
+	err := transaction.Conn(ctx, m.DB).QueryRowContext(ctx, `SELECT id FROM author WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return errHandle.ErrNotFound
+	}
+	return err
+}
+
+func (m *postgresAuthorRepo) GetByIDs(ctx context.Context, ids []int64) (res map[int64]domain.Author, err error) {
+	res = map[int64]domain.Author{}
+	if len(ids) == 0 {
+		return
+	}
+
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id = ANY($1)`
+
+	list, err := m.fetch(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, author := range list {
+		res[author.ID] = author
+	}
+	return
+}
+
+func (m *postgresAuthorRepo) Store(ctx context.Context, a *domain.Author) (err error) {
+	query := `INSERT INTO author (name, created_at, updated_at) VALUES ($1, $2, $3) RETURNING id`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	err = stmt.QueryRowContext(ctx, a.Name, a.CreatedAt, a.UpdatedAt).Scan(&a.ID)
+	return
+}
+
+func (m *postgresAuthorRepo) Update(ctx context.Context, a *domain.Author) (err error) {
+	query := `UPDATE author set name=$1, updated_at=$2 WHERE id = $3`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Name, a.UpdatedAt, a.ID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (m *postgresAuthorRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM author WHERE id = $1"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	authorPostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/postgres"
+)
+
+func TestGetByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+		AddRow(1, "Iman Tumorang", time.Now(), time.Now()).
+		AddRow(3, "Rachadian", time.Now(), time.Now())
+
+	query := "SELECT id, name, created_at, updated_at FROM author WHERE id = ANY\\(\\$1\\)"
+	mock.ExpectQuery(query).WithArgs(pq.Array([]int64{1, 3})).WillReturnRows(rows)
+
+	a := authorPostgresRepo.NewPostgresAuthorRepository(db)
+	res, err := a.GetByIDs(context.TODO(), []int64{1, 3})
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "Rachadian", res[3].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	ar := &domain.Author{
+		Name:      "Iman Tumorang",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT INTO author \\(name, created_at, updated_at\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(ar.Name, ar.CreatedAt, ar.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	a := authorPostgresRepo.NewPostgresAuthorRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), ar.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
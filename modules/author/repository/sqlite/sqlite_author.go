@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type sqliteAuthorRepo struct {
+	DB *sql.DB
+}
+
+// NewSqliteAuthorRepository will create an implementation of author.Repository
+func NewSqliteAuthorRepository(db *sql.DB) domain.AuthorRepository {
+	return &sqliteAuthorRepo{
+		DB: db,
+	}
+}
+
+func (m *sqliteAuthorRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Author, err error) {
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	row := stmt.QueryRowContext(ctx, args...)
+	res = domain.Author{}
+
+	err = row.Scan(
+		&res.ID,
+		&res.Name,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.Author{}, errHandle.ErrNotFound
+	}
+	return
+}
+
+func (m *sqliteAuthorRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Author, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Author, 0)
+	for rows.Next() {
+		author := domain.Author{}
+		err = rows.Scan(
+			&author.ID,
+			&author.Name,
+			&author.CreatedAt,
+			&author.UpdatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, author)
+	}
+
+	return result, nil
+}
+
+func (m *sqliteAuthorRepo) Fetch(ctx context.Context, page domain.Page) (res []domain.Author, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id, name, created_at, updated_at
+  						FROM author WHERE (created_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, keyset.Args()...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *sqliteAuthorRepo) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=?`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *sqliteAuthorRepo) Lock(ctx context.Context, id int64) error {
+	// SQLite has a single writer and the transactions share one connection,
+	// none can come in between the existence check and the end of this one
+	var locked int64
+	err := transaction.Conn(ctx, m.DB).QueryRowContext(ctx, `SELECT id FROM author WHERE id = ?`, id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return errHandle.ErrNotFound
+	}
+	return err
+}
+
+func (m *sqliteAuthorRepo) GetByIDs(ctx context.Context, ids []int64) (res map[int64]domain.Author, err error) {
+	res = map[int64]domain.Author{}
+	if len(ids) == 0 {
+		return
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id IN (?` + strings.Repeat(",?", len(ids)-1) + `)`
+
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, author := range list {
+		res[author.ID] = author
+	}
+	return
+}
+
+func (m *sqliteAuthorRepo) Store(ctx context.Context, a *domain.Author) (err error) {
+	query := `INSERT INTO author (name, created_at, updated_at) VALUES (?, ?, ?)`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Name, a.CreatedAt.UTC(), a.UpdatedAt.UTC())
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	return
+}
+
+func (m *sqliteAuthorRepo) Update(ctx context.Context, a *domain.Author) (err error) {
+	query := `UPDATE author set name=?, updated_at=? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Name, a.UpdatedAt.UTC(), a.ID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (m *sqliteAuthorRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM author WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return nil
+}
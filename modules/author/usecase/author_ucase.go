@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type authorUsecase struct {
+	authorRepo     domain.AuthorRepository
+	articleRepo    domain.ArticleRepository
+	userRepo       domain.UserRepository
+	policy         domain.Policy
+	transactor     domain.Transactor
+	sealer         domain.CursorSealer
+	contextTimeout time.Duration
+}
+
+// NewAuthorUsecase will create new an authorUsecase object representation of domain.AuthorUsecase interface
+func NewAuthorUsecase(a domain.AuthorRepository, ar domain.ArticleRepository, ur domain.UserRepository, p domain.Policy, t domain.Transactor, s domain.CursorSealer, timeout time.Duration) domain.AuthorUsecase {
+	return &authorUsecase{
+		authorRepo:     a,
+		articleRepo:    ar,
+		userRepo:       ur,
+		policy:         p,
+		transactor:     t,
+		sealer:         s,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *authorUsecase) Fetch(c context.Context, page domain.Page) (res []domain.Author, cursors domain.Cursors, err error) {
+	if page.Num == 0 {
+		page.Num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	opened, err := u.sealer.Open(page, "authors", nil)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, cursors, err = u.authorRepo.Fetch(ctx, opened)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	return res, u.sealer.Seal(page, "authors", nil, cursors), nil
+}
+
+func (u *authorUsecase) GetByID(c context.Context, id int64) (res domain.Author, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.authorRepo.GetByID(ctx, id)
+}
+
+func (u *authorUsecase) Store(c context.Context, m *domain.Author) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	if err = u.policy.CanManageAuthors(ctx); err != nil {
+		return
+	}
+
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	return u.authorRepo.Store(ctx, m)
+}
+
+func (u *authorUsecase) Update(c context.Context, m *domain.Author) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	if err = u.policy.CanManageAuthors(ctx); err != nil {
+		return
+	}
+
+	m.UpdatedAt = time.Now()
+	return u.authorRepo.Update(ctx, m)
+}
+
+// Delete removes an author that is not the profile of a user and has no
+// articles, not even in the trash where one could be restored from. The author
+// is locked for the checks and the delete, as it is when an article is given
+// to it, so that an article stored meanwhile can't be left without its author.
+func (u *authorUsecase) Delete(c context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	if err = u.policy.CanManageAuthors(ctx); err != nil {
+		return
+	}
+
+	return u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.authorRepo.Lock(ctx, id); err != nil {
+			return err
+		}
+		owned, err := u.articleRepo.CountByAuthor(ctx, id)
+		if err != nil {
+			return err
+		}
+		if owned > 0 {
+			return errHandle.ErrConflict
+		}
+		_, err = u.userRepo.GetByAuthorID(ctx, id)
+		if err == nil {
+			return errHandle.ErrConflict
+		}
+		if err != errHandle.ErrNotFound {
+			return err
+		}
+
+		return u.authorRepo.Delete(ctx, id)
+	})
+}
@@ -0,0 +1,158 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/internal/testutil"
+	ucase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/usecase"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/policy"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// newPolicy returns a Policy allowing the caller to manage authors
+func newPolicy() *mocks.Policy {
+	policy := new(mocks.Policy)
+	policy.On("CanManageAuthors", mock.Anything).Return(nil)
+	return policy
+}
+
+func TestFetch(t *testing.T) {
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockListAuthor := []domain.Author{
+		{ID: 1, Name: "Iman Tumorang"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthorRepo.On("Fetch", mock.Anything, domain.Page{Cursor: "12", Num: 10}).Return(mockListAuthor, domain.Cursors{Next: "next-cursor"}, nil).Once()
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, new(mocks.UserRepository), newPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		list, cursors, err := u.Fetch(context.TODO(), domain.Page{Cursor: "12"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", cursors.Next)
+		assert.Len(t, list, 1)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+	t.Run("error-failed", func(t *testing.T) {
+		mockAuthorRepo.On("Fetch", mock.Anything, domain.Page{Cursor: "12", Num: 1}).Return(nil, domain.Cursors{}, errors.New("Unexpected Error")).Once()
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, new(mocks.UserRepository), newPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		list, cursors, err := u.Fetch(context.TODO(), domain.Page{Cursor: "12", Num: 1})
+
+		assert.Error(t, err)
+		assert.Empty(t, cursors)
+		assert.Len(t, list, 0)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+}
+
+func TestStore(t *testing.T) {
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockAuthor := domain.Author{
+		Name: "Iman Tumorang",
+	}
+
+	mockAuthorRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Author")).Return(nil).Once()
+	mockArticleRepo := new(mocks.ArticleRepository)
+	u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, new(mocks.UserRepository), newPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+	err := u.Store(context.TODO(), &mockAuthor)
+
+	assert.NoError(t, err)
+	assert.False(t, mockAuthor.CreatedAt.IsZero())
+	assert.Equal(t, mockAuthor.CreatedAt, mockAuthor.UpdatedAt)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockAuthorRepo := new(mocks.AuthorRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorRepo.On("Lock", mock.Anything, int64(1)).Return(nil).Once()
+		mockArticleRepo.On("CountByAuthor", mock.Anything, int64(1)).Return(int64(0), nil).Once()
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByAuthorID", mock.Anything, int64(1)).Return(domain.User{}, errHandle.ErrNotFound).Once()
+		mockAuthorRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, mockUserRepo, newPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		err := u.Delete(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+	t.Run("still-owns-articles", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorRepo.On("Lock", mock.Anything, int64(1)).Return(nil).Once()
+		mockArticleRepo.On("CountByAuthor", mock.Anything, int64(1)).Return(int64(2), nil).Once()
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, new(mocks.UserRepository), newPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		err := u.Delete(context.TODO(), 1)
+
+		assert.Equal(t, errHandle.ErrConflict, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+	t.Run("profile-of-a-user", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorRepo.On("Lock", mock.Anything, int64(1)).Return(nil).Once()
+		mockArticleRepo.On("CountByAuthor", mock.Anything, int64(1)).Return(int64(0), nil).Once()
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByAuthorID", mock.Anything, int64(1)).Return(domain.User{ID: 3, AuthorID: 1}, nil).Once()
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, mockUserRepo, newPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		err := u.Delete(context.TODO(), 1)
+
+		assert.Equal(t, errHandle.ErrConflict, err)
+		mockUserRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorRepo.On("Lock", mock.Anything, int64(9)).Return(errHandle.ErrNotFound).Once()
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, new(mocks.UserRepository), newPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		err := u.Delete(context.TODO(), 9)
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		mockArticleRepo.AssertNotCalled(t, "CountByAuthor", mock.Anything, mock.Anything)
+		mockAuthorRepo.AssertExpectations(t)
+	})
+}
+
+func TestManageForbidden(t *testing.T) {
+	for name, ctx := range map[string]context.Context{
+		"anonymous": context.TODO(),
+		"author":    domain.NewContextWithUser(context.TODO(), domain.User{ID: 2, AuthorID: 1, Roles: []domain.Role{domain.RoleAuthor}}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			want := errHandle.ErrForbidden
+			if name == "anonymous" {
+				want = errHandle.ErrUnauthorized
+			}
+			mockAuthorRepo := new(mocks.AuthorRepository)
+			mockArticleRepo := new(mocks.ArticleRepository)
+			u := ucase.NewAuthorUsecase(mockAuthorRepo, mockArticleRepo, new(mocks.UserRepository), policy.NewRBACPolicy(), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+			assert.Equal(t, want, u.Store(ctx, &domain.Author{Name: "Iman Tumorang"}))
+			assert.Equal(t, want, u.Update(ctx, &domain.Author{ID: 1, Name: "Iman Tumorang"}))
+			assert.Equal(t, want, u.Delete(ctx, 1))
+			mockAuthorRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+			mockAuthorRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockAuthorRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// CategoryHandler  represent the httphandler for category
+type CategoryHandler struct {
+	CUsecase domain.CategoryUsecase
+}
+
+// assignRequest represent the request body for assigning a category to an article
+type assignRequest struct {
+	CategoryID int64 `json:"category_id" validate:"required"`
+}
+
+// NewCategoryHandler will initialize the categories/ resources endpoint
+func NewCategoryHandler(e *echo.Echo, us domain.CategoryUsecase) {
+	handler := &CategoryHandler{
+		CUsecase: us,
+	}
+	e.GET("/categories", handler.FetchCategory)
+	e.POST("/categories", handler.Store)
+	e.GET("/categories/:id", handler.GetByID)
+	e.PUT("/categories/:id", handler.Update)
+	e.DELETE("/categories/:id", handler.Delete)
+	e.POST("/articles/:id/categories", handler.AssignArticle)
+	e.DELETE("/articles/:id/categories/:category_id", handler.UnassignArticle)
+}
+
+// FetchCategory will fetch all categories
+func (h *CategoryHandler) FetchCategory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	list, err := h.CUsecase.Fetch(ctx)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetByID will get category by given id
+func (h *CategoryHandler) GetByID(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+
+	category, err := h.CUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+// Store will store the category by given request body
+func (h *CategoryHandler) Store(c echo.Context) (err error) {
+	var category domain.Category
+	err = c.Bind(&category)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&category); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = h.CUsecase.Store(ctx, &category)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, category)
+}
+
+// Update will update the category by given param and request body
+func (h *CategoryHandler) Update(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	var category domain.Category
+	err = c.Bind(&category)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	category.ID = int64(idP)
+
+	if err = validation.Struct(&category); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = h.CUsecase.Update(ctx, &category)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+// Delete will delete category by given param
+func (h *CategoryHandler) Delete(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+
+	err = h.CUsecase.Delete(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AssignArticle will put the article into the category given in the request body
+func (h *CategoryHandler) AssignArticle(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	var req assignRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = h.CUsecase.AssignArticle(ctx, int64(idP), req.CategoryID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UnassignArticle will take the article out of the given category
+func (h *CategoryHandler) UnassignArticle(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+	categoryID, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+
+	err = h.CUsecase.UnassignArticle(ctx, int64(idP), int64(categoryID))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case errHandle.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case errHandle.ErrNotFound:
+		return http.StatusNotFound
+	case errHandle.ErrConflict:
+		return http.StatusConflict
+	case errHandle.ErrBadParamInput:
+		return http.StatusBadRequest
+	case errHandle.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case errHandle.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
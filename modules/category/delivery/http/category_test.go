@@ -0,0 +1,165 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	categoryHttp "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/delivery/http"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.CategoryUsecase)
+	mockListCategory := []domain.Category{
+		{ID: 1, Name: "Makanan", Tag: "food"},
+	}
+	mockUCase.On("Fetch", mock.Anything).Return(mockListCategory, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/categories", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := categoryHttp.CategoryHandler{
+		CUsecase: mockUCase,
+	}
+	err = handler.FetchCategory(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	mockCategory := domain.Category{
+		Name: "Olahraga",
+		Tag:  "sport",
+	}
+	j, err := json.Marshal(mockCategory)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.CategoryUsecase)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Category")).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/categories", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUCase,
+		}
+		err = handler.Store(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("existing-tag", func(t *testing.T) {
+		mockUCase := new(mocks.CategoryUsecase)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Category")).Return(errHandle.ErrConflict).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/categories", strings.NewReader(string(j)))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUCase,
+		}
+		err = handler.Store(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestAssignArticle(t *testing.T) {
+	mockUCase := new(mocks.CategoryUsecase)
+	mockUCase.On("AssignArticle", mock.Anything, int64(1), int64(3)).Return(nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/articles/1/categories", strings.NewReader(`{"category_id":3}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("articles/:id/categories")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	handler := categoryHttp.CategoryHandler{
+		CUsecase: mockUCase,
+	}
+	err = handler.AssignArticle(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUnassignArticle(t *testing.T) {
+	mockUCase := new(mocks.CategoryUsecase)
+	mockUCase.On("UnassignArticle", mock.Anything, int64(1), int64(3)).Return(errHandle.ErrNotFound)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.DELETE, "/articles/1/categories/3", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("articles/:id/categories/:category_id")
+	c.SetParamNames("id", "category_id")
+	c.SetParamValues("1", "3")
+	handler := categoryHttp.CategoryHandler{
+		CUsecase: mockUCase,
+	}
+	err = handler.UnassignArticle(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDeleteDenied(t *testing.T) {
+	for denial, code := range map[error]int{errHandle.ErrUnauthorized: http.StatusUnauthorized, errHandle.ErrForbidden: http.StatusForbidden} {
+		mockUCase := new(mocks.CategoryUsecase)
+		mockUCase.On("Delete", mock.Anything, int64(4)).Return(denial).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/categories/4", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("categories/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("4")
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUCase,
+		}
+		err = handler.Delete(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, code, rec.Code, denial.Error())
+		mockUCase.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type memoryCategoryRepository struct {
+	DB *memdb.DB
+}
+
+// NewMemoryCategoryRepository will create an object that represent the category.Repository interface
+func NewMemoryCategoryRepository(db *memdb.DB) domain.CategoryRepository {
+	return &memoryCategoryRepository{db}
+}
+
+// sortByName orders the categories like ORDER BY name
+func sortByName(list []domain.Category) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name == list[j].Name {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].Name < list[j].Name
+	})
+}
+
+func (m *memoryCategoryRepository) Fetch(ctx context.Context) (res []domain.Category, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res = make([]domain.Category, 0, len(m.DB.Categories))
+	for _, c := range m.DB.Categories {
+		res = append(res, c)
+	}
+	sortByName(res)
+	return
+}
+
+func (m *memoryCategoryRepository) GetByID(ctx context.Context, id int64) (domain.Category, error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res, ok := m.DB.Categories[id]
+	if !ok {
+		return domain.Category{}, errHandle.ErrNotFound
+	}
+	return res, nil
+}
+
+func (m *memoryCategoryRepository) GetByTag(ctx context.Context, tag string) (res domain.Category, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	for _, c := range m.DB.Categories {
+		if c.Tag == tag && (res.ID == 0 || c.ID < res.ID) {
+			res = c
+		}
+	}
+	if res.ID == 0 {
+		return res, errHandle.ErrNotFound
+	}
+	return
+}
+
+func (m *memoryCategoryRepository) GetByArticleIDs(ctx context.Context, articleIDs []int64) (res map[int64][]domain.Category, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	res = map[int64][]domain.Category{}
+	for _, articleID := range articleIDs {
+		if _, done := res[articleID]; done {
+			continue
+		}
+		var list []domain.Category
+		for link := range m.DB.ArticleCategories {
+			if link.ArticleID != articleID {
+				continue
+			}
+			if c, ok := m.DB.Categories[link.CategoryID]; ok {
+				list = append(list, c)
+			}
+		}
+		if len(list) > 0 {
+			sortByName(list)
+			res[articleID] = list
+		}
+	}
+	return
+}
+
+// tagTaken reports whether a category other than id has the tag, like the
+// unique index of the SQL schema. The caller must hold the read lock.
+func (m *memoryCategoryRepository) tagTaken(tag string, id int64) bool {
+	for _, c := range m.DB.Categories {
+		if c.ID != id && c.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryCategoryRepository) Store(ctx context.Context, c *domain.Category) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	if m.tagTaken(c.Tag, 0) {
+		return errHandle.ErrConflict
+	}
+	c.ID = m.DB.NextID("category")
+	m.DB.Categories[c.ID] = *c
+	return
+}
+
+func (m *memoryCategoryRepository) Update(ctx context.Context, c *domain.Category) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	existing, ok := m.DB.Categories[c.ID]
+	if !ok {
+		return errHandle.ErrNotFound
+	}
+	if m.tagTaken(c.Tag, c.ID) {
+		return errHandle.ErrConflict
+	}
+	existing.Name = c.Name
+	existing.Tag = c.Tag
+	existing.UpdatedAt = c.UpdatedAt
+	m.DB.Categories[c.ID] = existing
+	return
+}
+
+func (m *memoryCategoryRepository) Delete(ctx context.Context, id int64) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	if _, ok := m.DB.Categories[id]; !ok {
+		return errHandle.ErrNotFound
+	}
+	for link := range m.DB.ArticleCategories {
+		if link.CategoryID == id {
+			delete(m.DB.ArticleCategories, link)
+		}
+	}
+	delete(m.DB.Categories, id)
+	return
+}
+
+func (m *memoryCategoryRepository) AddArticle(ctx context.Context, articleID int64, categoryID int64) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	link := memdb.ArticleCategory{ArticleID: articleID, CategoryID: categoryID}
+	if _, ok := m.DB.ArticleCategories[link]; ok {
+		return errHandle.ErrConflict
+	}
+	m.DB.ArticleCategories[link] = struct{}{}
+	return
+}
+
+func (m *memoryCategoryRepository) RemoveArticle(ctx context.Context, articleID int64, categoryID int64) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	link := memdb.ArticleCategory{ArticleID: articleID, CategoryID: categoryID}
+	if _, ok := m.DB.ArticleCategories[link]; !ok {
+		return errHandle.ErrNotFound
+	}
+	delete(m.DB.ArticleCategories, link)
+	return
+}
@@ -0,0 +1,241 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type mysqlCategoryRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlCategoryRepository will create an object that represent the category.Repository interface
+func NewMysqlCategoryRepository(Conn *sql.DB) domain.CategoryRepository {
+	return &mysqlCategoryRepository{Conn}
+}
+
+func (m *mysqlCategoryRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Category, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Category, 0)
+	for rows.Next() {
+		category := domain.Category{}
+		err = rows.Scan(
+			&category.ID,
+			&category.Name,
+			&category.Tag,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, category)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlCategoryRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Category, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, errHandle.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mysqlCategoryRepository) Fetch(ctx context.Context) (res []domain.Category, err error) {
+	query := `SELECT id, name, tag, created_at, updated_at FROM category ORDER BY name`
+	return m.fetch(ctx, query)
+}
+
+func (m *mysqlCategoryRepository) GetByID(ctx context.Context, id int64) (domain.Category, error) {
+	query := `SELECT id, name, tag, created_at, updated_at FROM category WHERE id = ?`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *mysqlCategoryRepository) GetByTag(ctx context.Context, tag string) (domain.Category, error) {
+	query := `SELECT id, name, tag, created_at, updated_at FROM category WHERE tag = ?`
+	return m.getOne(ctx, query, tag)
+}
+
+func (m *mysqlCategoryRepository) GetByArticleIDs(ctx context.Context, articleIDs []int64) (res map[int64][]domain.Category, err error) {
+	res = map[int64][]domain.Category{}
+	if len(articleIDs) == 0 {
+		return
+	}
+
+	args := make([]interface{}, len(articleIDs))
+	for i, id := range articleIDs {
+		args[i] = id
+	}
+	query := `SELECT ac.article_id, c.id, c.name, c.tag, c.created_at, c.updated_at
+  						FROM category c JOIN article_category ac ON ac.category_id = c.id
+  						WHERE ac.article_id IN (?` + strings.Repeat(",?", len(articleIDs)-1) + `) ORDER BY c.name`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	for rows.Next() {
+		articleID := int64(0)
+		category := domain.Category{}
+		err = rows.Scan(
+			&articleID,
+			&category.ID,
+			&category.Name,
+			&category.Tag,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res[articleID] = append(res[articleID], category)
+	}
+
+	return res, nil
+}
+
+func (m *mysqlCategoryRepository) Store(ctx context.Context, c *domain.Category) (err error) {
+	query := `INSERT category SET name=? , tag=? , created_at=?, updated_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, c.Name, c.Tag, c.CreatedAt, c.UpdatedAt)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	c.ID = lastID
+	return
+}
+
+func (m *mysqlCategoryRepository) Update(ctx context.Context, c *domain.Category) (err error) {
+	query := `UPDATE category set name=?, tag=?, updated_at=? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, c.Name, c.Tag, c.UpdatedAt, c.ID)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (m *mysqlCategoryRepository) Delete(ctx context.Context, id int64) error {
+	return transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) (err error) {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM article_category WHERE category_id = ?", id)
+		if err != nil {
+			return
+		}
+
+		res, err := tx.ExecContext(ctx, "DELETE FROM category WHERE id = ?", id)
+		if err != nil {
+			return
+		}
+
+		return checkAffected(res)
+	})
+}
+
+func (m *mysqlCategoryRepository) AddArticle(ctx context.Context, articleID int64, categoryID int64) (err error) {
+	query := `INSERT article_category SET article_id=? , category_id=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, articleID, categoryID)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+
+	return
+}
+
+func (m *mysqlCategoryRepository) RemoveArticle(ctx context.Context, articleID int64, categoryID int64) (err error) {
+	query := `DELETE FROM article_category WHERE article_id = ? AND category_id = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, articleID, categoryID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return nil
+}
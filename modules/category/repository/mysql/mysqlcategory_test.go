@@ -0,0 +1,191 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	categoryMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/repository/mysql"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "tag", "created_at", "updated_at"}).
+		AddRow(1, "Makanan", "food", time.Now(), time.Now()).
+		AddRow(2, "Kehidupan", "life", time.Now(), time.Now())
+
+	query := "SELECT id, name, tag, created_at, updated_at FROM category ORDER BY name"
+
+	mock.ExpectQuery(query).WillReturnRows(rows)
+	c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+	list, err := c.Fetch(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestGetByTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT id, name, tag, created_at, updated_at FROM category WHERE tag = \\?"
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "tag", "created_at", "updated_at"}).
+			AddRow(1, "Makanan", "food", time.Now(), time.Now())
+		mock.ExpectQuery(query).WithArgs("food").WillReturnRows(rows)
+		c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+		category, err := c.GetByTag(context.TODO(), "food")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), category.ID)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "tag", "created_at", "updated_at"})
+		mock.ExpectQuery(query).WithArgs("sport").WillReturnRows(rows)
+		c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+		_, err := c.GetByTag(context.TODO(), "sport")
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
+}
+
+func TestGetByArticleIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"article_id", "id", "name", "tag", "created_at", "updated_at"}).
+		AddRow(1, 1, "Makanan", "food", time.Now(), time.Now()).
+		AddRow(1, 2, "Kehidupan", "life", time.Now(), time.Now()).
+		AddRow(2, 2, "Kehidupan", "life", time.Now(), time.Now())
+
+	query := "SELECT ac.article_id, c.id, c.name, c.tag, c.created_at, c.updated_at FROM category c " +
+		"JOIN article_category ac ON ac.category_id = c.id WHERE ac.article_id IN \\(\\?,\\?,\\?\\) ORDER BY c.name"
+
+	mock.ExpectQuery(query).WithArgs(1, 2, 3).WillReturnRows(rows)
+	c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+	res, err := c.GetByArticleIDs(context.TODO(), []int64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, res[1], 2)
+	assert.Len(t, res[2], 1)
+	assert.Len(t, res[3], 0)
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	category := &domain.Category{
+		Name:      "Olahraga",
+		Tag:       "sport",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT category SET name=\\? , tag=\\? , created_at=\\?, updated_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(category.Name, category.Tag, category.CreatedAt, category.UpdatedAt).WillReturnResult(sqlmock.NewResult(4, 1))
+
+	c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+	err = c.Store(context.TODO(), category)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), category.ID)
+}
+
+func TestStoreDuplicateTag(t *testing.T) {
+	category := &domain.Category{Name: "Olahraga", Tag: "sport"}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT category SET name=\\? , tag=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'sport' for key 'category_tag'"})
+
+	c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+	err = c.Store(context.TODO(), category)
+	assert.Equal(t, errHandle.ErrConflict, err)
+	assert.Equal(t, int64(0), category.ID)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM article_category WHERE category_id = \\?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("DELETE FROM category WHERE id = \\?").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+	err = c.Delete(context.TODO(), int64(3))
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT article_category SET article_id=\\? , category_id=\\?"
+
+	t.Run("success", func(t *testing.T) {
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(12, 1))
+		c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+		err := c.AddArticle(context.TODO(), int64(1), int64(3))
+		assert.NoError(t, err)
+	})
+
+	t.Run("already-assigned", func(t *testing.T) {
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(1, 3).WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+		err := c.AddArticle(context.TODO(), int64(1), int64(3))
+		assert.Equal(t, errHandle.ErrConflict, err)
+	})
+}
+
+func TestRemoveArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "DELETE FROM article_category WHERE article_id = \\? AND category_id = \\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	c := categoryMysqlRepo.NewMysqlCategoryRepository(db)
+
+	err = c.RemoveArticle(context.TODO(), int64(1), int64(3))
+	assert.Equal(t, errHandle.ErrNotFound, err)
+}
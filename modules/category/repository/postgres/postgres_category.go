@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type postgresCategoryRepository struct {
+	Conn *sql.DB
+}
+
+// NewPostgresCategoryRepository will create an object that represent the category.Repository interface
+func NewPostgresCategoryRepository(Conn *sql.DB) domain.CategoryRepository {
+	return &postgresCategoryRepository{Conn}
+}
+
+func (m *postgresCategoryRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Category, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Category, 0)
+	for rows.Next() {
+		category := domain.Category{}
+		err = rows.Scan(
+			&category.ID,
+			&category.Name,
+			&category.Tag,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, category)
+	}
+
+	return result, nil
+}
+
+func (m *postgresCategoryRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Category, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, errHandle.ErrNotFound
+	}
+
+	return
+}
+
+func (m *postgresCategoryRepository) Fetch(ctx context.Context) (res []domain.Category, err error) {
+	query := `SELECT id, name, tag, created_at, updated_at FROM category ORDER BY name`
+	return m.fetch(ctx, query)
+}
+
+func (m *postgresCategoryRepository) GetByID(ctx context.Context, id int64) (domain.Category, error) {
+	query := `SELECT id, name, tag, created_at, updated_at FROM category WHERE id = $1`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *postgresCategoryRepository) GetByTag(ctx context.Context, tag string) (domain.Category, error) {
+	query := `SELECT id, name, tag, created_at, updated_at FROM category WHERE tag = $1`
+	return m.getOne(ctx, query, tag)
+}
+
+func (m *postgresCategoryRepository) GetByArticleIDs(ctx context.Context, articleIDs []int64) (res map[int64][]domain.Category, err error) {
+	res = map[int64][]domain.Category{}
+	if len(articleIDs) == 0 {
+		return
+	}
+
+	query := `SELECT ac.article_id, c.id, c.name, c.tag, c.created_at, c.updated_at
+  						FROM category c JOIN article_category ac ON ac.category_id = c.id
+  						WHERE ac.article_id = ANY($1) ORDER BY c.name`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, pq.Array(articleIDs))
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	for rows.Next() {
+		articleID := int64(0)
+		category := domain.Category{}
+		err = rows.Scan(
+			&articleID,
+			&category.ID,
+			&category.Name,
+			&category.Tag,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res[articleID] = append(res[articleID], category)
+	}
+
+	return res, nil
+}
+
+func (m *postgresCategoryRepository) Store(ctx context.Context, c *domain.Category) (err error) {
+	query := `INSERT INTO category (name, tag, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	err = stmt.QueryRowContext(ctx, c.Name, c.Tag, c.CreatedAt, c.UpdatedAt).Scan(&c.ID)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	return
+}
+
+func (m *postgresCategoryRepository) Update(ctx context.Context, c *domain.Category) (err error) {
+	query := `UPDATE category set name=$1, tag=$2, updated_at=$3 WHERE id = $4`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, c.Name, c.Tag, c.UpdatedAt, c.ID)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func (m *postgresCategoryRepository) Delete(ctx context.Context, id int64) error {
+	return transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) (err error) {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM article_category WHERE category_id = $1", id)
+		if err != nil {
+			return
+		}
+
+		res, err := tx.ExecContext(ctx, "DELETE FROM category WHERE id = $1", id)
+		if err != nil {
+			return
+		}
+
+		return checkAffected(res)
+	})
+}
+
+func (m *postgresCategoryRepository) AddArticle(ctx context.Context, articleID int64, categoryID int64) (err error) {
+	query := `INSERT INTO article_category (article_id, category_id) VALUES ($1, $2)`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, articleID, categoryID)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+
+	return
+}
+
+func (m *postgresCategoryRepository) RemoveArticle(ctx context.Context, articleID int64, categoryID int64) (err error) {
+	query := `DELETE FROM article_category WHERE article_id = $1 AND category_id = $2`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, articleID, categoryID)
+	if err != nil {
+		return
+	}
+
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return nil
+}
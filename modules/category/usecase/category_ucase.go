@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type categoryUsecase struct {
+	categoryRepo   domain.CategoryRepository
+	articleRepo    domain.ArticleRepository
+	policy         domain.Policy
+	searcher       domain.ArticleSearcher
+	contextTimeout time.Duration
+}
+
+// NewCategoryUsecase will create new an categoryUsecase object representation of domain.CategoryUsecase interface
+func NewCategoryUsecase(c domain.CategoryRepository, a domain.ArticleRepository, p domain.Policy, s domain.ArticleSearcher, timeout time.Duration) domain.CategoryUsecase {
+	return &categoryUsecase{
+		categoryRepo:   c,
+		articleRepo:    a,
+		policy:         p,
+		searcher:       s,
+		contextTimeout: timeout,
+	}
+}
+
+// articleIDs lists the ids of the published articles in the category, the
+// ones in the search index
+func (u *categoryUsecase) articleIDs(ctx context.Context, categoryID int64) ([]int64, error) {
+	ids := make([]int64, 0)
+	page := domain.Page{Num: 100}
+	for {
+		res, cursors, err := u.articleRepo.FetchByCategory(ctx, categoryID, domain.StatusPublished, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, ar := range res {
+			ids = append(ids, ar.ID)
+		}
+		if cursors.Next == "" {
+			return ids, nil
+		}
+		page.Cursor = cursors.Next
+	}
+}
+
+// reindex refreshes the categories of the published articles in the search
+// index. The index is rebuilt from the repositories by the reindex command,
+// so failing to keep it in sync is only logged.
+func (u *categoryUsecase) reindex(ctx context.Context, articleIDs []int64) {
+	if len(articleIDs) == 0 {
+		return
+	}
+
+	categories, err := u.categoryRepo.GetByArticleIDs(ctx, articleIDs)
+	if err != nil {
+		logrus.Errorf("indexing articles %v: %v", articleIDs, err)
+		return
+	}
+	articles := make([]domain.Article, 0, len(articleIDs))
+	for _, id := range articleIDs {
+		ar, err := u.articleRepo.GetByID(ctx, id)
+		if err != nil {
+			logrus.Errorf("indexing article %d: %v", id, err)
+			continue
+		}
+		if ar.Status != domain.StatusPublished {
+			continue
+		}
+		ar.Categories = categories[id]
+		articles = append(articles, ar)
+	}
+	if err = u.searcher.Index(ctx, articles); err != nil {
+		logrus.Errorf("indexing articles %v: %v", articleIDs, err)
+	}
+}
+
+func (u *categoryUsecase) Fetch(c context.Context) (res []domain.Category, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.categoryRepo.Fetch(ctx)
+}
+
+func (u *categoryUsecase) GetByID(c context.Context, id int64) (res domain.Category, err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.categoryRepo.GetByID(ctx, id)
+}
+
+func (u *categoryUsecase) Store(c context.Context, m *domain.Category) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	if err = u.policy.CanManageCategories(ctx); err != nil {
+		return
+	}
+
+	// a taken tag is an ErrConflict of the repository, whose unique index
+	// holds even when two requests race for the tag
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	return u.categoryRepo.Store(ctx, m)
+}
+
+func (u *categoryUsecase) Update(c context.Context, m *domain.Category) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	if err = u.policy.CanManageCategories(ctx); err != nil {
+		return
+	}
+
+	existed, err := u.categoryRepo.GetByTag(ctx, m.Tag)
+	if err == nil && existed.ID != m.ID {
+		return errHandle.ErrConflict
+	}
+	if err != nil && err != errHandle.ErrNotFound {
+		return
+	}
+
+	tagChanged := err == errHandle.ErrNotFound
+
+	m.UpdatedAt = time.Now()
+	if err = u.categoryRepo.Update(ctx, m); err != nil {
+		return
+	}
+
+	if tagChanged {
+		ids, errIDs := u.articleIDs(ctx, m.ID)
+		if errIDs != nil {
+			logrus.Errorf("indexing the articles of category %d: %v", m.ID, errIDs)
+		}
+		u.reindex(ctx, ids)
+	}
+	return
+}
+
+func (u *categoryUsecase) Delete(c context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+	if err = u.policy.CanManageCategories(ctx); err != nil {
+		return
+	}
+
+	ids, err := u.articleIDs(ctx, id)
+	if err != nil {
+		return
+	}
+	if err = u.categoryRepo.Delete(ctx, id); err != nil {
+		return
+	}
+
+	u.reindex(ctx, ids)
+	return
+}
+
+func (u *categoryUsecase) AssignArticle(c context.Context, articleID int64, categoryID int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	ar, err := u.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return
+	}
+	if err = u.policy.CanModifyArticle(ctx, ar); err != nil {
+		return
+	}
+	if _, err = u.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return
+	}
+
+	if err = u.categoryRepo.AddArticle(ctx, articleID, categoryID); err != nil {
+		return
+	}
+
+	u.reindex(ctx, []int64{articleID})
+	return
+}
+
+func (u *categoryUsecase) UnassignArticle(c context.Context, articleID int64, categoryID int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	ar, err := u.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return
+	}
+	if err = u.policy.CanModifyArticle(ctx, ar); err != nil {
+		return
+	}
+
+	if err = u.categoryRepo.RemoveArticle(ctx, articleID, categoryID); err != nil {
+		return
+	}
+
+	u.reindex(ctx, []int64{articleID})
+	return
+}
@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
+	ucase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/category/usecase"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/policy"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+// newPolicy returns a Policy allowing the caller to manage categories and
+// to modify any article
+func newPolicy() *mocks.Policy {
+	policy := new(mocks.Policy)
+	policy.On("CanManageCategories", mock.Anything).Return(nil)
+	policy.On("CanModifyArticle", mock.Anything, mock.AnythingOfType("domain.Article")).Return(nil)
+	return policy
+}
+
+func TestStore(t *testing.T) {
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockSearcher := new(mocks.ArticleSearcher)
+	mockCategory := domain.Category{
+		Name: "Olahraga",
+		Tag:  "sport",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tempMockCategory := mockCategory
+		mockCategoryRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Category")).Return(nil).Once()
+
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, newPolicy(), mockSearcher, time.Second*2)
+
+		err := u.Store(context.TODO(), &tempMockCategory)
+
+		assert.NoError(t, err)
+		assert.False(t, tempMockCategory.CreatedAt.IsZero())
+		mockCategoryRepo.AssertExpectations(t)
+	})
+	t.Run("existing-tag", func(t *testing.T) {
+		tempMockCategory := mockCategory
+		mockCategoryRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Category")).Return(errHandle.ErrConflict).Once()
+
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, newPolicy(), mockSearcher, time.Second*2)
+
+		err := u.Store(context.TODO(), &tempMockCategory)
+
+		assert.Equal(t, errHandle.ErrConflict, err)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockSearcher := new(mocks.ArticleSearcher)
+	mockCategory := domain.Category{
+		ID:   4,
+		Name: "Olahraga",
+		Tag:  "sport",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tempMockCategory := mockCategory
+		mockCategoryRepo.On("GetByTag", mock.Anything, "sport").Return(mockCategory, nil).Once()
+		mockCategoryRepo.On("Update", mock.Anything, &tempMockCategory).Return(nil).Once()
+
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, newPolicy(), mockSearcher, time.Second*2)
+
+		err := u.Update(context.TODO(), &tempMockCategory)
+
+		assert.NoError(t, err)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+	t.Run("tag-renamed", func(t *testing.T) {
+		tempMockCategory := mockCategory
+		tempMockCategory.Tag = "sports"
+		mockCategoryRepo.On("GetByTag", mock.Anything, "sports").Return(domain.Category{}, errHandle.ErrNotFound).Once()
+		mockCategoryRepo.On("Update", mock.Anything, &tempMockCategory).Return(nil).Once()
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).
+			Return(map[int64][]domain.Category{1: {tempMockCategory}}, nil).Once()
+
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("FetchByCategory", mock.Anything, int64(4), domain.StatusPublished, mock.AnythingOfType("domain.Page")).
+			Return([]domain.Article{{ID: 1}}, domain.Cursors{}, nil).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Lari pagi", Status: domain.StatusPublished}, nil).Once()
+		mockSearcher.On("Index", mock.Anything, []domain.Article{{ID: 1, Title: "Lari pagi", Status: domain.StatusPublished, Categories: []domain.Category{tempMockCategory}}}).
+			Return(nil).Once()
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, newPolicy(), mockSearcher, time.Second*2)
+
+		err := u.Update(context.TODO(), &tempMockCategory)
+
+		assert.NoError(t, err)
+		mockCategoryRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
+	})
+	t.Run("tag-taken", func(t *testing.T) {
+		tempMockCategory := mockCategory
+		mockCategoryRepo.On("GetByTag", mock.Anything, "sport").Return(domain.Category{ID: 5, Tag: "sport"}, nil).Once()
+
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, newPolicy(), mockSearcher, time.Second*2)
+
+		err := u.Update(context.TODO(), &tempMockCategory)
+
+		assert.Equal(t, errHandle.ErrConflict, err)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestAssignArticle(t *testing.T) {
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockSearcher := new(mocks.ArticleSearcher)
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Status: domain.StatusPublished}, nil).Twice()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(3)).Return(domain.Category{ID: 3}, nil).Once()
+		mockCategoryRepo.On("AddArticle", mock.Anything, int64(1), int64(3)).Return(nil).Once()
+		mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).
+			Return(map[int64][]domain.Category{1: {{ID: 3}}}, nil).Once()
+		mockSearcher.On("Index", mock.Anything, []domain.Article{{ID: 1, Status: domain.StatusPublished, Categories: []domain.Category{{ID: 3}}}}).Return(nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, newPolicy(), mockSearcher, time.Second*2)
+
+		err := u.AssignArticle(context.TODO(), 1, 3)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+		mockSearcher.AssertExpectations(t)
+	})
+	t.Run("article-is-not-exist", func(t *testing.T) {
+		mockArticleRepo.On("GetByID", mock.Anything, int64(2)).Return(domain.Article{}, errHandle.ErrNotFound).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, newPolicy(), mockSearcher, time.Second*2)
+
+		err := u.AssignArticle(context.TODO(), 2, 3)
+
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		mockArticleRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestManageForbidden(t *testing.T) {
+	for name, ctx := range map[string]context.Context{
+		"anonymous": context.TODO(),
+		"author":    domain.NewContextWithUser(context.TODO(), domain.User{ID: 2, AuthorID: 1, Roles: []domain.Role{domain.RoleAuthor}}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			want := errHandle.ErrForbidden
+			if name == "anonymous" {
+				want = errHandle.ErrUnauthorized
+			}
+			mockCategoryRepo := new(mocks.CategoryRepository)
+			mockArticleRepo := new(mocks.ArticleRepository)
+			mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Author: domain.Author{ID: 8}}, nil)
+			u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, policy.NewRBACPolicy(), new(mocks.ArticleSearcher), time.Second*2)
+
+			assert.Equal(t, want, u.Store(ctx, &domain.Category{Name: "Olahraga", Tag: "sport"}))
+			assert.Equal(t, want, u.Update(ctx, &domain.Category{ID: 4, Name: "Olahraga", Tag: "sport"}))
+			assert.Equal(t, want, u.Delete(ctx, 4))
+			assert.Equal(t, want, u.AssignArticle(ctx, 7, 4), "someone else's article")
+			assert.Equal(t, want, u.UnassignArticle(ctx, 7, 4), "someone else's article")
+			mockCategoryRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+			mockCategoryRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			mockCategoryRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			mockCategoryRepo.AssertNotCalled(t, "AddArticle", mock.Anything, mock.Anything, mock.Anything)
+			mockCategoryRepo.AssertNotCalled(t, "RemoveArticle", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUnassignArticle(t *testing.T) {
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockSearcher := new(mocks.ArticleSearcher)
+	author := domain.NewContextWithUser(context.TODO(), domain.User{ID: 2, AuthorID: 1, Roles: []domain.Role{domain.RoleAuthor}})
+
+	mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Author: domain.Author{ID: 1}, Status: domain.StatusDraft}, nil).Once()
+	mockCategoryRepo.On("RemoveArticle", mock.Anything, int64(1), int64(3)).Return(nil).Once()
+	mockCategoryRepo.On("GetByArticleIDs", mock.Anything, []int64{1}).Return(map[int64][]domain.Category{}, nil).Maybe()
+	mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Author: domain.Author{ID: 1}, Status: domain.StatusDraft}, nil).Maybe()
+	mockSearcher.On("Index", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockSearcher.On("Delete", mock.Anything, mock.Anything).Return(nil).Maybe()
+	u := ucase.NewCategoryUsecase(mockCategoryRepo, mockArticleRepo, policy.NewRBACPolicy(), mockSearcher, time.Second*2)
+
+	err := u.UnassignArticle(author, 1, 3)
+
+	assert.NoError(t, err, "authors relabel their own articles")
+	mockCategoryRepo.AssertExpectations(t)
+}
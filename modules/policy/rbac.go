@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type rbacPolicy struct{}
+
+// NewRBACPolicy will create a domain.Policy deciding on the roles of the
+// user carried by the context
+func NewRBACPolicy() domain.Policy {
+	return &rbacPolicy{}
+}
+
+func (p *rbacPolicy) caller(ctx context.Context) (domain.User, error) {
+	user, ok := domain.UserFromContext(ctx)
+	if !ok {
+		return domain.User{}, errHandle.ErrUnauthorized
+	}
+	return user, nil
+}
+
+func (p *rbacPolicy) CanCreateArticle(ctx context.Context) error {
+	user, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.Can(domain.PermissionArticleWrite) {
+		return errHandle.ErrForbidden
+	}
+	return nil
+}
+
+// CanModifyArticle allows editors on any article, and authors on the
+// articles of the author profile linked to their account.
+func (p *rbacPolicy) CanModifyArticle(ctx context.Context, a domain.Article) error {
+	user, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if user.Can(domain.PermissionArticleEditAny) {
+		return nil
+	}
+	if user.Can(domain.PermissionArticleWrite) && user.AuthorID != 0 && user.AuthorID == a.Author.ID {
+		return nil
+	}
+	return errHandle.ErrForbidden
+}
+
+// CanPostAsAuthor allows users to publish under the author profile linked to
+// their account, and admins under any author.
+func (p *rbacPolicy) CanPostAsAuthor(ctx context.Context, authorID int64) error {
+	user, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if user.Can(domain.PermissionArticlePostAsAny) {
+		return nil
+	}
+	if user.Can(domain.PermissionArticleWrite) && user.AuthorID != 0 && user.AuthorID == authorID {
+		return nil
+	}
+	return errHandle.ErrForbidden
+}
+
+// CanPublishArticle allows editors and admins to publish, unpublish and
+// archive articles and to list the ones not published
+func (p *rbacPolicy) CanPublishArticle(ctx context.Context) error {
+	user, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.Can(domain.PermissionArticlePublish) {
+		return errHandle.ErrForbidden
+	}
+	return nil
+}
+
+func (p *rbacPolicy) CanManageUsers(ctx context.Context) error {
+	user, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.Can(domain.PermissionUserManage) {
+		return errHandle.ErrForbidden
+	}
+	return nil
+}
+
+// CanManageAuthors allows editors and admins to create, edit and delete authors
+func (p *rbacPolicy) CanManageAuthors(ctx context.Context) error {
+	user, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.Can(domain.PermissionAuthorManage) {
+		return errHandle.ErrForbidden
+	}
+	return nil
+}
+
+// CanManageCategories allows editors and admins to create, edit and delete
+// categories
+func (p *rbacPolicy) CanManageCategories(ctx context.Context) error {
+	user, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.Can(domain.PermissionCategoryManage) {
+		return errHandle.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/policy"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func withUser(roles []domain.Role, authorID int64) context.Context {
+	return domain.NewContextWithUser(context.TODO(), domain.User{ID: 1, AuthorID: authorID, Roles: roles})
+}
+
+func TestCanCreateArticle(t *testing.T) {
+	p := policy.NewRBACPolicy()
+
+	assert.Equal(t, errHandle.ErrUnauthorized, p.CanCreateArticle(context.TODO()))
+	assert.Equal(t, errHandle.ErrForbidden, p.CanCreateArticle(withUser(nil, 0)))
+	assert.NoError(t, p.CanCreateArticle(withUser([]domain.Role{domain.RoleAuthor}, 1)))
+}
+
+func TestCanModifyArticle(t *testing.T) {
+	p := policy.NewRBACPolicy()
+	article := domain.Article{ID: 1, Author: domain.Author{ID: 7}}
+
+	t.Run("anonymous", func(t *testing.T) {
+		assert.Equal(t, errHandle.ErrUnauthorized, p.CanModifyArticle(context.TODO(), article))
+	})
+
+	t.Run("own-article", func(t *testing.T) {
+		assert.NoError(t, p.CanModifyArticle(withUser([]domain.Role{domain.RoleAuthor}, 7), article))
+	})
+
+	t.Run("someone-else-article", func(t *testing.T) {
+		assert.Equal(t, errHandle.ErrForbidden, p.CanModifyArticle(withUser([]domain.Role{domain.RoleAuthor}, 8), article))
+	})
+
+	t.Run("no-author-profile", func(t *testing.T) {
+		assert.Equal(t, errHandle.ErrForbidden, p.CanModifyArticle(withUser([]domain.Role{domain.RoleAuthor}, 0), domain.Article{}))
+	})
+
+	t.Run("editor", func(t *testing.T) {
+		assert.NoError(t, p.CanModifyArticle(withUser([]domain.Role{domain.RoleEditor}, 0), article))
+	})
+}
+
+func TestCanPostAsAuthor(t *testing.T) {
+	p := policy.NewRBACPolicy()
+
+	assert.Equal(t, errHandle.ErrUnauthorized, p.CanPostAsAuthor(context.TODO(), 7))
+	assert.NoError(t, p.CanPostAsAuthor(withUser([]domain.Role{domain.RoleAuthor}, 7), 7))
+	assert.Equal(t, errHandle.ErrForbidden, p.CanPostAsAuthor(withUser([]domain.Role{domain.RoleEditor}, 7), 8))
+	assert.Equal(t, errHandle.ErrForbidden, p.CanPostAsAuthor(withUser([]domain.Role{domain.RoleAuthor}, 0), 0))
+	assert.NoError(t, p.CanPostAsAuthor(withUser([]domain.Role{domain.RoleAdmin}, 7), 8))
+}
+
+func TestCanPublishArticle(t *testing.T) {
+	p := policy.NewRBACPolicy()
+
+	assert.Equal(t, errHandle.ErrUnauthorized, p.CanPublishArticle(context.TODO()))
+	assert.Equal(t, errHandle.ErrForbidden, p.CanPublishArticle(withUser([]domain.Role{domain.RoleAuthor}, 7)))
+	assert.NoError(t, p.CanPublishArticle(withUser([]domain.Role{domain.RoleEditor}, 0)))
+	assert.NoError(t, p.CanPublishArticle(withUser([]domain.Role{domain.RoleAdmin}, 0)))
+}
+
+func TestCanManageUsers(t *testing.T) {
+	p := policy.NewRBACPolicy()
+
+	assert.Equal(t, errHandle.ErrUnauthorized, p.CanManageUsers(context.TODO()))
+	assert.Equal(t, errHandle.ErrForbidden, p.CanManageUsers(withUser([]domain.Role{domain.RoleEditor}, 0)))
+	assert.NoError(t, p.CanManageUsers(withUser([]domain.Role{domain.RoleAdmin}, 0)))
+}
+
+func TestCanManageAuthors(t *testing.T) {
+	p := policy.NewRBACPolicy()
+
+	assert.Equal(t, errHandle.ErrUnauthorized, p.CanManageAuthors(context.TODO()))
+	assert.Equal(t, errHandle.ErrForbidden, p.CanManageAuthors(withUser([]domain.Role{domain.RoleAuthor}, 7)))
+	assert.NoError(t, p.CanManageAuthors(withUser([]domain.Role{domain.RoleEditor}, 0)))
+	assert.NoError(t, p.CanManageAuthors(withUser([]domain.Role{domain.RoleAdmin}, 0)))
+}
+
+func TestCanManageCategories(t *testing.T) {
+	p := policy.NewRBACPolicy()
+
+	assert.Equal(t, errHandle.ErrUnauthorized, p.CanManageCategories(context.TODO()))
+	assert.Equal(t, errHandle.ErrForbidden, p.CanManageCategories(withUser([]domain.Role{domain.RoleAuthor}, 7)))
+	assert.NoError(t, p.CanManageCategories(withUser([]domain.Role{domain.RoleEditor}, 0)))
+	assert.NoError(t, p.CanManageCategories(withUser([]domain.Role{domain.RoleAdmin}, 0)))
+}
@@ -17,7 +17,7 @@ func TestCORS(t *testing.T) {
 	req := test.NewRequest(echo.GET, "/", nil)
 	res := test.NewRecorder()
 	c := e.NewContext(req, res)
-	m := middleware.InitMiddleware()
+	m := middleware.InitMiddleware(nil)
 
 	h := m.CORS(echo.HandlerFunc(func(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
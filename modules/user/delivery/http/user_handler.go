@@ -1,7 +1,6 @@
 package http
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -9,6 +8,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
 	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
@@ -17,6 +17,19 @@ type ResponseError struct {
 	Message string `json:"message"`
 }
 
+// registerRequest represent the request body to sign up a new user
+type registerRequest struct {
+	Fullname string `json:"fullname" validate:"required,max=200"`
+	Username string `json:"username" validate:"required,alphanum,max=45"`
+	Email    string `json:"email" validate:"required,email,max=200"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// roleRequest represent the request body to grant a role
+type roleRequest struct {
+	Role domain.Role `json:"role" validate:"required"`
+}
+
 // UserHandler  represent the httphandler for article
 type UserHandler struct {
 	UserUcase domain.UserUsecase
@@ -28,24 +41,101 @@ func NewUserHandler(e *echo.Echo, us domain.UserUsecase) {
 		UserUcase: us,
 	}
 	e.GET("/users", handler.FetchUser)
+	e.POST("/users", handler.Register)
+	e.POST("/users/:id/roles", handler.GrantRole)
+	e.DELETE("/users/:id/roles/:role", handler.RevokeRole)
 }
 
 // FetchUser will fetch the article based on given params
 func (a *UserHandler) FetchUser(c echo.Context) error {
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
-	cursor := c.QueryParam("cursor")
+	page := domain.Page{
+		Cursor: c.QueryParam("cursor"),
+		Num:    int64(num),
+		Sort:   domain.SortDirection(c.QueryParam("sort")),
+	}
 	ctx := c.Request().Context()
 
-	listUser, nextCursor, err := a.UserUcase.Fetch(ctx, cursor, int64(num))
+	listUser, cursors, err := a.UserUcase.Fetch(ctx, page)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
-	fmt.Println(nextCursor)
-	c.Response().Header().Set(`X-Cursor`, nextCursor)
+
+	c.Response().Header().Set(`X-Cursor`, cursors.Next)
+	c.Response().Header().Set(`X-Prev-Cursor`, cursors.Prev)
 	return c.JSON(http.StatusOK, listUser)
 }
 
+// Register will sign up a new user by given request body
+func (a *UserHandler) Register(c echo.Context) (err error) {
+	var req registerRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	user := domain.User{
+		Fullname: req.Fullname,
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+	}
+	ctx := c.Request().Context()
+	err = a.UserUcase.Store(ctx, &user)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, user)
+}
+
+// GrantRole will grant the role given in the request body to the user
+func (a *UserHandler) GrantRole(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	var req roleRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err = validation.Struct(&req); err != nil {
+		return validation.Respond(c, err)
+	}
+
+	ctx := c.Request().Context()
+	err = a.UserUcase.GrantRole(ctx, int64(idP), req.Role)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RevokeRole will revoke the role given in the path from the user
+func (a *UserHandler) RevokeRole(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, errHandle.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = a.UserUcase.RevokeRole(ctx, int64(idP), domain.Role(c.Param("role")))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func getStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
@@ -59,6 +149,12 @@ func getStatusCode(err error) int {
 		return http.StatusNotFound
 	case errHandle.ErrConflict:
 		return http.StatusConflict
+	case errHandle.ErrBadParamInput:
+		return http.StatusBadRequest
+	case errHandle.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case errHandle.ErrForbidden:
+		return http.StatusForbidden
 	default:
 		return http.StatusInternalServerError
 	}
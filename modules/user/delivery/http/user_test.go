@@ -4,12 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"strconv"
 	"strings"
 	"testing"
-	"time"
 
-	"github.com/bxcodec/faker"
 	"github.com/labstack/echo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,54 +14,64 @@ import (
 
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
-	articleHttp "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/delivery/http"
+	userHttp "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/delivery/http"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 func TestFetch(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
-	assert.NoError(t, err)
-	mockUCase := new(mocks.ArticleUsecase)
-	mockListArticle := make([]domain.Article, 0)
-	mockListArticle = append(mockListArticle, mockArticle)
+	mockUser := domain.User{
+		ID:       1,
+		Fullname: "Iman Tumorang",
+		Username: "iman",
+		Email:    "iman@example.com",
+		Roles:    []domain.Role{domain.RoleAuthor},
+	}
+	mockUCase := new(mocks.UserUsecase)
+	mockListUser := make([]domain.User, 0)
+	mockListUser = append(mockListUser, mockUser)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListArticle, "10", nil)
+	page := domain.Page{Cursor: cursor, Num: int64(num), Sort: domain.SortDesc}
+	mockUCase.On("Fetch", mock.Anything, page).Return(mockListUser, domain.Cursors{Next: "10", Prev: "8"}, nil)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/users?num=1&sort=desc&cursor="+cursor, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	handler := articleHttp.ArticleHandler{
-		AUsecase: mockUCase,
+	handler := userHttp.UserHandler{
+		UserUcase: mockUCase,
 	}
-	err = handler.FetchArticle(c)
+	err = handler.FetchUser(c)
 	require.NoError(t, err)
 
 	responseCursor := rec.Header().Get("X-Cursor")
 	assert.Equal(t, "10", responseCursor)
+	assert.Equal(t, "8", rec.Header().Get("X-Prev-Cursor"))
+	assert.Contains(t, rec.Body.String(), `"roles":["author"]`)
 	assert.Equal(t, http.StatusOK, rec.Code)
 	mockUCase.AssertExpectations(t)
 }
 
 func TestFetchError(t *testing.T) {
-	mockUCase := new(mocks.ArticleUsecase)
+	mockUCase := new(mocks.UserUsecase)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(nil, "", domain.ErrInternalServerError)
+	page := domain.Page{Cursor: cursor, Num: int64(num)}
+	mockUCase.On("Fetch", mock.Anything, page).Return(nil, domain.Cursors{}, errHandle.ErrInternalServerError)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
+	req, err := http.NewRequest(echo.GET, "/users?num=1&cursor="+cursor, strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	handler := articleHttp.ArticleHandler{
-		AUsecase: mockUCase,
+	handler := userHttp.UserHandler{
+		UserUcase: mockUCase,
 	}
-	err = handler.FetchArticle(c)
+	err = handler.FetchUser(c)
 	require.NoError(t, err)
 
 	responseCursor := rec.Header().Get("X-Cursor")
@@ -73,99 +80,183 @@ func TestFetchError(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
-func TestGetByID(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
-	assert.NoError(t, err)
-
-	mockUCase := new(mocks.ArticleUsecase)
-
-	num := int(mockArticle.ID)
-
-	mockUCase.On("GetByID", mock.Anything, int64(num)).Return(mockArticle, nil)
-
-	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/article/"+strconv.Itoa(num), strings.NewReader(""))
-	assert.NoError(t, err)
-
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
-	c.SetPath("article/:id")
-	c.SetParamNames("id")
-	c.SetParamValues(strconv.Itoa(num))
-	handler := articleHttp.ArticleHandler{
-		AUsecase: mockUCase,
-	}
-	err = handler.GetByID(c)
-	require.NoError(t, err)
-
-	assert.Equal(t, http.StatusOK, rec.Code)
-	mockUCase.AssertExpectations(t)
+func TestRegister(t *testing.T) {
+	body := `{"fullname":"Iman Tumorang","username":"iman","email":"iman@example.com","password":"supersecret"}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.UserUsecase)
+		mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Username == "iman" && u.Password == "supersecret"
+		})).Run(func(args mock.Arguments) {
+			u := args.Get(1).(*domain.User)
+			u.ID = 1
+			u.Password = "$2a$10$hashedpasswordvalue"
+		}).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/users", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := userHttp.UserHandler{
+			UserUcase: mockUCase,
+		}
+		err = handler.Register(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "hashedpasswordvalue")
+		assert.NotContains(t, rec.Body.String(), "supersecret")
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-email", func(t *testing.T) {
+		mockUCase := new(mocks.UserUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/users", strings.NewReader(`{"fullname":"Iman Tumorang","username":"iman","email":"iman","password":"supersecret"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := userHttp.UserHandler{
+			UserUcase: mockUCase,
+		}
+		err = handler.Register(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, validation.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+		var problem validation.Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, []validation.FieldError{{Field: "email", Code: "email", Message: "email must be a valid email address"}}, problem.Errors)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("too-long", func(t *testing.T) {
+		mockUCase := new(mocks.UserUsecase)
+		long := `{"fullname":"` + strings.Repeat("a", 201) + `","username":"` + strings.Repeat("a", 46) +
+			`","email":"` + strings.Repeat("a", 189) + `@example.com","password":"supersecret"}`
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/users", strings.NewReader(long))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := userHttp.UserHandler{
+			UserUcase: mockUCase,
+		}
+		err = handler.Register(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var problem validation.Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		var fields []string
+		for _, fe := range problem.Errors {
+			assert.Equal(t, "max", fe.Code)
+			fields = append(fields, fe.Field)
+		}
+		assert.ElementsMatch(t, []string{"fullname", "username", "email"}, fields)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("already-registered", func(t *testing.T) {
+		mockUCase := new(mocks.UserUsecase)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errHandle.ErrConflict).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/users", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := userHttp.UserHandler{
+			UserUcase: mockUCase,
+		}
+		err = handler.Register(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
 }
 
-func TestStore(t *testing.T) {
-	mockArticle := domain.Article{
-		Title:     "Title",
-		Content:   "Content",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	tempMockArticle := mockArticle
-	tempMockArticle.ID = 0
-	mockUCase := new(mocks.ArticleUsecase)
-
-	j, err := json.Marshal(tempMockArticle)
-	assert.NoError(t, err)
-
-	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil)
-
-	e := echo.New()
-	req, err := http.NewRequest(echo.POST, "/article", strings.NewReader(string(j)))
-	assert.NoError(t, err)
-	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
-	c.SetPath("/article")
-
-	handler := articleHttp.ArticleHandler{
-		AUsecase: mockUCase,
-	}
-	err = handler.Store(c)
-	require.NoError(t, err)
-
-	assert.Equal(t, http.StatusCreated, rec.Code)
-	mockUCase.AssertExpectations(t)
+func TestGrantRole(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.UserUsecase)
+		mockUCase.On("GrantRole", mock.Anything, int64(2), domain.RoleEditor).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/users/2/roles", strings.NewReader(`{"role":"editor"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:id/roles")
+		c.SetParamNames("id")
+		c.SetParamValues("2")
+		handler := userHttp.UserHandler{
+			UserUcase: mockUCase,
+		}
+		err = handler.GrantRole(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockUCase := new(mocks.UserUsecase)
+		mockUCase.On("GrantRole", mock.Anything, int64(2), domain.RoleAdmin).Return(errHandle.ErrForbidden).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/users/2/roles", strings.NewReader(`{"role":"admin"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/users/:id/roles")
+		c.SetParamNames("id")
+		c.SetParamValues("2")
+		handler := userHttp.UserHandler{
+			UserUcase: mockUCase,
+		}
+		err = handler.GrantRole(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		mockUCase.AssertExpectations(t)
+	})
 }
 
-func TestDelete(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
-	assert.NoError(t, err)
-
-	mockUCase := new(mocks.ArticleUsecase)
-
-	num := int(mockArticle.ID)
-
-	mockUCase.On("Delete", mock.Anything, int64(num)).Return(nil)
+func TestRevokeRole(t *testing.T) {
+	mockUCase := new(mocks.UserUsecase)
+	mockUCase.On("RevokeRole", mock.Anything, int64(2), domain.RoleEditor).Return(nil).Once()
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.DELETE, "/article/"+strconv.Itoa(num), strings.NewReader(""))
+	req, err := http.NewRequest(echo.DELETE, "/users/2/roles/editor", strings.NewReader(""))
 	assert.NoError(t, err)
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	c.SetPath("article/:id")
-	c.SetParamNames("id")
-	c.SetParamValues(strconv.Itoa(num))
-	handler := articleHttp.ArticleHandler{
-		AUsecase: mockUCase,
+	c.SetPath("/users/:id/roles/:role")
+	c.SetParamNames("id", "role")
+	c.SetParamValues("2", "editor")
+	handler := userHttp.UserHandler{
+		UserUcase: mockUCase,
 	}
-	err = handler.Delete(c)
+	err = handler.RevokeRole(c)
 	require.NoError(t, err)
 
 	assert.Equal(t, http.StatusNoContent, rec.Code)
 	mockUCase.AssertExpectations(t)
-
 }
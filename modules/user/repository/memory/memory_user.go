@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type memoryUserRepository struct {
+	DB *memdb.DB
+}
+
+// NewMemoryUserRepository will create an object that represent the User.Repository interface
+func NewMemoryUserRepository(db *memdb.DB) domain.UserRepository {
+	return &memoryUserRepository{db}
+}
+
+// withRoles returns the user with its roles sorted like ORDER BY role. The
+// caller must hold the read lock.
+func (m *memoryUserRepository) withRoles(u domain.User) domain.User {
+	u.Roles = []domain.Role{}
+	for link := range m.DB.UserRoles {
+		if link.UserID == u.ID {
+			u.Roles = append(u.Roles, link.Role)
+		}
+	}
+	sort.Slice(u.Roles, func(i, j int) bool { return u.Roles[i] < u.Roles[j] })
+	return u
+}
+
+func (m *memoryUserRepository) Fetch(ctx context.Context, page domain.Page) (res []domain.User, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	ids := make([]int64, 0, len(m.DB.Users))
+	for id := range m.DB.Users {
+		ids = append(ids, id)
+	}
+
+	ids = memdb.Page(ids, func(id int64) pagination.Key {
+		return pagination.Key{CreatedAt: m.DB.Users[id].CreatedAt, ID: id}
+	}, keyset)
+	res = make([]domain.User, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, m.withRoles(m.DB.Users[id]))
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+// getOne returns the first user, by id, matching filter
+func (m *memoryUserRepository) getOne(filter func(u domain.User) bool) (res domain.User, err error) {
+	m.DB.RLock()
+	defer m.DB.RUnlock()
+
+	for _, u := range m.DB.Users {
+		if filter(u) && (res.ID == 0 || u.ID < res.ID) {
+			res = u
+		}
+	}
+	if res.ID == 0 {
+		return res, errHandle.ErrNotFound
+	}
+	return m.withRoles(res), nil
+}
+
+func (m *memoryUserRepository) GetByID(ctx context.Context, id int64) (domain.User, error) {
+	return m.getOne(func(u domain.User) bool { return u.ID == id })
+}
+
+func (m *memoryUserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	return m.getOne(func(u domain.User) bool { return u.Email == email })
+}
+
+func (m *memoryUserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	return m.getOne(func(u domain.User) bool { return u.Username == username })
+}
+
+func (m *memoryUserRepository) GetByAuthorID(ctx context.Context, authorID int64) (domain.User, error) {
+	return m.getOne(func(u domain.User) bool { return u.AuthorID == authorID })
+}
+
+// Store inserts the user together with its roles. Like the unique keys of the
+// user table, username, email and author id may only be used once.
+func (m *memoryUserRepository) Store(ctx context.Context, u *domain.User) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	for _, existing := range m.DB.Users {
+		if existing.Username == u.Username || existing.Email == u.Email ||
+			(u.AuthorID != 0 && existing.AuthorID == u.AuthorID) {
+			return errHandle.ErrConflict
+		}
+	}
+
+	u.ID = m.DB.NextID("user")
+	stored := *u
+	stored.Roles = nil
+	m.DB.Users[u.ID] = stored
+	for _, role := range u.Roles {
+		m.DB.UserRoles[memdb.UserRole{UserID: u.ID, Role: role}] = struct{}{}
+	}
+	return
+}
+
+func (m *memoryUserRepository) AddRole(ctx context.Context, userID int64, role domain.Role) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	link := memdb.UserRole{UserID: userID, Role: role}
+	if _, ok := m.DB.UserRoles[link]; ok {
+		return errHandle.ErrConflict
+	}
+	m.DB.UserRoles[link] = struct{}{}
+	return
+}
+
+func (m *memoryUserRepository) RemoveRole(ctx context.Context, userID int64, role domain.Role) (err error) {
+	m.DB.Lock()
+	defer m.DB.Unlock()
+
+	link := memdb.UserRole{UserID: userID, Role: role}
+	if _, ok := m.DB.UserRoles[link]; !ok {
+		return errHandle.ErrNotFound
+	}
+	delete(m.DB.UserRoles, link)
+	return
+}
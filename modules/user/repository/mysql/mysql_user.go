@@ -3,11 +3,14 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
-	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository"
 	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
@@ -21,7 +24,7 @@ func NewMysqlUserRepository(Conn *sql.DB) domain.UserRepository {
 }
 
 func (m *mysqlUserRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.User, err error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -43,6 +46,7 @@ func (m *mysqlUserRepository) fetch(ctx context.Context, query string, args ...i
 			&user.Username,
 			&user.Email,
 			&user.Password,
+			&user.AuthorID,
 			&user.UpdatedAt,
 			&user.CreatedAt,
 		)
@@ -54,26 +58,179 @@ func (m *mysqlUserRepository) fetch(ctx context.Context, query string, args ...i
 		result = append(result, user)
 	}
 
-	return result, nil
+	return m.fillRoles(ctx, result)
 }
 
-func (m *mysqlUserRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.User, nextCursor string, err error) {
-	query := `SELECT id, fullname, username, email, password, updated_at, created_at
-  						FROM user WHERE created_at > ? ORDER BY created_at LIMIT ? `
+// fillRoles loads the roles of every user in a single query
+func (m *mysqlUserRepository) fillRoles(ctx context.Context, users []domain.User) ([]domain.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+
+	args := make([]interface{}, len(users))
+	index := make(map[int64]int, len(users))
+	for i, user := range users {
+		args[i] = user.ID
+		index[user.ID] = i
+		users[i].Roles = []domain.Role{}
+	}
+	query := `SELECT user_id, role FROM user_role WHERE user_id IN (?` + strings.Repeat(",?", len(users)-1) + `) ORDER BY role`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	for rows.Next() {
+		var userID int64
+		var role domain.Role
+		if err = rows.Scan(&userID, &role); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		i := index[userID]
+		users[i].Roles = append(users[i].Roles, role)
+	}
+
+	return users, nil
+}
+
+func (m *mysqlUserRepository) Fetch(ctx context.Context, page domain.Page) (res []domain.User, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE (created_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, keyset.Args()...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *mysqlUserRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.User, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.User{}, err
+	}
 
-	decodedCursor, err := repository.DecodeCursor(cursor)
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, errHandle.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mysqlUserRepository) GetByID(ctx context.Context, id int64) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE id = ?`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *mysqlUserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE email = ?`
+	return m.getOne(ctx, query, email)
+}
+
+func (m *mysqlUserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE username = ?`
+	return m.getOne(ctx, query, username)
+}
+
+func (m *mysqlUserRepository) GetByAuthorID(ctx context.Context, authorID int64) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE author_id = ?`
+	return m.getOne(ctx, query, authorID)
+}
 
-	if err != nil && cursor != "" {
-		return nil, "", errHandle.ErrBadParamInput
+// Store inserts the user together with its roles
+func (m *mysqlUserRepository) Store(ctx context.Context, u *domain.User) error {
+	return transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) (err error) {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		var authorID interface{}
+		if u.AuthorID != 0 {
+			authorID = u.AuthorID
+		}
+
+		query := `INSERT user SET fullname=? , username=? , email=? , password=? , author_id=? , updated_at=? , created_at=?`
+		res, err := tx.ExecContext(ctx, query, u.Fullname, u.Username, u.Email, u.Password, authorID, u.UpdatedAt, u.CreatedAt)
+		if transaction.IsUniqueViolation(err) {
+			return errHandle.ErrConflict
+		}
+		if err != nil {
+			return
+		}
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return
+		}
+
+		for _, role := range u.Roles {
+			_, err = tx.ExecContext(ctx, `INSERT user_role SET user_id=? , role=?`, lastID, role)
+			if err != nil {
+				return
+			}
+		}
+
+		u.ID = lastID
+		return
+	})
+}
+
+func (m *mysqlUserRepository) AddRole(ctx context.Context, userID int64, role domain.Role) (err error) {
+	query := `INSERT user_role SET user_id=? , role=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
 	}
 
-	res, err = m.fetch(ctx, query, decodedCursor, num)
+	_, err = stmt.ExecContext(ctx, userID, role)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	return
+}
+
+func (m *mysqlUserRepository) RemoveRole(ctx context.Context, userID int64, role domain.Role) (err error) {
+	query := `DELETE FROM user_role WHERE user_id = ? AND role = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
-		return nil, "", err
+		return
 	}
 
-	if len(res) == int(num) {
-		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	res, err := stmt.ExecContext(ctx, userID, role)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
 	}
 
 	return
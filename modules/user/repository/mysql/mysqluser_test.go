@@ -5,12 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
-	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository"
-	articleMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/repository/mysql"
+	userMysqlRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/mysql"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 func TestFetch(t *testing.T) {
@@ -19,147 +20,142 @@ func TestFetch(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	mockArticles := []domain.Article{
-		domain.Article{
-			ID: 1, Title: "title 1", Content: "content 1",
-			Author: domain.Author{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
+	mockUsers := []domain.User{
+		domain.User{
+			ID: 1, Fullname: "Iman Tumorang", Username: "iman", Email: "iman@example.com",
+			Password: "secret", UpdatedAt: time.Now(), CreatedAt: time.Now(),
 		},
-		domain.Article{
-			ID: 2, Title: "title 2", Content: "content 2",
-			Author: domain.Author{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
+		domain.User{
+			ID: 2, Fullname: "Rachadian Novansyah", Username: "rachadian", Email: "rachadian@example.com",
+			Password: "secret", UpdatedAt: time.Now(), CreatedAt: time.Now(),
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(mockArticles[0].ID, mockArticles[0].Title, mockArticles[0].Content,
-			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt).
-		AddRow(mockArticles[1].ID, mockArticles[1].Title, mockArticles[1].Content,
-			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt)
+	rows := sqlmock.NewRows([]string{"id", "fullname", "username", "email", "password", "author_id", "updated_at", "created_at"}).
+		AddRow(mockUsers[0].ID, mockUsers[0].Fullname, mockUsers[0].Username, mockUsers[0].Email,
+			mockUsers[0].Password, 0, mockUsers[0].UpdatedAt, mockUsers[0].CreatedAt).
+		AddRow(mockUsers[1].ID, mockUsers[1].Fullname, mockUsers[1].Username, mockUsers[1].Email,
+			mockUsers[1].Password, 3, mockUsers[1].UpdatedAt, mockUsers[1].CreatedAt)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+	query := "SELECT id, fullname, username, email, password, COALESCE\\(author_id, 0\\), updated_at, created_at FROM user " +
+		"WHERE \\(created_at, id\\) > \\(\\?, \\?\\) ORDER BY created_at ASC, id ASC LIMIT \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
-	a := articleMysqlRepo.NewMysqlArticleRepository(db)
-	cursor := repository.EncodeCursor(mockArticles[1].CreatedAt)
-	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
-	assert.NotEmpty(t, nextCursor)
+	roleRows := sqlmock.NewRows([]string{"user_id", "role"}).
+		AddRow(2, "admin").
+		AddRow(2, "author")
+	mock.ExpectQuery("SELECT user_id, role FROM user_role WHERE user_id IN \\(\\?,\\?\\) ORDER BY role").
+		WithArgs(mockUsers[0].ID, mockUsers[1].ID).WillReturnRows(roleRows)
+	u := userMysqlRepo.NewMysqlUserRepository(db)
+	list, cursors, err := u.Fetch(context.TODO(), domain.Page{Num: 2})
+	assert.Empty(t, cursors.Next)
 	assert.NoError(t, err)
 	assert.Len(t, list, 2)
+	assert.Equal(t, []domain.Role{}, list[0].Roles)
+	assert.Equal(t, []domain.Role{domain.RoleAdmin, domain.RoleAuthor}, list[1].Roles)
+	assert.Equal(t, int64(3), list[1].AuthorID)
 }
 
-func TestGetByID(t *testing.T) {
+func TestGetByEmail(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
-
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE ID = \\?"
-
-	mock.ExpectQuery(query).WillReturnRows(rows)
-	a := articleMysqlRepo.NewMysqlArticleRepository(db)
-
-	num := int64(5)
-	anArticle, err := a.GetByID(context.TODO(), num)
-	assert.NoError(t, err)
-	assert.NotNil(t, anArticle)
+	query := "SELECT id, fullname, username, email, password, COALESCE\\(author_id, 0\\), updated_at, created_at FROM user WHERE email = \\?"
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "fullname", "username", "email", "password", "author_id", "updated_at", "created_at"}).
+			AddRow(1, "Iman Tumorang", "iman", "iman@example.com", "hash", 0, time.Now(), time.Now())
+		mock.ExpectQuery(query).WithArgs("iman@example.com").WillReturnRows(rows)
+		mock.ExpectQuery("SELECT user_id, role FROM user_role").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow(1, "author"))
+		u := userMysqlRepo.NewMysqlUserRepository(db)
+
+		user, err := u.GetByEmail(context.TODO(), "iman@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "iman", user.Username)
+		assert.Equal(t, []domain.Role{domain.RoleAuthor}, user.Roles)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "fullname", "username", "email", "password", "author_id", "updated_at", "created_at"})
+		mock.ExpectQuery(query).WithArgs("nobody@example.com").WillReturnRows(rows)
+		u := userMysqlRepo.NewMysqlUserRepository(db)
+
+		_, err := u.GetByEmail(context.TODO(), "nobody@example.com")
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
 }
 
 func TestStore(t *testing.T) {
 	now := time.Now()
-	ar := &domain.Article{
-		Title:     "Judul",
-		Content:   "Content",
+	user := &domain.User{
+		Fullname:  "Iman Tumorang",
+		Username:  "iman",
+		Email:     "iman@example.com",
+		Password:  "hash",
+		AuthorID:  4,
+		Roles:     []domain.Role{domain.RoleAuthor},
 		CreatedAt: now,
 		UpdatedAt: now,
-		Author: domain.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		},
 	}
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?"
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.CreatedAt, ar.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
-
-	a := articleMysqlRepo.NewMysqlArticleRepository(db)
-
-	err = a.Store(context.TODO(), ar)
-	assert.NoError(t, err)
-	assert.Equal(t, int64(12), ar.ID)
-}
-
-func TestGetByTitle(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
-
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE title = \\?"
-
-	mock.ExpectQuery(query).WillReturnRows(rows)
-	a := articleMysqlRepo.NewMysqlArticleRepository(db)
-
-	title := "title 1"
-	anArticle, err := a.GetByTitle(context.TODO(), title)
-	assert.NoError(t, err)
-	assert.NotNil(t, anArticle)
+	query := "INSERT user SET fullname=\\? , username=\\? , email=\\? , password=\\? , author_id=\\? , updated_at=\\? , created_at=\\?"
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WithArgs(user.Fullname, user.Username, user.Email, user.Password, user.AuthorID, user.UpdatedAt, user.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec("INSERT user_role SET user_id=\\? , role=\\?").WithArgs(7, "author").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		u := userMysqlRepo.NewMysqlUserRepository(db)
+
+		err := u.Store(context.TODO(), user)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), user.ID)
+	})
+
+	t.Run("duplicate-entry", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		mock.ExpectRollback()
+		u := userMysqlRepo.NewMysqlUserRepository(db)
+
+		err := u.Store(context.TODO(), user)
+		assert.Equal(t, errHandle.ErrConflict, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
-func TestDelete(t *testing.T) {
+func TestRemoveRole(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "DELETE FROM article WHERE id = \\?"
-
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+	query := "DELETE FROM user_role WHERE user_id = \\? AND role = \\?"
 
-	a := articleMysqlRepo.NewMysqlArticleRepository(db)
+	t.Run("success", func(t *testing.T) {
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(1, "editor").WillReturnResult(sqlmock.NewResult(0, 1))
+		u := userMysqlRepo.NewMysqlUserRepository(db)
 
-	num := int64(12)
-	err = a.Delete(context.TODO(), num)
-	assert.NoError(t, err)
-}
+		err := u.RemoveRole(context.TODO(), 1, domain.RoleEditor)
+		assert.NoError(t, err)
+	})
 
-func TestUpdate(t *testing.T) {
-	now := time.Now()
-	ar := &domain.Article{
-		ID:        12,
-		Title:     "Judul",
-		Content:   "Content",
-		CreatedAt: now,
-		UpdatedAt: now,
-		Author: domain.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		},
-	}
+	t.Run("not-granted", func(t *testing.T) {
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(1, "editor").WillReturnResult(sqlmock.NewResult(0, 0))
+		u := userMysqlRepo.NewMysqlUserRepository(db)
 
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\? WHERE ID = \\?"
-
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
-
-	a := articleMysqlRepo.NewMysqlArticleRepository(db)
-
-	err = a.Update(context.TODO(), ar)
-	assert.NoError(t, err)
+		err := u.RemoveRole(context.TODO(), 1, domain.RoleEditor)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+	})
 }
@@ -0,0 +1,91 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	userPostgresRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/postgres"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+func TestGetByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "fullname", "username", "email", "password", "author_id", "updated_at", "created_at"}).
+		AddRow(1, "Iman Tumorang", "iman", "iman@example.com", "hash", 2, time.Now(), time.Now())
+	query := "SELECT id, fullname, username, email, password, COALESCE\\(author_id, 0\\), updated_at, created_at FROM \"user\" WHERE email = \\$1"
+	mock.ExpectQuery(query).WithArgs("iman@example.com").WillReturnRows(rows)
+
+	roleRows := sqlmock.NewRows([]string{"user_id", "role"}).AddRow(1, "author")
+	mock.ExpectQuery("SELECT user_id, role FROM user_role WHERE user_id = ANY\\(\\$1\\) ORDER BY role").
+		WithArgs(pq.Array([]int64{1})).WillReturnRows(roleRows)
+
+	u := userPostgresRepo.NewPostgresUserRepository(db)
+	res, err := u.GetByEmail(context.TODO(), "iman@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.AuthorID)
+	assert.Equal(t, []domain.Role{domain.RoleAuthor}, res.Roles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	user := domain.User{
+		Fullname:  "Iman Tumorang",
+		Username:  "iman",
+		Email:     "iman@example.com",
+		Password:  "hash",
+		Roles:     []domain.Role{domain.RoleAuthor},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	query := "INSERT INTO \"user\" \\(fullname, username, email, password, author_id, updated_at, created_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\) RETURNING id"
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(user.Fullname, user.Username, user.Email, user.Password, nil, user.UpdatedAt, user.CreatedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectExec("INSERT INTO user_role \\(user_id, role\\) VALUES \\(\\$1, \\$2\\)").WithArgs(5, domain.RoleAuthor).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		u := userPostgresRepo.NewPostgresUserRepository(db)
+		tempUser := user
+		err = u.Store(context.TODO(), &tempUser)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), tempUser.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate-entry", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint \"user_email\""})
+		mock.ExpectRollback()
+
+		u := userPostgresRepo.NewPostgresUserRepository(db)
+		tempUser := user
+		err = u.Store(context.TODO(), &tempUser)
+		assert.Equal(t, errHandle.ErrConflict, err)
+		assert.Equal(t, int64(0), tempUser.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/pagination"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/transaction"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
+)
+
+type sqliteUserRepository struct {
+	Conn *sql.DB
+}
+
+// NewSqliteUserRepository will create an object that represent the User.Repository interface
+func NewSqliteUserRepository(Conn *sql.DB) domain.UserRepository {
+	return &sqliteUserRepository{Conn}
+}
+
+func (m *sqliteUserRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.User, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.User, 0)
+	for rows.Next() {
+		user := domain.User{}
+		err = rows.Scan(
+			&user.ID,
+			&user.Fullname,
+			&user.Username,
+			&user.Email,
+			&user.Password,
+			&user.AuthorID,
+			&user.UpdatedAt,
+			&user.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, user)
+	}
+
+	return m.fillRoles(ctx, result)
+}
+
+// fillRoles loads the roles of every user in a single query
+func (m *sqliteUserRepository) fillRoles(ctx context.Context, users []domain.User) ([]domain.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+
+	args := make([]interface{}, len(users))
+	index := make(map[int64]int, len(users))
+	for i, user := range users {
+		args[i] = user.ID
+		index[user.ID] = i
+		users[i].Roles = []domain.Role{}
+	}
+	query := `SELECT user_id, role FROM user_role WHERE user_id IN (?` + strings.Repeat(",?", len(users)-1) + `) ORDER BY role`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	for rows.Next() {
+		var userID int64
+		var role domain.Role
+		if err = rows.Scan(&userID, &role); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		i := index[userID]
+		users[i].Roles = append(users[i].Roles, role)
+	}
+
+	return users, nil
+}
+
+func (m *sqliteUserRepository) Fetch(ctx context.Context, page domain.Page) (res []domain.User, cursors domain.Cursors, err error) {
+	keyset, err := pagination.New(page)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE (created_at, id) ` + keyset.Op() + ` (?, ?) ORDER BY ` + keyset.OrderBy("created_at", "id") + ` LIMIT ? `
+
+	res, err = m.fetch(ctx, query, keyset.Args()...)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	n, cursors := keyset.Page(res, func(i int) pagination.Key {
+		return pagination.Key{CreatedAt: res[i].CreatedAt, ID: res[i].ID}
+	})
+	return res[:n], cursors, nil
+}
+
+func (m *sqliteUserRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.User, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, errHandle.ErrNotFound
+	}
+
+	return
+}
+
+func (m *sqliteUserRepository) GetByID(ctx context.Context, id int64) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE id = ?`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *sqliteUserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE email = ?`
+	return m.getOne(ctx, query, email)
+}
+
+func (m *sqliteUserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE username = ?`
+	return m.getOne(ctx, query, username)
+}
+
+func (m *sqliteUserRepository) GetByAuthorID(ctx context.Context, authorID int64) (domain.User, error) {
+	query := `SELECT id, fullname, username, email, password, COALESCE(author_id, 0), updated_at, created_at
+  						FROM user WHERE author_id = ?`
+	return m.getOne(ctx, query, authorID)
+}
+
+// Store inserts the user together with its roles
+func (m *sqliteUserRepository) Store(ctx context.Context, u *domain.User) error {
+	return transaction.WithinTx(ctx, m.Conn, func(ctx context.Context) (err error) {
+		tx := transaction.Conn(ctx, m.Conn)
+
+		var authorID interface{}
+		if u.AuthorID != 0 {
+			authorID = u.AuthorID
+		}
+
+		query := `INSERT INTO user (fullname, username, email, password, author_id, updated_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+		res, err := tx.ExecContext(ctx, query, u.Fullname, u.Username, u.Email, u.Password, authorID, u.UpdatedAt.UTC(), u.CreatedAt.UTC())
+		if transaction.IsUniqueViolation(err) {
+			return errHandle.ErrConflict
+		}
+		if err != nil {
+			return
+		}
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return
+		}
+
+		for _, role := range u.Roles {
+			_, err = tx.ExecContext(ctx, `INSERT INTO user_role (user_id, role) VALUES (?, ?)`, lastID, role)
+			if err != nil {
+				return
+			}
+		}
+
+		u.ID = lastID
+		return
+	})
+}
+
+func (m *sqliteUserRepository) AddRole(ctx context.Context, userID int64, role domain.Role) (err error) {
+	query := `INSERT INTO user_role (user_id, role) VALUES (?, ?)`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, userID, role)
+	if transaction.IsUniqueViolation(err) {
+		return errHandle.ErrConflict
+	}
+	return
+}
+
+func (m *sqliteUserRepository) RemoveRole(ctx context.Context, userID int64, role domain.Role) (err error) {
+	query := `DELETE FROM user_role WHERE user_id = ? AND role = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, role)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return errHandle.ErrNotFound
+	}
+	if affect != 1 {
+		return fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+
+	return
+}
@@ -4,34 +4,156 @@ import (
 	"context"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 type userUsecase struct {
 	userRepo       domain.UserRepository
+	authorRepo     domain.AuthorRepository
+	policy         domain.Policy
+	transactor     domain.Transactor
+	sealer         domain.CursorSealer
 	contextTimeout time.Duration
 }
 
 // NewUserUsecase will create new an UserUsecase object representation of domain.UserUsecase interface
-func NewUserUsecase(a domain.UserRepository, timeout time.Duration) domain.UserUsecase {
+func NewUserUsecase(a domain.UserRepository, ar domain.AuthorRepository, p domain.Policy, t domain.Transactor, s domain.CursorSealer, timeout time.Duration) domain.UserUsecase {
 	return &userUsecase{
 		userRepo:       a,
+		authorRepo:     ar,
+		policy:         p,
+		transactor:     t,
+		sealer:         s,
 		contextTimeout: timeout,
 	}
 }
 
-func (a *userUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.User, nextCursor string, err error) {
-	if num == 0 {
-		num = 10
+func (a *userUsecase) Fetch(c context.Context, page domain.Page) (res []domain.User, cursors domain.Cursors, err error) {
+	if page.Num == 0 {
+		page.Num = 10
 	}
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
-	res, nextCursor, err = a.userRepo.Fetch(ctx, cursor, num)
+	if err = a.policy.CanManageUsers(ctx); err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	opened, err := a.sealer.Open(page, "users", nil)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	res, cursors, err = a.userRepo.Fetch(ctx, opened)
+	if err != nil {
+		return nil, domain.Cursors{}, err
+	}
+
+	return res, a.sealer.Seal(page, "users", nil, cursors), nil
+}
+
+// Store registers the user with the author role and an author profile named
+// after the user. u.Password carries the plain password and is replaced by its
+// bcrypt hash before the user is persisted. The author and the user are stored
+// in a single transaction.
+func (a *userUsecase) Store(c context.Context, u *domain.User) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err = a.userRepo.GetByEmail(ctx, u.Email); err == nil {
+		return errHandle.ErrConflict
+	}
+	if err != errHandle.ErrNotFound {
+		return
+	}
+	if _, err = a.userRepo.GetByUsername(ctx, u.Username); err == nil {
+		return errHandle.ErrConflict
+	}
+	if err != errHandle.ErrNotFound {
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", err
+		return
 	}
+	u.Password = string(hash)
+	u.Roles = []domain.Role{domain.RoleAuthor}
+	u.CreatedAt = time.Now()
+	u.UpdatedAt = u.CreatedAt
 
+	author := domain.Author{
+		Name:      u.Fullname,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if author.Name == "" {
+		author.Name = u.Username
+	}
+	err = a.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := a.authorRepo.Store(ctx, &author); err != nil {
+			return err
+		}
+		u.AuthorID = author.ID
+
+		return a.userRepo.Store(ctx, u)
+	})
+	if err != nil {
+		u.AuthorID = 0
+	}
+	return
+}
+
+func (a *userUsecase) GrantRole(c context.Context, userID int64, role domain.Role) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err = a.policy.CanManageUsers(ctx); err != nil {
+		return
+	}
+	if !role.Valid() {
+		return errHandle.ErrBadParamInput
+	}
+	if _, err = a.userRepo.GetByID(ctx, userID); err != nil {
+		return
+	}
+
+	return a.userRepo.AddRole(ctx, userID, role)
+}
+
+func (a *userUsecase) RevokeRole(c context.Context, userID int64, role domain.Role) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err = a.policy.CanManageUsers(ctx); err != nil {
+		return
+	}
+
+	return a.userRepo.RemoveRole(ctx, userID, role)
+}
+
+// Promote grants the role to the user with the given email on behalf of the
+// operator, without asking the policy, so that a deployment gets its first
+// admin. Granting a role the user already has is not an error.
+func (a *userUsecase) Promote(c context.Context, email string, role domain.Role) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if !role.Valid() {
+		return errHandle.ErrBadParamInput
+	}
+	user, err := a.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return
+	}
+
+	err = a.userRepo.AddRole(ctx, user.ID, role)
+	if err == errHandle.ErrConflict {
+		return nil
+	}
 	return
 }
@@ -8,213 +8,293 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 
+	"github.com/rachadiannovansyah/go-echo-clean-arch/database/memdb"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain"
 	"github.com/rachadiannovansyah/go-echo-clean-arch/domain/mocks"
-	ucase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/article/usecase"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/internal/testutil"
+	authorMemoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/author/repository/memory"
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/policy"
+	userMemoryRepo "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/repository/memory"
+	ucase "github.com/rachadiannovansyah/go-echo-clean-arch/modules/user/usecase"
+	errHandle "github.com/rachadiannovansyah/go-echo-clean-arch/utils"
 )
 
 func TestFetch(t *testing.T) {
-	mockArticleRepo := new(mocks.ArticleRepository)
-	mockArticle := domain.Article{
-		Title:   "Hello",
-		Content: "Content",
+	mockUserRepo := new(mocks.UserRepository)
+	mockUser := domain.User{
+		Fullname: "Iman Tumorang",
+		Username: "iman",
+		Email:    "iman@example.com",
 	}
 
-	mockListArtilce := make([]domain.Article, 0)
-	mockListArtilce = append(mockListArtilce, mockArticle)
+	mockListUser := make([]domain.User, 0)
+	mockListUser = append(mockListUser, mockUser)
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
-		mockAuthor := domain.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		}
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockUserRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.Page")).Return(mockListUser, domain.Cursors{Next: "next-cursor"}, nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanManageUsers", mock.Anything).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
-		cursorExpected := "next-cursor"
-		assert.Equal(t, cursorExpected, nextCursor)
-		assert.NotEmpty(t, nextCursor)
+		list, cursors, err := u.Fetch(context.TODO(), domain.Page{Cursor: cursor, Num: num})
+		assert.Equal(t, "next-cursor", cursors.Next)
 		assert.NoError(t, err)
-		assert.Len(t, list, len(mockListArtilce))
+		assert.Len(t, list, len(mockListUser))
 
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
+		mockUserRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.Page")).Return(nil, domain.Cursors{}, errors.New("Unexpexted Error")).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanManageUsers", mock.Anything).Return(nil).Once()
 
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, cursors, err := u.Fetch(context.TODO(), domain.Page{Cursor: cursor, Num: num})
 
-		assert.Empty(t, nextCursor)
+		assert.Empty(t, cursors)
 		assert.Error(t, err)
 		assert.Len(t, list, 0)
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
 
+	t.Run("not-an-admin", func(t *testing.T) {
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanManageUsers", mock.Anything).Return(errHandle.ErrForbidden).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+		_, _, err := u.Fetch(context.TODO(), domain.Page{Num: 1})
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockPolicy.AssertExpectations(t)
+	})
 }
 
-func TestGetByID(t *testing.T) {
-	mockArticleRepo := new(mocks.ArticleRepository)
-	mockArticle := domain.Article{
-		Title:   "Hello",
-		Content: "Content",
-	}
-	mockAuthor := domain.Author{
-		ID:   1,
-		Name: "Iman Tumorang",
+func TestStore(t *testing.T) {
+	mockUser := domain.User{
+		Fullname: "Iman Tumorang",
+		Username: "iman",
+		Email:    "iman@example.com",
+		Password: "supersecret",
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
-
-		a, err := u.GetByID(context.TODO(), mockArticle.ID)
+		tempMockUser := mockUser
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(domain.User{}, errHandle.ErrNotFound).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mockUser.Username).Return(domain.User{}, errHandle.ErrNotFound).Once()
+		mockUserRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *domain.Author) bool {
+			return a.Name == mockUser.Fullname
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Author).ID = 4
+		}).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockAuthorRepo, new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		err := u.Store(context.TODO(), &tempMockUser)
 
 		assert.NoError(t, err)
-		assert.NotNil(t, a)
-
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		assert.NotEqual(t, mockUser.Password, tempMockUser.Password)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(tempMockUser.Password), []byte(mockUser.Password)))
+		assert.False(t, tempMockUser.CreatedAt.IsZero())
+		assert.Equal(t, []domain.Role{domain.RoleAuthor}, tempMockUser.Roles)
+		assert.Equal(t, int64(4), tempMockUser.AuthorID)
+		mockUserRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertExpectations(t)
 	})
-	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Article{}, errors.New("Unexpected")).Once()
 
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+	t.Run("failed-store-rolls-back-author", func(t *testing.T) {
+		tempMockUser := mockUser
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(domain.User{}, errHandle.ErrNotFound).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mockUser.Username).Return(domain.User{}, errHandle.ErrNotFound).Once()
+		mockUserRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errHandle.ErrConflict).Once()
+		mockAuthorRepo := new(mocks.AuthorRepository)
+		mockAuthorRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Author")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Author).ID = 4
+		}).Return(nil).Once()
+		mockTransactor := testutil.NewTransactor()
+		u := ucase.NewUserUsecase(mockUserRepo, mockAuthorRepo, new(mocks.Policy), mockTransactor, testutil.NewSealer(), time.Second*2)
+
+		err := u.Store(context.TODO(), &tempMockUser)
+
+		assert.Equal(t, errHandle.ErrConflict, err)
+		assert.Zero(t, tempMockUser.AuthorID)
+		mockUserRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertExpectations(t)
+		mockAuthorRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		mockTransactor.AssertNumberOfCalls(t, "WithinTx", 1)
+	})
 
-		a, err := u.GetByID(context.TODO(), mockArticle.ID)
+	t.Run("existing-email", func(t *testing.T) {
+		tempMockUser := mockUser
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(domain.User{ID: 1}, nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 
-		assert.Error(t, err)
-		assert.Equal(t, domain.Article{}, a)
+		err := u.Store(context.TODO(), &tempMockUser)
 
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		assert.Equal(t, errHandle.ErrConflict, err)
+		mockUserRepo.AssertExpectations(t)
 	})
 
-}
+	t.Run("existing-username", func(t *testing.T) {
+		tempMockUser := mockUser
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(domain.User{}, errHandle.ErrNotFound).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mockUser.Username).Return(domain.User{ID: 1}, nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 
-func TestStore(t *testing.T) {
-	mockArticleRepo := new(mocks.ArticleRepository)
-	mockArticle := domain.Article{
-		Title:   "Hello",
-		Content: "Content",
-	}
+		err := u.Store(context.TODO(), &tempMockUser)
 
-	t.Run("success", func(t *testing.T) {
-		tempMockArticle := mockArticle
-		tempMockArticle.ID = 0
-		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
-		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		assert.Equal(t, errHandle.ErrConflict, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
 
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+func TestGrantRole(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, int64(2)).Return(domain.User{ID: 2}, nil).Once()
+		mockUserRepo.On("AddRole", mock.Anything, int64(2), domain.RoleEditor).Return(nil).Once()
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanManageUsers", mock.Anything).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 
-		err := u.Store(context.TODO(), &tempMockArticle)
+		err := u.GrantRole(context.TODO(), 2, domain.RoleEditor)
 
 		assert.NoError(t, err)
-		assert.Equal(t, mockArticle.Title, tempMockArticle.Title)
-		mockArticleRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
-	t.Run("existing-title", func(t *testing.T) {
-		existingArticle := mockArticle
-		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(existingArticle, nil).Once()
-		mockAuthor := domain.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		}
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
 
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+	t.Run("unknown-role", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanManageUsers", mock.Anything).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 
-		err := u.Store(context.TODO(), &mockArticle)
+		err := u.GrantRole(context.TODO(), 2, domain.Role("superuser"))
 
-		assert.Error(t, err)
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		mockUserRepo.AssertExpectations(t)
 	})
 
+	t.Run("not-an-admin", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockPolicy := new(mocks.Policy)
+		mockPolicy.On("CanManageUsers", mock.Anything).Return(errHandle.ErrForbidden).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
+
+		err := u.GrantRole(context.TODO(), 2, domain.RoleAdmin)
+
+		assert.Equal(t, errHandle.ErrForbidden, err)
+		mockUserRepo.AssertExpectations(t)
+	})
 }
 
-func TestDelete(t *testing.T) {
-	mockArticleRepo := new(mocks.ArticleRepository)
-	mockArticle := domain.Article{
-		Title:   "Hello",
-		Content: "Content",
-	}
+func TestRevokeRole(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("RemoveRole", mock.Anything, int64(2), domain.RoleEditor).Return(errHandle.ErrNotFound).Once()
+	mockPolicy := new(mocks.Policy)
+	mockPolicy.On("CanManageUsers", mock.Anything).Return(nil).Once()
+	u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), mockPolicy, testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 
-	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockArticle, nil).Once()
+	err := u.RevokeRole(context.TODO(), 2, domain.RoleEditor)
 
-		mockArticleRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
+	assert.Equal(t, errHandle.ErrNotFound, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestPromote(t *testing.T) {
+	t.Run("bootstrapped-admin-can-publish", func(t *testing.T) {
+		db := memdb.New()
+		userRepo := userMemoryRepo.NewMemoryUserRepository(db)
+		rbac := policy.NewRBACPolicy()
+		u := ucase.NewUserUsecase(userRepo, authorMemoryRepo.NewMemoryAuthorRepository(db), rbac, db, testutil.NewSealer(), time.Second*2)
 
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		user := domain.User{Fullname: "Iman Tumorang", Username: "iman", Email: "iman@example.com", Password: "supersecret"}
+		assert.NoError(t, u.Store(context.TODO(), &user))
+		registered, err := userRepo.GetByEmail(context.TODO(), "iman@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, errHandle.ErrForbidden, rbac.CanPublishArticle(domain.NewContextWithUser(context.TODO(), registered)))
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		assert.NoError(t, u.Promote(context.TODO(), "iman@example.com", domain.RoleAdmin))
+		assert.NoError(t, u.Promote(context.TODO(), "iman@example.com", domain.RoleAdmin), "already an admin")
 
+		admin, err := userRepo.GetByEmail(context.TODO(), "iman@example.com")
 		assert.NoError(t, err)
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		assert.ElementsMatch(t, []domain.Role{domain.RoleAuthor, domain.RoleAdmin}, admin.Roles)
+		ctx := domain.NewContextWithUser(context.TODO(), admin)
+		assert.NoError(t, rbac.CanPublishArticle(ctx))
+		assert.NoError(t, rbac.CanManageUsers(ctx))
 	})
-	t.Run("article-is-not-exist", func(t *testing.T) {
-		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Article{}, nil).Once()
 
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+	t.Run("unknown-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(domain.User{}, errHandle.ErrNotFound).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Promote(context.TODO(), "nobody@example.com", domain.RoleAdmin)
 
-		assert.Error(t, err)
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		assert.Equal(t, errHandle.ErrNotFound, err)
+		mockUserRepo.AssertExpectations(t)
 	})
-	t.Run("error-happens-in-db", func(t *testing.T) {
-		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.Article{}, errors.New("Unexpected Error")).Once()
 
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+	t.Run("unknown-role", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		u := ucase.NewUserUsecase(mockUserRepo, new(mocks.AuthorRepository), new(mocks.Policy), testutil.NewTransactor(), testutil.NewSealer(), time.Second*2)
 
-		err := u.Delete(context.TODO(), mockArticle.ID)
+		err := u.Promote(context.TODO(), "iman@example.com", domain.Role("superuser"))
 
-		assert.Error(t, err)
-		mockArticleRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		assert.Equal(t, errHandle.ErrBadParamInput, err)
+		mockUserRepo.AssertExpectations(t)
 	})
-
 }
 
-func TestUpdate(t *testing.T) {
-	mockArticleRepo := new(mocks.ArticleRepository)
-	mockArticle := domain.Article{
-		Title:   "Hello",
-		Content: "Content",
-		ID:      23,
-	}
-
-	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Update", mock.Anything, &mockArticle).Once().Return(nil)
+func TestStoreWithMemoryRepositories(t *testing.T) {
+	db := memdb.New()
+	userRepo := userMemoryRepo.NewMemoryUserRepository(db)
+	authorRepo := authorMemoryRepo.NewMemoryAuthorRepository(db)
+	u := ucase.NewUserUsecase(userRepo, authorRepo, new(mocks.Policy), db, testutil.NewSealer(), time.Second*2)
+
+	user := domain.User{Fullname: "Iman Tumorang", Username: "iman", Email: "iman@example.com", Password: "supersecret"}
+	err := u.Store(context.TODO(), &user)
+	assert.NoError(t, err)
+
+	stored, err := userRepo.GetByUsername(context.TODO(), "iman")
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Role{domain.RoleAuthor}, stored.Roles)
+	author, err := authorRepo.GetByID(context.TODO(), stored.AuthorID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Iman Tumorang", author.Name)
+
+	again := domain.User{Username: "iman", Email: "another@example.com", Password: "supersecret"}
+	err = u.Store(context.TODO(), &again)
+	assert.Equal(t, errHandle.ErrConflict, err)
+	authors, _, err := authorRepo.Fetch(context.TODO(), domain.Page{Num: 10})
+	assert.NoError(t, err)
+	assert.Len(t, authors, 1, "the author of a rejected user is not kept")
+
+	failing := ucase.NewUserUsecase(failingUserRepository{userRepo}, authorRepo, new(mocks.Policy), db, testutil.NewSealer(), time.Second*2)
+	other := domain.User{Fullname: "Someone Else", Username: "else", Email: "else@example.com", Password: "supersecret"}
+	err = failing.Store(context.TODO(), &other)
+	assert.Error(t, err)
+	authors, _, err = authorRepo.Fetch(context.TODO(), domain.Page{Num: 10})
+	assert.NoError(t, err)
+	assert.Len(t, authors, 1, "the author stored in a rolled back transaction is not kept")
+}
 
-		mockAuthorrepo := new(mocks.AuthorRepository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+// failingUserRepository fails every Store after the checks of the usecase pass
+type failingUserRepository struct {
+	domain.UserRepository
+}
 
-		err := u.Update(context.TODO(), &mockArticle)
-		assert.NoError(t, err)
-		mockArticleRepo.AssertExpectations(t)
-	})
+func (failingUserRepository) Store(ctx context.Context, u *domain.User) error {
+	return errors.New("disk full")
 }
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"reflect"
+
+	ut "github.com/go-playground/universal-translator"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// indonesian holds the messages of the validate tags found on the requests.
+// The tags bounding a size tell apart strings, numbers and lists.
+var indonesian = map[string]string{
+	"required":   "{0} wajib diisi",
+	"email":      "{0} harus berupa alamat email yang valid",
+	"alphanum":   "{0} hanya boleh berisi huruf dan angka",
+	"min-string": "panjang {0} minimal {1} karakter",
+	"min-number": "{0} minimal {1}",
+	"min-items":  "{0} minimal berisi {1} item",
+	"max-string": "panjang {0} maksimal {1} karakter",
+	"max-number": "{0} maksimal {1}",
+	"max-items":  "{0} maksimal berisi {1} item",
+	"len-string": "panjang {0} harus {1} karakter",
+	"len-number": "{0} harus {1}",
+	"len-items":  "{0} harus berisi {1} item",
+}
+
+// sized are the tags whose message depends on the kind of the field
+var sized = map[string]bool{"min": true, "max": true, "len": true}
+
+// registerIndonesian adds the Indonesian messages to trans and has v
+// translate its errors with them
+func registerIndonesian(v *validator.Validate, trans ut.Translator) error {
+	for key, text := range indonesian {
+		if err := trans.Add(key, text, false); err != nil {
+			return err
+		}
+	}
+
+	added := func(ut.Translator) error { return nil }
+	for _, tag := range []string{"required", "email", "alphanum", "min", "max", "len"} {
+		if err := v.RegisterTranslation(tag, trans, added, translate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func translate(trans ut.Translator, fe validator.FieldError) string {
+	key := fe.Tag()
+	if sized[key] {
+		switch fe.Kind() {
+		case reflect.String:
+			key += "-string"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			key += "-items"
+		default:
+			key += "-number"
+		}
+	}
+
+	msg, err := trans.T(key, fe.Field(), fe.Param())
+	if err != nil {
+		return ""
+	}
+	return msg
+}
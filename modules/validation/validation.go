@@ -0,0 +1,182 @@
+// Package validation checks the bodies of requests along their validate tags
+// and reports the invalid fields as an RFC 7807 problem, in English or in
+// Indonesian as asked by the Accept-Language of the request.
+package validation
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/labstack/echo"
+	"golang.org/x/text/language"
+	validator "gopkg.in/go-playground/validator.v9"
+	enTranslations "gopkg.in/go-playground/validator.v9/translations/en"
+)
+
+const (
+	// MIMEApplicationProblemJSON is the media type of an RFC 7807 problem
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+// FieldError tells what is wrong with one field of a request
+type FieldError struct {
+	// Field is the path of the field in the JSON body, e.g. "author.id"
+	Field string `json:"field"`
+	// Code is the validate tag the field failed, e.g. "required"
+	Code string `json:"code"`
+	// Message tells it to a human, in the language of the request
+	Message string `json:"message"`
+}
+
+// Problem is the RFC 7807 problem detail of a request failing validation
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail"`
+	Errors []FieldError `json:"errors"`
+}
+
+// texts are the parts of a problem not given by the validate tags
+var texts = map[string]map[string]string{
+	"en": {
+		"problem-title":   "Bad Request",
+		"problem-detail":  "Some fields of the request are not valid.",
+		"problem-invalid": "{0} is not valid",
+	},
+	"id": {
+		"problem-title":   "Permintaan Tidak Valid",
+		"problem-detail":  "Beberapa isian permintaan tidak valid.",
+		"problem-invalid": "{0} tidak valid",
+	},
+}
+
+var (
+	validate   = validator.New()
+	translator = ut.New(en.New(), en.New(), id.New())
+)
+
+func init() {
+	validate.RegisterTagNameFunc(jsonName)
+
+	english, _ := translator.GetTranslator("en")
+	if err := enTranslations.RegisterDefaultTranslations(validate, english); err != nil {
+		panic(err)
+	}
+	indonesian, _ := translator.GetTranslator("id")
+	if err := registerIndonesian(validate, indonesian); err != nil {
+		panic(err)
+	}
+
+	for locale, keys := range texts {
+		trans, _ := translator.GetTranslator(locale)
+		for key, text := range keys {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// jsonName names the fields after their JSON key, as clients know them
+func jsonName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// Struct validates the fields of s along their validate tags, giving the
+// validator.ValidationErrors of the invalid ones
+func Struct(s interface{}) error {
+	return validate.Struct(s)
+}
+
+// Respond answers the request with err, as given by Struct, as a 400
+// problem+json listing the invalid fields in the language of the request.
+func Respond(c echo.Context, err error) error {
+	trans := negotiate(c.Request().Header.Get(headerAcceptLanguage))
+
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  text(trans, "problem-title"),
+		Status: http.StatusBadRequest,
+		Detail: text(trans, "problem-detail"),
+		Errors: []FieldError{},
+	}
+	fieldErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		problem.Detail = err.Error()
+	}
+	for _, fe := range fieldErrs {
+		problem.Errors = append(problem.Errors, FieldError{
+			Field:   fieldPath(fe),
+			Code:    fe.Tag(),
+			Message: message(trans, fe),
+		})
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	header.Set(headerContentLanguage, trans.Locale())
+	header.Add(echo.HeaderVary, headerAcceptLanguage)
+	return c.JSON(http.StatusBadRequest, problem)
+}
+
+// maxAcceptLanguage bounds the Accept-Language header worth parsing, far
+// above what browsers send
+const maxAcceptLanguage = 256
+
+// negotiate picks the translator of the language the client prefers among
+// the ones of the Accept-Language header, English when none is known or the
+// header is longer than maxAcceptLanguage.
+func negotiate(acceptLanguage string) ut.Translator {
+	if len(acceptLanguage) > maxAcceptLanguage {
+		acceptLanguage = ""
+	}
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	locales := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		locales = append(locales, base.String())
+	}
+
+	trans, _ := translator.FindTranslator(locales...)
+	return trans
+}
+
+// fieldPath is the namespace of the field without the name of the struct
+// validated, "Article.author.id" giving "author.id"
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+// message translates fe, telling the field is not valid when its tag has no
+// translation, for which the validator gives back its raw error
+func message(trans ut.Translator, fe validator.FieldError) string {
+	msg := fe.Translate(trans)
+	if raw, ok := fe.(error); msg == "" || ok && msg == raw.Error() {
+		return text(trans, "problem-invalid", fe.Field())
+	}
+	return msg
+}
+
+func text(trans ut.Translator, key string, params ...string) string {
+	s, _ := trans.T(key, params...)
+	return s
+}
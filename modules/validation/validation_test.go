@@ -0,0 +1,131 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rachadiannovansyah/go-echo-clean-arch/modules/validation"
+)
+
+type author struct {
+	ID int64 `json:"id" validate:"min=1"`
+}
+
+type request struct {
+	Username string   `json:"username" validate:"required,alphanum,max=5"`
+	Email    string   `json:"email" validate:"required,email"`
+	Password string   `json:"password" validate:"min=8"`
+	Tags     []string `json:"tags" validate:"max=1"`
+	Code     string   `json:"code" validate:"omitempty,uuid4"`
+	Author   author   `json:"author"`
+	Internal string   `json:"-" validate:"required"`
+}
+
+// respond validates req and answers as a handler would, to the given
+// Accept-Language
+func respond(t *testing.T, req request, acceptLanguage string) (*httptest.ResponseRecorder, validation.Problem) {
+	e := echo.New()
+	httpReq := httptest.NewRequest(echo.POST, "/", nil)
+	if acceptLanguage != "" {
+		httpReq.Header.Set("Accept-Language", acceptLanguage)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+
+	err := validation.Struct(&req)
+	require.Error(t, err)
+	require.NoError(t, validation.Respond(c, err))
+
+	var problem validation.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	return rec, problem
+}
+
+func messages(problem validation.Problem) map[string]string {
+	res := map[string]string{}
+	for _, fe := range problem.Errors {
+		res[fe.Field+" "+fe.Code] = fe.Message
+	}
+	return res
+}
+
+func TestStruct(t *testing.T) {
+	valid := request{Username: "iman", Email: "iman@example.com", Password: "supersecret", Author: author{ID: 1}, Internal: "x"}
+	assert.NoError(t, validation.Struct(&valid))
+}
+
+func TestRespondEnglish(t *testing.T) {
+	req := request{Username: "iman_t", Password: "short", Tags: []string{"a", "b"}, Code: "nope"}
+	rec, problem := respond(t, req, "")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, validation.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "en", rec.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", rec.Header().Get(echo.HeaderVary))
+
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Bad Request", problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, map[string]string{
+		"username alphanum": "username can only contain alphanumeric characters",
+		"email required":    "email is a required field",
+		"password min":      "password must be at least 8 characters in length",
+		"tags max":          "tags must contain at maximum 1 item",
+		"code uuid4":        "code must be a valid version 4 UUID",
+		"author.id min":     "id must be 1 or greater",
+		"Internal required": "Internal is a required field",
+	}, messages(problem))
+}
+
+func TestRespondIndonesian(t *testing.T) {
+	req := request{Username: "imantumorang", Email: "iman", Password: "short", Tags: []string{"a", "b"}, Code: "nope", Internal: "x"}
+	rec, problem := respond(t, req, "id-ID,id;q=0.9,en;q=0.8")
+
+	assert.Equal(t, "id", rec.Header().Get("Content-Language"))
+	assert.Equal(t, "Permintaan Tidak Valid", problem.Title)
+	assert.Equal(t, map[string]string{
+		"username max":  "panjang username maksimal 5 karakter",
+		"email email":   "email harus berupa alamat email yang valid",
+		"password min":  "panjang password minimal 8 karakter",
+		"tags max":      "tags maksimal berisi 1 item",
+		"code uuid4":    "code tidak valid",
+		"author.id min": "id minimal 1",
+	}, messages(problem))
+}
+
+func TestRespondNegotiation(t *testing.T) {
+	for acceptLanguage, want := range map[string]string{
+		"":                                      "en",
+		"id":                                    "id",
+		"en-US,en;q=0.9,id;q=0.8":               "en",
+		"fr, id;q=0.8, en;q=0.5":                "id",
+		"ja, fr;q=0.5":                          "en",
+		"not a language header":                 "en",
+		strings.Repeat("id;q=0.9, ", 30) + "id": "en",
+	} {
+		rec, _ := respond(t, request{}, acceptLanguage)
+		assert.Equal(t, want, rec.Header().Get("Content-Language"), acceptLanguage)
+	}
+}
+
+func TestRespondOtherError(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(echo.POST, "/", nil), rec)
+
+	require.NoError(t, validation.Respond(c, errors.New("not a struct")))
+
+	var problem validation.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "not a struct", problem.Detail)
+	assert.Empty(t, problem.Errors)
+}
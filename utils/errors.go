@@ -11,4 +11,12 @@ var (
 	ErrConflict = errors.New("Your Item already exist")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrBadParamInput = errors.New("Given Param is not valid")
+	// ErrPreconditionFailed will throw if the item was modified since the version the client based its change on
+	ErrPreconditionFailed = errors.New("Your Item has been modified by someone else")
+	// ErrInvalidTransition will throw if the item is not in a state the action applies to
+	ErrInvalidTransition = errors.New("Your Item is not in a state allowing this action")
+	// ErrUnauthorized will throw if the given credentials or token are not valid
+	ErrUnauthorized = errors.New("Your credentials are not valid")
+	// ErrForbidden will throw if the caller is not allowed to perform the action
+	ErrForbidden = errors.New("You are not allowed to perform this action")
 )